@@ -0,0 +1,310 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/collector/model/pdata"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+)
+
+// Convert converts entries to OTLP logs. Entries with the same resource
+// are grouped into one ResourceLogs, and entries with the same scope name
+// into one ScopeLogs, in the order in which they first appear.
+func Convert(entries []*entry.Entry) pdata.Logs {
+	logs := pdata.NewLogs()
+	resourceLogs := logs.ResourceLogs()
+
+	resourceIndex := make(map[string]int)
+	scopeIndex := make(map[string]map[string]int)
+	for _, e := range entries {
+		resourceID := resourceKey(e.Resource)
+		ri, ok := resourceIndex[resourceID]
+		if !ok {
+			ri = resourceLogs.Len()
+			resourceIndex[resourceID] = ri
+			scopeIndex[resourceID] = make(map[string]int)
+			setMap(resourceLogs.AppendEmpty().Resource().Attributes(), e.Resource)
+		}
+
+		scopeLogs := resourceLogs.At(ri).ScopeLogs()
+		si, ok := scopeIndex[resourceID][e.ScopeName]
+		if !ok {
+			si = scopeLogs.Len()
+			scopeIndex[resourceID][e.ScopeName] = si
+			scopeLogs.AppendEmpty().Scope().SetName(e.ScopeName)
+		}
+
+		ConvertRecord(e, scopeLogs.At(si).LogRecords().AppendEmpty())
+	}
+	return logs
+}
+
+// ConvertRecord copies an entry to a log record. The resource and scope
+// name of the entry are not part of a log record, and are not copied.
+//
+// The conversion is lossy where the log data model has no equivalent of a
+// field or value type. The observed timestamp is not copied, trace and span
+// IDs of the wrong length are dropped, and only the first byte of the trace
+// flags is kept. Values are converted as described by setValue.
+func ConvertRecord(e *entry.Entry, dest pdata.LogRecord) {
+	if !e.Timestamp.IsZero() {
+		dest.SetTimestamp(pdata.NewTimestampFromTime(e.Timestamp))
+	}
+	dest.SetSeverityNumber(toSeverityNumber(e.Severity))
+	dest.SetSeverityText(e.SeverityText)
+	setValue(dest.Body(), e.Body)
+	setMap(dest.Attributes(), e.Attributes)
+
+	if len(e.TraceId) == 16 {
+		var traceID [16]byte
+		copy(traceID[:], e.TraceId)
+		dest.SetTraceID(pdata.NewTraceID(traceID))
+	}
+	if len(e.SpanId) == 8 {
+		var spanID [8]byte
+		copy(spanID[:], e.SpanId)
+		dest.SetSpanID(pdata.NewSpanID(spanID))
+	}
+	if len(e.TraceFlags) > 0 {
+		dest.SetFlags(uint32(e.TraceFlags[0]))
+	}
+}
+
+// ConvertFrom converts OTLP logs to entries.
+func ConvertFrom(logs pdata.Logs) []*entry.Entry {
+	entries := make([]*entry.Entry, 0, logs.LogRecordCount())
+	resourceLogs := logs.ResourceLogs()
+	for i := 0; i < resourceLogs.Len(); i++ {
+		rl := resourceLogs.At(i)
+		scopeLogs := rl.ScopeLogs()
+		for j := 0; j < scopeLogs.Len(); j++ {
+			sl := scopeLogs.At(j)
+			records := sl.LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				entries = append(entries, ConvertFromRecord(rl.Resource(), sl.Scope(), records.At(k)))
+			}
+		}
+	}
+	return entries
+}
+
+// ConvertFromRecord converts a log record, along with its resource and scope, to an entry.
+// Log records do not have an observed timestamp, so the entry is observed when it is converted.
+// Int values are converted to int64, double values to float64, and empty maps to nil.
+func ConvertFromRecord(resource pdata.Resource, scope pdata.InstrumentationScope, record pdata.LogRecord) *entry.Entry {
+	e := entry.New()
+	if record.Timestamp() != 0 {
+		e.Timestamp = record.Timestamp().AsTime()
+	}
+	e.Severity = fromSeverityNumber(record.SeverityNumber())
+	e.SeverityText = record.SeverityText()
+	e.Body = fromValue(record.Body())
+	e.Attributes = fromMap(record.Attributes())
+	e.Resource = fromMap(resource.Attributes())
+	e.ScopeName = scope.Name()
+
+	if traceID := record.TraceID(); !traceID.IsEmpty() {
+		bytes := traceID.Bytes()
+		e.TraceId = bytes[:]
+	}
+	if spanID := record.SpanID(); !spanID.IsEmpty() {
+		bytes := spanID.Bytes()
+		e.SpanId = bytes[:]
+	}
+	if flags := record.Flags(); flags != 0 {
+		e.TraceFlags = []byte{byte(flags)}
+	}
+	return e
+}
+
+// toSeverityNumber relies on entry severities having the same values as OTLP severity numbers
+func toSeverityNumber(severity entry.Severity) pdata.SeverityNumber {
+	if severity < entry.Default || severity > entry.Fatal4 {
+		return pdata.SeverityNumberUNDEFINED
+	}
+	return pdata.SeverityNumber(severity)
+}
+
+func fromSeverityNumber(severity pdata.SeverityNumber) entry.Severity {
+	if severity < pdata.SeverityNumberUNDEFINED || severity > pdata.SeverityNumberFATAL4 {
+		return entry.Default
+	}
+	return entry.Severity(severity)
+}
+
+// resourceKey identifies entries with equal resources. Maps are
+// formatted with sorted keys, so equal resources have equal keys.
+func resourceKey(resource map[string]interface{}) string {
+	if len(resource) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%#v", resource)
+}
+
+// setMap replaces the values of an attribute map with the values of a map
+func setMap(dest pdata.Map, values map[string]interface{}) {
+	dest.Clear()
+	dest.EnsureCapacity(len(values))
+
+	// Keys are sorted, so that equal maps are converted to equal attributes
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		dest.InsertNull(k)
+		value, _ := dest.Get(k)
+		setValue(value, values[k])
+	}
+}
+
+// setValue sets an attribute value to a value of an entry. Integers of every size are
+// set as int values, except unsigned integers above math.MaxInt64, which are set as
+// double values. Times are set as RFC 3339 strings, maps of strings and slices of
+// strings or ints as maps and slices, and values of any other type as strings.
+func setValue(dest pdata.Value, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		pdata.NewValueEmpty().CopyTo(dest)
+	case string:
+		dest.SetStringVal(v)
+	case []byte:
+		dest.SetBytesVal(append([]byte(nil), v...))
+	case bool:
+		dest.SetBoolVal(v)
+	case int:
+		dest.SetIntVal(int64(v))
+	case int8:
+		dest.SetIntVal(int64(v))
+	case int16:
+		dest.SetIntVal(int64(v))
+	case int32:
+		dest.SetIntVal(int64(v))
+	case int64:
+		dest.SetIntVal(v)
+	case uint:
+		setUint(dest, uint64(v))
+	case uint8:
+		dest.SetIntVal(int64(v))
+	case uint16:
+		dest.SetIntVal(int64(v))
+	case uint32:
+		dest.SetIntVal(int64(v))
+	case uint64:
+		setUint(dest, v)
+	case float32:
+		dest.SetDoubleVal(float64(v))
+	case float64:
+		dest.SetDoubleVal(v)
+	case time.Time:
+		dest.SetStringVal(v.Format(time.RFC3339Nano))
+	case map[string]interface{}:
+		pdata.NewValueMap().CopyTo(dest)
+		setMap(dest.MapVal(), v)
+	case map[string]string:
+		values := make(map[string]interface{}, len(v))
+		for k, s := range v {
+			values[k] = s
+		}
+		pdata.NewValueMap().CopyTo(dest)
+		setMap(dest.MapVal(), values)
+	case []interface{}:
+		pdata.NewValueSlice().CopyTo(dest)
+		slice := dest.SliceVal()
+		slice.EnsureCapacity(len(v))
+		for _, item := range v {
+			setValue(slice.AppendEmpty(), item)
+		}
+	case []string:
+		pdata.NewValueSlice().CopyTo(dest)
+		slice := dest.SliceVal()
+		slice.EnsureCapacity(len(v))
+		for _, item := range v {
+			slice.AppendEmpty().SetStringVal(item)
+		}
+	case []int:
+		pdata.NewValueSlice().CopyTo(dest)
+		slice := dest.SliceVal()
+		slice.EnsureCapacity(len(v))
+		for _, item := range v {
+			slice.AppendEmpty().SetIntVal(int64(item))
+		}
+	default:
+		dest.SetStringVal(fmt.Sprintf("%v", v))
+	}
+}
+
+// setUint sets an unsigned integer that may not fit in an int value
+func setUint(dest pdata.Value, v uint64) {
+	if v > math.MaxInt64 {
+		dest.SetDoubleVal(float64(v))
+		return
+	}
+	dest.SetIntVal(int64(v))
+}
+
+// fromMap converts an attribute map to a map, or nil if it is empty
+func fromMap(m pdata.Map) map[string]interface{} {
+	if m.Len() == 0 {
+		return nil
+	}
+
+	values := make(map[string]interface{}, m.Len())
+	m.Range(func(k string, v pdata.Value) bool {
+		values[k] = fromValue(v)
+		return true
+	})
+	return values
+}
+
+// fromValue converts an attribute value to the value of an entry
+func fromValue(v pdata.Value) interface{} {
+	switch v.Type() {
+	case pdata.ValueTypeString:
+		return v.StringVal()
+	case pdata.ValueTypeBool:
+		return v.BoolVal()
+	case pdata.ValueTypeInt:
+		return v.IntVal()
+	case pdata.ValueTypeDouble:
+		return v.DoubleVal()
+	case pdata.ValueTypeBytes:
+		return append([]byte(nil), v.BytesVal()...)
+	case pdata.ValueTypeMap:
+		values := make(map[string]interface{}, v.MapVal().Len())
+		v.MapVal().Range(func(k string, item pdata.Value) bool {
+			values[k] = fromValue(item)
+			return true
+		})
+		return values
+	case pdata.ValueTypeSlice:
+		slice := v.SliceVal()
+		values := make([]interface{}, 0, slice.Len())
+		for i := 0; i < slice.Len(); i++ {
+			values = append(values, fromValue(slice.At(i)))
+		}
+		return values
+	default:
+		return nil
+	}
+}
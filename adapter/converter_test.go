@@ -0,0 +1,331 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+)
+
+func newTestEntry() *entry.Entry {
+	e := entry.New()
+	e.Timestamp = time.Date(2022, time.January, 2, 3, 4, 5, 6, time.UTC)
+	e.Severity = entry.Warn2
+	e.SeverityText = "WARNING"
+	e.Body = map[string]interface{}{
+		"message": "hello",
+		"count":   int64(3),
+		"ratio":   0.5,
+		"ok":      true,
+		"raw":     []byte{0x00, 0xff},
+		"empty":   nil,
+		"nested": map[string]interface{}{
+			"list": []interface{}{"a", int64(1), 2.5, false, nil, []interface{}{"b"}, map[string]interface{}{"c": "d"}},
+		},
+	}
+	e.Attributes = map[string]interface{}{
+		"file.name": "app.log",
+		"tags":      []interface{}{"x", "y"},
+	}
+	e.Resource = map[string]interface{}{
+		"host.name": "server",
+	}
+	e.TraceId = []byte{0x48, 0x01, 0x40, 0xf3, 0xd7, 0x70, 0xa5, 0xae, 0x32, 0xf0, 0xa2, 0x2b, 0x6a, 0x81, 0x2c, 0xff}
+	e.SpanId = []byte{0x32, 0xf0, 0xa2, 0x2b, 0x6a, 0x81, 0x2c, 0xff}
+	e.TraceFlags = []byte{0x01}
+	e.ScopeName = "my.logger"
+	return e
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	e := newTestEntry()
+
+	logs := Convert([]*entry.Entry{e})
+	require.Equal(t, 1, logs.LogRecordCount())
+
+	entries := ConvertFrom(logs)
+	require.Len(t, entries, 1)
+
+	// Log records do not have an observed timestamp
+	entries[0].ObservedTimestamp = e.ObservedTimestamp
+	require.Equal(t, e, entries[0])
+}
+
+func TestConvertRoundTripLossy(t *testing.T) {
+	observed := time.Date(2022, time.January, 2, 3, 4, 6, 0, time.UTC)
+	timestamp := time.Date(2022, time.January, 2, 3, 4, 5, 6, time.FixedZone("UTC+1", 3600))
+
+	cases := []struct {
+		name     string
+		value    interface{}
+		expected interface{}
+	}{
+		{"Int", int(-1), int64(-1)},
+		{"Int8", int8(-8), int64(-8)},
+		{"Int16", int16(-16), int64(-16)},
+		{"Int32", int32(-32), int64(-32)},
+		{"Uint", uint(1), int64(1)},
+		{"Uint8", uint8(8), int64(8)},
+		{"Uint16", uint16(16), int64(16)},
+		{"Uint32", uint32(32), int64(32)},
+		{"Uint64", uint64(64), int64(64)},
+		{"Uint64AboveMaxInt64", uint64(math.MaxUint64), float64(math.MaxUint64)},
+		{"UintAboveMaxInt64", uint(math.MaxInt64) + 1, float64(math.MaxInt64)},
+		{"Float32", float32(0.5), float64(0.5)},
+		{"Time", timestamp, "2022-01-02T03:04:05.000000006+01:00"},
+		{"StringMap", map[string]string{"a": "b"}, map[string]interface{}{"a": "b"}},
+		{"StringSlice", []string{"a", "b"}, []interface{}{"a", "b"}},
+		{"IntSlice", []int{1, 2}, []interface{}{int64(1), int64(2)}},
+		{"Other", struct{ A int }{1}, "{1}"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := entry.New()
+			e.ObservedTimestamp = observed
+			e.Body = tc.value
+			e.Attributes = map[string]interface{}{"value": tc.value}
+
+			entries := ConvertFrom(Convert([]*entry.Entry{e}))
+			require.Len(t, entries, 1)
+			require.Equal(t, tc.expected, entries[0].Body)
+			require.Equal(t, tc.expected, entries[0].Attributes["value"])
+		})
+	}
+
+	t.Run("ObservedTimestamp", func(t *testing.T) {
+		e := entry.New()
+		e.ObservedTimestamp = observed
+
+		before := time.Now()
+		entries := ConvertFrom(Convert([]*entry.Entry{e}))
+		require.Len(t, entries, 1)
+		require.False(t, entries[0].ObservedTimestamp.Before(before), "the entry is observed when it is converted back")
+	})
+
+	t.Run("TraceContext", func(t *testing.T) {
+		e := entry.New()
+		e.TraceId = []byte{0x01, 0x02}
+		e.SpanId = []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09}
+		e.TraceFlags = []byte{0x01, 0x02}
+
+		entries := ConvertFrom(Convert([]*entry.Entry{e}))
+		require.Len(t, entries, 1)
+		require.Nil(t, entries[0].TraceId)
+		require.Nil(t, entries[0].SpanId)
+		require.Equal(t, []byte{0x01}, entries[0].TraceFlags)
+	})
+
+	t.Run("ZeroTraceFlags", func(t *testing.T) {
+		e := entry.New()
+		e.TraceFlags = []byte{0x00}
+
+		entries := ConvertFrom(Convert([]*entry.Entry{e}))
+		require.Len(t, entries, 1)
+		require.Nil(t, entries[0].TraceFlags)
+	})
+
+	t.Run("EmptyMaps", func(t *testing.T) {
+		e := entry.New()
+		e.Attributes = map[string]interface{}{}
+		e.Resource = map[string]interface{}{}
+
+		entries := ConvertFrom(Convert([]*entry.Entry{e}))
+		require.Len(t, entries, 1)
+		require.Nil(t, entries[0].Attributes)
+		require.Nil(t, entries[0].Resource)
+	})
+}
+
+func TestConvertRecord(t *testing.T) {
+	e := newTestEntry()
+	record := pdata.NewLogRecord()
+	ConvertRecord(e, record)
+
+	require.Equal(t, e.Timestamp, record.Timestamp().AsTime())
+	require.Equal(t, pdata.SeverityNumberWARN2, record.SeverityNumber())
+	require.Equal(t, "WARNING", record.SeverityText())
+	require.Equal(t, pdata.NewTraceID([16]byte{0x48, 0x01, 0x40, 0xf3, 0xd7, 0x70, 0xa5, 0xae, 0x32, 0xf0, 0xa2, 0x2b, 0x6a, 0x81, 0x2c, 0xff}), record.TraceID())
+	require.Equal(t, pdata.NewSpanID([8]byte{0x32, 0xf0, 0xa2, 0x2b, 0x6a, 0x81, 0x2c, 0xff}), record.SpanID())
+	require.Equal(t, uint32(1), record.Flags())
+
+	require.Equal(t, pdata.ValueTypeMap, record.Body().Type())
+	message, ok := record.Body().MapVal().Get("message")
+	require.True(t, ok)
+	require.Equal(t, "hello", message.StringVal())
+	raw, ok := record.Body().MapVal().Get("raw")
+	require.True(t, ok)
+	require.Equal(t, pdata.ValueTypeBytes, raw.Type())
+	empty, ok := record.Body().MapVal().Get("empty")
+	require.True(t, ok)
+	require.Equal(t, pdata.ValueTypeEmpty, empty.Type())
+
+	fileName, ok := record.Attributes().Get("file.name")
+	require.True(t, ok)
+	require.Equal(t, "app.log", fileName.StringVal())
+}
+
+func TestConvertEmptyEntry(t *testing.T) {
+	e := entry.New()
+	logs := Convert([]*entry.Entry{e})
+	record := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+
+	require.Equal(t, pdata.Timestamp(0), record.Timestamp())
+	require.Equal(t, pdata.SeverityNumberUNDEFINED, record.SeverityNumber())
+	require.Equal(t, pdata.ValueTypeEmpty, record.Body().Type())
+	require.Equal(t, 0, record.Attributes().Len())
+	require.True(t, record.TraceID().IsEmpty())
+	require.True(t, record.SpanID().IsEmpty())
+	require.Equal(t, uint32(0), record.Flags())
+
+	converted := ConvertFrom(logs)[0]
+	converted.ObservedTimestamp = e.ObservedTimestamp
+	require.Equal(t, e, converted)
+}
+
+func TestConvertGroupsResourcesAndScopes(t *testing.T) {
+	newEntry := func(host, scope, body string) *entry.Entry {
+		e := entry.New()
+		e.Resource = map[string]interface{}{"host.name": host}
+		e.ScopeName = scope
+		e.Body = body
+		return e
+	}
+
+	entries := []*entry.Entry{
+		newEntry("a", "x", "1"),
+		newEntry("b", "x", "2"),
+		newEntry("a", "y", "3"),
+		newEntry("a", "x", "4"),
+		newEntry("b", "x", "5"),
+	}
+	logs := Convert(entries)
+	require.Equal(t, 5, logs.LogRecordCount())
+
+	resourceLogs := logs.ResourceLogs()
+	require.Equal(t, 2, resourceLogs.Len())
+
+	hostA, _ := resourceLogs.At(0).Resource().Attributes().Get("host.name")
+	require.Equal(t, "a", hostA.StringVal())
+	scopesA := resourceLogs.At(0).ScopeLogs()
+	require.Equal(t, 2, scopesA.Len())
+	require.Equal(t, "x", scopesA.At(0).Scope().Name())
+	require.Equal(t, 2, scopesA.At(0).LogRecords().Len())
+	require.Equal(t, "1", scopesA.At(0).LogRecords().At(0).Body().StringVal())
+	require.Equal(t, "4", scopesA.At(0).LogRecords().At(1).Body().StringVal())
+	require.Equal(t, "y", scopesA.At(1).Scope().Name())
+	require.Equal(t, 1, scopesA.At(1).LogRecords().Len())
+
+	hostB, _ := resourceLogs.At(1).Resource().Attributes().Get("host.name")
+	require.Equal(t, "b", hostB.StringVal())
+	require.Equal(t, 1, resourceLogs.At(1).ScopeLogs().Len())
+	require.Equal(t, 2, resourceLogs.At(1).ScopeLogs().At(0).LogRecords().Len())
+
+	converted := ConvertFrom(logs)
+	bodies := make([]interface{}, 0, len(converted))
+	for _, e := range converted {
+		bodies = append(bodies, e.Body)
+	}
+	require.Equal(t, []interface{}{"1", "4", "3", "2", "5"}, bodies)
+}
+
+func TestConvertValues(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    interface{}
+		expected interface{}
+	}{
+		{"String", "str", "str"},
+		{"Bool", true, true},
+		{"Int", 1, int64(1)},
+		{"Int8", int8(-8), int64(-8)},
+		{"Int16", int16(16), int64(16)},
+		{"Int32", int32(32), int64(32)},
+		{"Uint", uint(1), int64(1)},
+		{"Uint8", uint8(8), int64(8)},
+		{"Uint16", uint16(16), int64(16)},
+		{"Uint32", uint32(32), int64(32)},
+		{"Uint64", uint64(64), int64(64)},
+		{"Uint64Overflow", uint64(math.MaxUint64), float64(math.MaxUint64)},
+		{"Float32", float32(1.5), 1.5},
+		{"Bytes", []byte("bytes"), []byte("bytes")},
+		{"Time", time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC), "2022-01-01T00:00:00Z"},
+		{"StringMap", map[string]string{"a": "b"}, map[string]interface{}{"a": "b"}},
+		{"StringSlice", []string{"a", "b"}, []interface{}{"a", "b"}},
+		{"EmptyMap", map[string]interface{}{}, map[string]interface{}{}},
+		{"EmptySlice", []interface{}{}, []interface{}{}},
+		{"Other", struct{ A int }{1}, "{1}"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			value := pdata.NewValueEmpty()
+			setValue(value, tc.value)
+			require.Equal(t, tc.expected, fromValue(value))
+		})
+	}
+}
+
+func TestConvertSeverity(t *testing.T) {
+	for severity := entry.Default; severity <= entry.Fatal4; severity++ {
+		number := toSeverityNumber(severity)
+		require.Equal(t, int32(severity), int32(number))
+		require.Equal(t, severity, fromSeverityNumber(number))
+	}
+
+	require.Equal(t, pdata.SeverityNumberUNDEFINED, toSeverityNumber(entry.Severity(100)))
+	require.Equal(t, pdata.SeverityNumberUNDEFINED, toSeverityNumber(entry.Severity(-1)))
+	require.Equal(t, entry.Default, fromSeverityNumber(pdata.SeverityNumber(100)))
+}
+
+func TestConvertFromRecord(t *testing.T) {
+	resource := pdata.NewResource()
+	resource.Attributes().InsertString("service.name", "checkout")
+	scope := pdata.NewInstrumentationScope()
+	scope.SetName("checkout.logger")
+
+	record := pdata.NewLogRecord()
+	record.SetTimestamp(pdata.NewTimestampFromTime(time.Unix(1136214245, 0)))
+	record.SetSeverityNumber(pdata.SeverityNumberERROR)
+	record.SetSeverityText("E")
+	record.Body().SetStringVal("failed")
+	record.Attributes().InsertInt("attempt", 2)
+	record.SetFlags(1)
+
+	e := ConvertFromRecord(resource, scope, record)
+	require.Equal(t, time.Unix(1136214245, 0).UTC(), e.Timestamp)
+	require.Equal(t, entry.Error, e.Severity)
+	require.Equal(t, "E", e.SeverityText)
+	require.Equal(t, "failed", e.Body)
+	require.Equal(t, map[string]interface{}{"attempt": int64(2)}, e.Attributes)
+	require.Equal(t, map[string]interface{}{"service.name": "checkout"}, e.Resource)
+	require.Equal(t, "checkout.logger", e.ScopeName)
+	require.Nil(t, e.TraceId)
+	require.Nil(t, e.SpanId)
+	require.Equal(t, []byte{0x01}, e.TraceFlags)
+
+	// Converting the entry back produces an equal record
+	logs := Convert([]*entry.Entry{e})
+	converted := logs.ResourceLogs().At(0)
+	require.Equal(t, resource, converted.Resource())
+	require.Equal(t, scope, converted.ScopeLogs().At(0).Scope())
+	require.Equal(t, record, converted.ScopeLogs().At(0).LogRecords().At(0))
+}
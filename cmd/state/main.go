@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command state inspects and resets the operator state stored by a file persister.
+//
+// Usage:
+//
+//	state --directory DIR list
+//	state --directory DIR inspect SCOPE
+//	state --directory DIR reset SCOPE
+//
+// The scope of an operator's state is the operator's ID.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/persister"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// record is the representation of a key's state printed by inspect
+type record struct {
+	Key        string `json:"key"`
+	Type       string `json:"type,omitempty"`
+	Version    int    `json:"version"`
+	Data       string `json:"data,omitempty"`
+	DataBase64 []byte `json:"data_base64,omitempty"`
+}
+
+// run executes the command and returns its exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("state", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	directory := flags.String("directory", "", "directory of the file persister")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: state --directory DIR list | inspect SCOPE | reset SCOPE")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *directory == "" {
+		fmt.Fprintln(stderr, "the --directory flag is required")
+		flags.Usage()
+		return 2
+	}
+
+	command, commandArgs := flags.Arg(0), flags.Args()
+	switch {
+	case command == "list" && len(commandArgs) == 1:
+	case (command == "inspect" || command == "reset") && len(commandArgs) == 2:
+	default:
+		flags.Usage()
+		return 2
+	}
+
+	cfg := persister.NewFileConfig(*directory)
+	p, err := cfg.Build(newLogger(stderr))
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to open state: %s\n", err)
+		return 1
+	}
+	defer p.Close()
+
+	ctx := context.Background()
+	switch command {
+	case "list":
+		err = list(ctx, p, stdout)
+	case "inspect":
+		err = inspect(ctx, p, commandArgs[1], stdout)
+	case "reset":
+		err = reset(ctx, p, commandArgs[1], stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to %s state: %s\n", command, err)
+		return 1
+	}
+	return 0
+}
+
+// list prints the scopes that hold state, and the number of keys in each
+func list(ctx context.Context, p operator.Persister, w io.Writer) error {
+	scopes, err := operator.StateScopes(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	for _, scope := range scopes {
+		records, err := operator.InspectState(ctx, p, scope)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\t%d\n", scope, len(records))
+	}
+	return nil
+}
+
+// inspect prints the state of each key in a scope as a line of JSON
+func inspect(ctx context.Context, p operator.Persister, scope string, w io.Writer) error {
+	records, err := operator.InspectState(ctx, p, scope)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	for _, r := range records {
+		printed := record{
+			Key:     r.Key,
+			Type:    r.Type,
+			Version: r.Version,
+		}
+		if utf8.Valid(r.Data) {
+			printed.Data = string(r.Data)
+		} else {
+			printed.DataBase64 = r.Data
+		}
+		if err := encoder.Encode(printed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reset deletes the state of a scope
+func reset(ctx context.Context, p operator.Persister, scope string, w io.Writer) error {
+	deleted, err := operator.ResetState(ctx, p, scope)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "deleted %d keys from %s\n", deleted, scope)
+	return nil
+}
+
+func newLogger(w io.Writer) *zap.SugaredLogger {
+	encoder := zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	core := zapcore.NewCore(encoder, zapcore.AddSync(w), zapcore.WarnLevel)
+	return zap.New(core).Sugar()
+}
@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/persister"
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
+)
+
+func newStateDirectory(t *testing.T) string {
+	dir := t.TempDir()
+	p, err := persister.NewFileConfig(dir).Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	fileInput := operator.NewVersionedPersister("file_input", operator.NewScopedPersister("file_input", p), operator.DefaultStateMigrations, testutil.Logger(t))
+	require.NoError(t, fileInput.Set(ctx, "knownFiles", []byte(`{"Offset":10}`)))
+	journald := operator.NewScopedPersister("journald_input", p)
+	require.NoError(t, journald.Set(ctx, "lastReadCursor", []byte{0xff, 0xfe}))
+	require.NoError(t, p.Close())
+	return dir
+}
+
+func TestRunList(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--directory", newStateDirectory(t), "list"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	require.Equal(t, "file_input\t1\njournald_input\t1\n", stdout.String())
+}
+
+func TestRunInspect(t *testing.T) {
+	dir := newStateDirectory(t)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--directory", dir, "inspect", "file_input"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	require.Equal(t, `{"key":"knownFiles","type":"file_input","version":0,"data":"{\"Offset\":10}"}`+"\n", stdout.String())
+
+	stdout.Reset()
+	code = run([]string{"--directory", dir, "inspect", "journald_input"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	require.Equal(t, `{"key":"lastReadCursor","version":0,"data_base64":"//4="}`+"\n", stdout.String())
+}
+
+func TestRunReset(t *testing.T) {
+	dir := newStateDirectory(t)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--directory", dir, "reset", "file_input"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	require.Equal(t, "deleted 1 keys from file_input\n", stdout.String())
+
+	stdout.Reset()
+	code = run([]string{"--directory", dir, "list"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	require.Equal(t, "journald_input\t1\n", stdout.String())
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 2, run([]string{"list"}, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "the --directory flag is required")
+
+	dir := t.TempDir()
+	require.Equal(t, 2, run([]string{"--directory", dir}, &stdout, &stderr))
+	require.Equal(t, 2, run([]string{"--directory", dir, "inspect"}, &stdout, &stderr))
+	require.Equal(t, 2, run([]string{"--directory", dir, "unknown"}, &stdout, &stderr))
+	require.Equal(t, 2, run([]string{"--unknown"}, &stdout, &stderr))
+}
@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command validate checks a pipeline configuration, and optionally
+// previews the entries it produces from a sample of log lines.
+//
+// Usage:
+//
+//	validate --config pipeline.yaml [--sample lines.log]
+//	validate --schema
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns its exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := flags.String("config", "", "path to the pipeline configuration file")
+	samplePath := flags.String("sample", "", "path to a file of log lines to send through the pipeline")
+	printSchema := flags.Bool("schema", false, "print the JSON Schema of operator configs")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *printSchema {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(operator.JSONSchema()); err != nil {
+			fmt.Fprintf(stderr, "failed to encode schema: %s\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if *configPath == "" {
+		fmt.Fprintln(stderr, "the --config flag is required")
+		flags.Usage()
+		return 2
+	}
+
+	logger := newLogger(stderr)
+	cfg, errs := loadConfig(*configPath, logger)
+	if len(errs) > 0 {
+		reportErrors(stderr, errs)
+		return 1
+	}
+
+	pipe, err := cfg.Build(logger)
+	if err != nil {
+		reportErrors(stderr, []configError{{err: err}})
+		return 1
+	}
+
+	graph, err := pipe.Render()
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to render pipeline: %s\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s\n", graph)
+
+	if *samplePath == "" {
+		return 0
+	}
+
+	if err := runSample(cfg, *samplePath, logger, stdout); err != nil {
+		reportErrors(stderr, []configError{{err: err}})
+		return 1
+	}
+	return 0
+}
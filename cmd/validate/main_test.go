@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunValid(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--config", "testdata/valid.yaml"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+	require.Contains(t, stdout.String(), "file_input -> regex_parser;")
+	require.Contains(t, stdout.String(), "regex_parser -> filter;")
+	require.Contains(t, stdout.String(), "filter -> stdout;")
+}
+
+func TestRunReportsAllErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--config", "testdata/invalid.yaml"}, &stdout, &stderr)
+	require.Equal(t, 1, code)
+	require.Empty(t, stdout.String())
+
+	expected := []string{
+		"error: operator 1 (file_input): required argument `include` is empty",
+		"error: operator 2: unsupported type 'unknown_operator'",
+		"error: operator 3 (regex_parser): compiling regex",
+		"error: operator 4 (json_parser): operator config has an invalid `on_error` field.",
+		"  suggestion: ensure that the `on_error` field is set to either `send`, `drop` or `dead_letter`.",
+		"  details: on_error=invalid",
+		"error: operator 5 (stdout): operator config contains unknown fields",
+		"  details: fields=encodng, operator_id=stdout, operator_type=stdout",
+	}
+	for _, line := range expected {
+		require.Contains(t, stderr.String(), line)
+	}
+}
+
+func TestRunPipelineError(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--config", "testdata/missing_output.yaml"}, &stdout, &stderr)
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr.String(), "error: operator 'missing' does not exist")
+	require.Contains(t, stderr.String(), "  details: operator_id=json_parser")
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 2, run([]string{}, &stdout, &stderr))
+	require.Contains(t, stderr.String(), "the --config flag is required")
+
+	require.Equal(t, 2, run([]string{"--unknown"}, &stdout, &stderr))
+	require.Equal(t, 1, run([]string{"--config", "testdata/missing.yaml"}, &stdout, &stderr))
+}
+
+func TestRunSchema(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, run([]string{"--schema"}, &stdout, &stderr), stderr.String())
+
+	var schema struct {
+		OneOf []struct {
+			Title string `json:"title"`
+		} `json:"oneOf"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &schema))
+
+	titles := make([]string, 0, len(schema.OneOf))
+	for _, operatorSchema := range schema.OneOf {
+		titles = append(titles, operatorSchema.Title)
+	}
+	require.Contains(t, titles, "file_input")
+	require.Contains(t, titles, "regex_parser")
+}
+
+func TestRunSample(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--config", "testdata/valid.yaml", "--sample", "testdata/sample.log"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+
+	var bodies []interface{}
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+
+		var printed struct {
+			Output string `json:"output"`
+			Entry  struct {
+				Body       interface{}            `json:"body"`
+				Attributes map[string]interface{} `json:"attributes"`
+			} `json:"entry"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(line), &printed))
+		require.Equal(t, "stdout", printed.Output)
+		bodies = append(bodies, printed.Entry.Body)
+
+		if printed.Entry.Body == "info started" {
+			require.Equal(t, "started", printed.Entry.Attributes["message"])
+		}
+	}
+
+	// The filtered line is dropped, and the malformed line is sent on after the parser fails
+	require.Equal(t, []interface{}{"info started", "error failed", "malformed"}, bodies)
+	require.Contains(t, stderr.String(), "regex pattern does not match")
+}
@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+// Operators are registered when their packages are imported
+import (
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/input/file"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/input/generate"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/input/k8sevent"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/input/stdin"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/input/syslog"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/input/tcp"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/input/udp"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/output/drop"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/output/file"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/output/stdout"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/parser/csv"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/parser/json"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/parser/keyvalue"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/parser/regex"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/parser/scope"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/parser/severity"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/parser/syslog"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/parser/time"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/parser/trace"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/parser/uri"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/add"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/buffer"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/copy"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/filter"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/flatten"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/move"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/noop"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/recombine"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/remove"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/retain"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/router"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/sizelimit"
+)
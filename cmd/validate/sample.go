@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/pipeline"
+	"github.com/open-telemetry/opentelemetry-log-collection/pipeline/sample"
+)
+
+// runSample sends each line of a file through the pipeline, and prints the entries that reach its outputs
+func runSample(cfg pipeline.Config, path string, logger *zap.SugaredLogger, w io.Writer) error {
+	lines, err := sample.ReadLines(path)
+	if err != nil {
+		return err
+	}
+
+	printer := &entryPrinter{encoder: json.NewEncoder(w)}
+	return sample.Run(cfg, lines, nil, printer.print, logger)
+}
+
+// entryPrinter prints entries as JSON, one per line
+type entryPrinter struct {
+	mux     sync.Mutex
+	encoder *json.Encoder
+}
+
+func (p *entryPrinter) print(outputID string, e *entry.Entry) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return p.encoder.Encode(struct {
+		Output string       `json:"output"`
+		Entry  *entry.Entry `json:"entry"`
+	}{outputID, e})
+}
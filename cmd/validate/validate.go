@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/pipeline"
+	"github.com/open-telemetry/opentelemetry-log-collection/pipeline/sample"
+)
+
+// configError is an error found in the configuration
+type configError struct {
+	// location describes the operator that caused the error, if any
+	location string
+	err      error
+}
+
+// loadConfig loads a pipeline config, and builds each operator to find all of the errors in it
+func loadConfig(path string, logger *zap.SugaredLogger) (pipeline.Config, []configError) {
+	file, err := sample.ReadConfigFile(path)
+	if err != nil {
+		return pipeline.Config{}, []configError{{err: err}}
+	}
+
+	if len(file.Pipeline) == 0 {
+		return pipeline.Config{}, []configError{{err: errors.NewError(
+			"the configuration does not define any operators",
+			"ensure that the `pipeline` field contains at least one operator",
+		)}}
+	}
+
+	var errs []configError
+	cfg := pipeline.Config{Queues: file.Queues}
+	for i, opCfg := range file.Pipeline {
+		if opCfg.Err != nil {
+			errs = append(errs, configError{location: fmt.Sprintf("operator %d", i+1), err: opCfg.Err})
+			continue
+		}
+
+		if err := buildOperator(opCfg.Config, logger); err != nil {
+			location := fmt.Sprintf("operator %d (%s)", i+1, opCfg.Config.ID())
+			errs = append(errs, configError{location: location, err: err})
+			continue
+		}
+		cfg.Operators = append(cfg.Operators, opCfg.Config)
+	}
+
+	return cfg, errs
+}
+
+// buildOperator builds an operator to check its config. Template operators
+// are only checked for unknown fields, because they are built by the pipeline.
+func buildOperator(cfg operator.Config, logger *zap.SugaredLogger) error {
+	if _, ok := cfg.Builder.(*pipeline.TemplateConfig); ok {
+		return cfg.CheckUnknownFields()
+	}
+	_, err := cfg.Build(logger)
+	return err
+}
+
+// reportErrors writes errors, including the suggestion and details of agent errors
+func reportErrors(w io.Writer, errs []configError) {
+	for _, configErr := range errs {
+		var location string
+		if configErr.location != "" {
+			location = configErr.location + ": "
+		}
+
+		var agentErr errors.AgentError
+		if !stderrors.As(configErr.err, &agentErr) {
+			fmt.Fprintf(w, "error: %s%s\n", location, configErr.err)
+			continue
+		}
+
+		fmt.Fprintf(w, "error: %s%s\n", location, agentErr.Description)
+		if agentErr.Suggestion != "" {
+			fmt.Fprintf(w, "  suggestion: %s\n", agentErr.Suggestion)
+		}
+		if len(agentErr.Details) > 0 {
+			keys := make([]string, 0, len(agentErr.Details))
+			for key := range agentErr.Details {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			details := make([]string, 0, len(keys))
+			for _, key := range keys {
+				details = append(details, fmt.Sprintf("%s=%s", key, agentErr.Details[key]))
+			}
+			fmt.Fprintf(w, "  details: %s\n", strings.Join(details, ", "))
+		}
+	}
+}
+
+// newLogger creates a logger that writes errors logged by operators
+func newLogger(w io.Writer) *zap.SugaredLogger {
+	encoder := zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	core := zapcore.NewCore(encoder, zapcore.AddSync(w), zapcore.ErrorLevel)
+	return zap.New(core).Sugar()
+}
@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import "sync/atomic"
+
+// ackToken is shared by an entry and all of its copies. Its callback
+// is invoked once every copy of the entry has been acknowledged.
+type ackToken struct {
+	pending  int32
+	callback func()
+}
+
+func (t *ackToken) release() {
+	if atomic.AddInt32(&t.pending, -1) == 0 {
+		t.callback()
+	}
+}
+
+// OnAck registers a callback that is invoked once the entry,
+// and every copy made of it, has been acknowledged.
+func (entry *Entry) OnAck(callback func()) {
+	entry.checkReleased()
+	entry.acks = append(entry.acks, &ackToken{pending: 1, callback: callback})
+}
+
+// Ack acknowledges that the entry has been fully handled. It is called by the
+// last operator to handle an entry, such as an output once the entry has been
+// written, or a filter that drops the entry. Acknowledging an entry more than
+// once has no effect.
+func (entry *Entry) Ack() {
+	entry.checkReleased()
+	acks := entry.acks
+	entry.acks = nil
+	for _, token := range acks {
+		token.release()
+	}
+}
+
+// Abandon discards the pending acknowledgements of the entry without invoking them,
+// so that the entry can be released when it could not be handled. The inputs of an
+// abandoned entry do not commit its position, and will read it again when restarted.
+func (entry *Entry) Abandon() {
+	entry.checkReleased()
+	entry.acks = nil
+}
+
+// MergeAcks moves the pending acknowledgements of another entry to this entry.
+// It is used by operators that combine several entries into one, so that the
+// combined entries are acknowledged once the resulting entry is acknowledged.
+func (entry *Entry) MergeAcks(other *Entry) {
+	entry.acks = append(entry.acks, other.acks...)
+	other.acks = nil
+}
+
+// copyAcks returns the pending acknowledgements of the entry, registering
+// a copy of the entry that must also be acknowledged.
+func (entry *Entry) copyAcks() []*ackToken {
+	if len(entry.acks) == 0 {
+		return nil
+	}
+
+	acks := make([]*ackToken, 0, len(entry.acks))
+	for _, token := range entry.acks {
+		atomic.AddInt32(&token.pending, 1)
+		acks = append(acks, token)
+	}
+	return acks
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAck(t *testing.T) {
+	acked := 0
+	entry := New()
+	entry.OnAck(func() { acked++ })
+
+	entry.Ack()
+	require.Equal(t, 1, acked)
+
+	entry.Ack()
+	require.Equal(t, 1, acked, "acknowledging twice should have no effect")
+}
+
+func TestAckWithoutCallback(t *testing.T) {
+	entry := New()
+	require.NotPanics(t, entry.Ack)
+	require.Nil(t, entry.Copy().acks)
+}
+
+func TestAckCopy(t *testing.T) {
+	acked := 0
+	entry := New()
+	entry.OnAck(func() { acked++ })
+
+	copy1 := entry.Copy()
+	copy2 := copy1.Copy()
+
+	entry.Ack()
+	copy2.Ack()
+	require.Equal(t, 0, acked, "callback should wait for every copy")
+
+	copy1.Ack()
+	require.Equal(t, 1, acked)
+}
+
+func TestAbandon(t *testing.T) {
+	SetPoolDebug(true)
+	defer SetPoolDebug(false)
+
+	entry := New()
+	acked := 0
+	entry.OnAck(func() { acked++ })
+
+	entry.Abandon()
+	entry.Ack()
+	require.Equal(t, 0, acked)
+	require.NotPanics(t, entry.Release)
+}
+
+func TestAckMerge(t *testing.T) {
+	acked := []string{}
+	first := New()
+	first.OnAck(func() { acked = append(acked, "first") })
+	second := New()
+	second.OnAck(func() { acked = append(acked, "second") })
+
+	first.MergeAcks(second)
+	second.Ack()
+	require.Empty(t, acked, "merged acknowledgements should be moved")
+
+	first.Ack()
+	require.Equal(t, []string{"first", "second"}, acked)
+}
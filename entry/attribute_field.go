@@ -96,8 +96,9 @@ func (f AttributeField) Get(entry *Entry) (interface{}, bool) {
 // Set will set a value on an entry's attributes using the field.
 // If a key already exists, it will be overwritten.
 func (f AttributeField) Set(entry *Entry, value interface{}) error {
+	entry.Unshare()
 	if entry.Attributes == nil {
-		entry.Attributes = map[string]interface{}{}
+		entry.Attributes = newMap()
 	}
 
 	mapValue, isMapValue := value.(map[string]interface{})
@@ -124,6 +125,7 @@ func (f AttributeField) Set(entry *Entry, value interface{}) error {
 // Merge will attempt to merge the contents of a map into an entry's attributes.
 // It will overwrite any intermediate values as necessary.
 func (f AttributeField) Merge(entry *Entry, mapValues map[string]interface{}) {
+	entry.Unshare()
 	currentMap := entry.Attributes
 
 	for _, key := range f.Keys {
@@ -138,6 +140,7 @@ func (f AttributeField) Merge(entry *Entry, mapValues map[string]interface{}) {
 // Delete removes a value from an entry's attributes using the field.
 // It will return the deleted value and whether the field existed.
 func (f AttributeField) Delete(entry *Entry) (interface{}, bool) {
+	entry.Unshare()
 	if entry.Attributes == nil {
 		return "", false
 	}
@@ -86,6 +86,7 @@ func (f BodyField) Get(entry *Entry) (interface{}, bool) {
 // Set will set a value on an entry's body using the field.
 // If a key already exists, it will be overwritten.
 func (f BodyField) Set(entry *Entry, value interface{}) error {
+	entry.Unshare()
 	mapValue, isMapValue := value.(map[string]interface{})
 	if isMapValue {
 		f.Merge(entry, mapValue)
@@ -116,6 +117,7 @@ func (f BodyField) Set(entry *Entry, value interface{}) error {
 // Merge will attempt to merge the contents of a map into an entry's body.
 // It will overwrite any intermediate values as necessary.
 func (f BodyField) Merge(entry *Entry, mapValues map[string]interface{}) {
+	entry.Unshare()
 	currentMap, ok := entry.Body.(map[string]interface{})
 	if !ok {
 		currentMap = map[string]interface{}{}
@@ -134,6 +136,7 @@ func (f BodyField) Merge(entry *Entry, mapValues map[string]interface{}) {
 // Delete removes a value from an entry's body using the field.
 // It will return the deleted value and whether the field existed.
 func (f BodyField) Delete(entry *Entry) (interface{}, bool) {
+	entry.Unshare()
 	if f.isRoot() {
 		oldBody := entry.Body
 		entry.Body = nil
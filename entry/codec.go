@@ -0,0 +1,583 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// CodecVersion is the version of the binary encoding written by Marshal.
+// Unmarshal can decode every version up to and including CodecVersion.
+const CodecVersion = 1
+
+// maxCodecDepth limits the nesting of decoded values
+const maxCodecDepth = 10000
+
+// valueTag identifies the type of an encoded value.
+// Tags are part of the encoding, so they must never be changed or reused.
+type valueTag byte
+
+const (
+	tagNil valueTag = iota
+	tagString
+	tagBool
+	tagInt
+	tagInt8
+	tagInt16
+	tagInt32
+	tagInt64
+	tagUint
+	tagUint8
+	tagUint16
+	tagUint32
+	tagUint64
+	tagFloat32
+	tagFloat64
+	tagBytes
+	tagTime
+	tagInterfaceMap
+	tagStringMap
+	tagInterfaceArray
+	tagStringArray
+	tagIntArray
+)
+
+// Marshal encodes an entry into a compact binary representation, which starts with the CodecVersion.
+// The body, attributes and resource may contain nil, strings, booleans, all sized integer and float
+// types, byte slices, time.Time, map[string]interface{}, map[string]string, []interface{}, []string
+// and []int values, and their types are preserved by Unmarshal. Other values are encoded as if they
+// had been copied by Entry.Copy. Times keep their offset from UTC, but not the name of their location.
+// The acknowledgements of an entry are not encoded.
+func Marshal(entry *Entry) ([]byte, error) {
+	entry.checkReleased()
+
+	enc := &encoder{buf: make([]byte, 0, 256)}
+	enc.uvarint(CodecVersion)
+	if err := enc.time(entry.ObservedTimestamp); err != nil {
+		return nil, err
+	}
+	if err := enc.time(entry.Timestamp); err != nil {
+		return nil, err
+	}
+	if err := enc.value(entry.Body); err != nil {
+		return nil, fmt.Errorf("body: %s", err)
+	}
+	if err := enc.value(entry.Attributes); err != nil {
+		return nil, fmt.Errorf("attributes: %s", err)
+	}
+	if err := enc.value(entry.Resource); err != nil {
+		return nil, fmt.Errorf("resource: %s", err)
+	}
+	enc.string(entry.SeverityText)
+	enc.varint(int64(entry.Severity))
+	enc.bytes(entry.SpanId)
+	enc.bytes(entry.TraceId)
+	enc.bytes(entry.TraceFlags)
+	enc.string(entry.ScopeName)
+	return enc.buf, nil
+}
+
+// Unmarshal decodes an entry that was encoded with Marshal.
+// The entry is taken from the pool used by New.
+func Unmarshal(data []byte) (*Entry, error) {
+	dec := &decoder{data: data}
+	version, err := dec.uvarint()
+	if err != nil {
+		return nil, fmt.Errorf("version: %s", err)
+	}
+	if version == 0 || version > CodecVersion {
+		return nil, fmt.Errorf("unsupported encoding version %d", version)
+	}
+
+	entry := entryPool.Get().(*Entry)
+	if err := dec.entry(entry); err != nil {
+		*entry = Entry{}
+		entryPool.Put(entry)
+		return nil, err
+	}
+	if len(dec.data) != dec.pos {
+		*entry = Entry{}
+		entryPool.Put(entry)
+		return nil, fmt.Errorf("found %d unexpected bytes after the entry", len(dec.data)-dec.pos)
+	}
+	return entry, nil
+}
+
+// encoder appends encoded values to a buffer
+type encoder struct {
+	buf     []byte
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (e *encoder) uvarint(v uint64) {
+	n := binary.PutUvarint(e.scratch[:], v)
+	e.buf = append(e.buf, e.scratch[:n]...)
+}
+
+func (e *encoder) varint(v int64) {
+	n := binary.PutVarint(e.scratch[:], v)
+	e.buf = append(e.buf, e.scratch[:n]...)
+}
+
+func (e *encoder) string(s string) {
+	e.uvarint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// bytes encodes a byte slice, preserving whether it is nil
+func (e *encoder) bytes(b []byte) {
+	if b == nil {
+		e.uvarint(0)
+		return
+	}
+	e.uvarint(uint64(len(b)) + 1)
+	e.buf = append(e.buf, b...)
+}
+
+// time encodes a time with its offset from UTC, or nothing if it is the zero time
+func (e *encoder) time(t time.Time) error {
+	if t.IsZero() {
+		e.bytes(nil)
+		return nil
+	}
+	b, err := t.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("time: %s", err)
+	}
+	e.bytes(b)
+	return nil
+}
+
+func (e *encoder) tag(t valueTag) {
+	e.buf = append(e.buf, byte(t))
+}
+
+func (e *encoder) value(v interface{}) error {
+	switch value := v.(type) {
+	case nil:
+		e.tag(tagNil)
+	case string:
+		e.tag(tagString)
+		e.string(value)
+	case bool:
+		e.tag(tagBool)
+		if value {
+			e.buf = append(e.buf, 1)
+		} else {
+			e.buf = append(e.buf, 0)
+		}
+	case int:
+		e.tag(tagInt)
+		e.varint(int64(value))
+	case int8:
+		e.tag(tagInt8)
+		e.varint(int64(value))
+	case int16:
+		e.tag(tagInt16)
+		e.varint(int64(value))
+	case int32:
+		e.tag(tagInt32)
+		e.varint(int64(value))
+	case int64:
+		e.tag(tagInt64)
+		e.varint(value)
+	case uint:
+		e.tag(tagUint)
+		e.uvarint(uint64(value))
+	case uint8:
+		e.tag(tagUint8)
+		e.uvarint(uint64(value))
+	case uint16:
+		e.tag(tagUint16)
+		e.uvarint(uint64(value))
+	case uint32:
+		e.tag(tagUint32)
+		e.uvarint(uint64(value))
+	case uint64:
+		e.tag(tagUint64)
+		e.uvarint(value)
+	case float32:
+		e.tag(tagFloat32)
+		binary.LittleEndian.PutUint32(e.scratch[:4], math.Float32bits(value))
+		e.buf = append(e.buf, e.scratch[:4]...)
+	case float64:
+		e.tag(tagFloat64)
+		binary.LittleEndian.PutUint64(e.scratch[:8], math.Float64bits(value))
+		e.buf = append(e.buf, e.scratch[:8]...)
+	case []byte:
+		e.tag(tagBytes)
+		e.bytes(value)
+	case time.Time:
+		e.tag(tagTime)
+		return e.time(value)
+	case map[string]interface{}:
+		if value == nil {
+			e.tag(tagNil)
+			return nil
+		}
+		e.tag(tagInterfaceMap)
+		e.uvarint(uint64(len(value)))
+		for k, child := range value {
+			e.string(k)
+			if err := e.value(child); err != nil {
+				return err
+			}
+		}
+	case map[string]string:
+		e.tag(tagStringMap)
+		e.uvarint(uint64(len(value)))
+		for k, child := range value {
+			e.string(k)
+			e.string(child)
+		}
+	case []interface{}:
+		e.tag(tagInterfaceArray)
+		e.uvarint(uint64(len(value)))
+		for _, child := range value {
+			if err := e.value(child); err != nil {
+				return err
+			}
+		}
+	case []string:
+		e.tag(tagStringArray)
+		e.uvarint(uint64(len(value)))
+		for _, child := range value {
+			e.string(child)
+		}
+	case []int:
+		e.tag(tagIntArray)
+		e.uvarint(uint64(len(value)))
+		for _, child := range value {
+			e.varint(int64(child))
+		}
+	default:
+		// Unknown values are encoded as their JSON representation, as they are by copyUnknown
+		b, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("encode value of type %T: %s", value, err)
+		}
+		var result interface{}
+		if err := json.Unmarshal(b, &result); err != nil {
+			return fmt.Errorf("encode value of type %T: %s", value, err)
+		}
+		return e.value(result)
+	}
+	return nil
+}
+
+// decoder reads encoded values from a buffer
+type decoder struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+func (d *decoder) entry(entry *Entry) error {
+	var err error
+	if entry.ObservedTimestamp, err = d.time(); err != nil {
+		return fmt.Errorf("observed timestamp: %s", err)
+	}
+	if entry.Timestamp, err = d.time(); err != nil {
+		return fmt.Errorf("timestamp: %s", err)
+	}
+	if entry.Body, err = d.value(); err != nil {
+		return fmt.Errorf("body: %s", err)
+	}
+	if entry.Attributes, err = d.topLevelMap(); err != nil {
+		return fmt.Errorf("attributes: %s", err)
+	}
+	if entry.Resource, err = d.topLevelMap(); err != nil {
+		return fmt.Errorf("resource: %s", err)
+	}
+	if entry.SeverityText, err = d.string(); err != nil {
+		return fmt.Errorf("severity text: %s", err)
+	}
+	severity, err := d.varint()
+	if err != nil {
+		return fmt.Errorf("severity: %s", err)
+	}
+	entry.Severity = Severity(severity)
+	if entry.SpanId, err = d.bytes(); err != nil {
+		return fmt.Errorf("span id: %s", err)
+	}
+	if entry.TraceId, err = d.bytes(); err != nil {
+		return fmt.Errorf("trace id: %s", err)
+	}
+	if entry.TraceFlags, err = d.bytes(); err != nil {
+		return fmt.Errorf("trace flags: %s", err)
+	}
+	if entry.ScopeName, err = d.string(); err != nil {
+		return fmt.Errorf("scope name: %s", err)
+	}
+	return nil
+}
+
+func (d *decoder) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid varint at offset %d", d.pos)
+	}
+	d.pos += n
+	return v, nil
+}
+
+func (d *decoder) varint() (int64, error) {
+	v, n := binary.Varint(d.data[d.pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid varint at offset %d", d.pos)
+	}
+	d.pos += n
+	return v, nil
+}
+
+// length reads the length of a value, each element of which takes at least one byte
+func (d *decoder) length() (int, error) {
+	n, err := d.uvarint()
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(d.data)-d.pos) {
+		return 0, fmt.Errorf("length %d at offset %d exceeds the remaining data", n, d.pos)
+	}
+	return int(n), nil
+}
+
+// fixed reads a value of a fixed number of bytes
+func (d *decoder) fixed(n int) ([]byte, error) {
+	if n > len(d.data)-d.pos {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+	return d.next(n), nil
+}
+
+func (d *decoder) next(n int) []byte {
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b
+}
+
+func (d *decoder) string() (string, error) {
+	n, err := d.length()
+	if err != nil {
+		return "", err
+	}
+	return string(d.next(n)), nil
+}
+
+func (d *decoder) bytes() ([]byte, error) {
+	n, err := d.uvarint()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	if n-1 > uint64(len(d.data)-d.pos) {
+		return nil, fmt.Errorf("length %d at offset %d exceeds the remaining data", n-1, d.pos)
+	}
+	return copyByteArray(d.next(int(n - 1))), nil
+}
+
+func (d *decoder) time() (time.Time, error) {
+	b, err := d.bytes()
+	if err != nil || b == nil {
+		return time.Time{}, err
+	}
+	var t time.Time
+	if err := t.UnmarshalBinary(b); err != nil {
+		return time.Time{}, err
+	}
+	return t, nil
+}
+
+// topLevelMap decodes the attributes or resource of an entry
+func (d *decoder) topLevelMap() (map[string]interface{}, error) {
+	if d.pos >= len(d.data) {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+	switch valueTag(d.data[d.pos]) {
+	case tagNil:
+		d.pos++
+		return nil, nil
+	case tagInterfaceMap:
+		d.pos++
+		return d.interfaceMap(newMap())
+	default:
+		return nil, fmt.Errorf("unexpected value tag %d at offset %d", d.data[d.pos], d.pos)
+	}
+}
+
+func (d *decoder) interfaceMap(m map[string]interface{}) (map[string]interface{}, error) {
+	n, err := d.length()
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < n; i++ {
+		k, err := d.string()
+		if err != nil {
+			return nil, err
+		}
+		if m[k], err = d.value(); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (d *decoder) value() (interface{}, error) {
+	if d.pos >= len(d.data) {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > maxCodecDepth {
+		return nil, fmt.Errorf("values are nested more than %d levels deep", maxCodecDepth)
+	}
+
+	tag := valueTag(d.data[d.pos])
+	d.pos++
+	switch tag {
+	case tagNil:
+		return nil, nil
+	case tagString:
+		return d.string()
+	case tagBool:
+		b, err := d.fixed(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case tagInt, tagInt8, tagInt16, tagInt32, tagInt64:
+		v, err := d.varint()
+		if err != nil {
+			return nil, err
+		}
+		return signedValue(tag, v), nil
+	case tagUint, tagUint8, tagUint16, tagUint32, tagUint64:
+		v, err := d.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		return unsignedValue(tag, v), nil
+	case tagFloat32:
+		b, err := d.fixed(4)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(binary.LittleEndian.Uint32(b)), nil
+	case tagFloat64:
+		b, err := d.fixed(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case tagBytes:
+		return d.bytes()
+	case tagTime:
+		return d.time()
+	case tagInterfaceMap:
+		return d.interfaceMap(map[string]interface{}{})
+	case tagStringMap:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]string, n)
+		for i := 0; i < n; i++ {
+			k, err := d.string()
+			if err != nil {
+				return nil, err
+			}
+			if m[k], err = d.string(); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case tagInterfaceArray:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		a := make([]interface{}, n)
+		for i := range a {
+			if a[i], err = d.value(); err != nil {
+				return nil, err
+			}
+		}
+		return a, nil
+	case tagStringArray:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		a := make([]string, n)
+		for i := range a {
+			if a[i], err = d.string(); err != nil {
+				return nil, err
+			}
+		}
+		return a, nil
+	case tagIntArray:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		a := make([]int, n)
+		for i := range a {
+			v, err := d.varint()
+			if err != nil {
+				return nil, err
+			}
+			a[i] = int(v)
+		}
+		return a, nil
+	default:
+		return nil, fmt.Errorf("unknown value tag %d at offset %d", tag, d.pos-1)
+	}
+}
+
+func signedValue(tag valueTag, v int64) interface{} {
+	switch tag {
+	case tagInt8:
+		return int8(v)
+	case tagInt16:
+		return int16(v)
+	case tagInt32:
+		return int32(v)
+	case tagInt64:
+		return v
+	default:
+		return int(v)
+	}
+}
+
+func unsignedValue(tag valueTag, v uint64) interface{} {
+	switch tag {
+	case tagUint8:
+		return uint8(v)
+	case tagUint16:
+		return uint16(v)
+	case tagUint32:
+		return uint32(v)
+	case tagUint64:
+		return v
+	default:
+		return uint(v)
+	}
+}
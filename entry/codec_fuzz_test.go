@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package entry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func FuzzUnmarshal(f *testing.F) {
+	for _, e := range []*Entry{{}, {Body: "body"}, codecTestEntry()} {
+		data, err := Marshal(e)
+		require.NoError(f, err)
+		f.Add(data)
+	}
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		e, err := Unmarshal(data)
+		if err != nil {
+			return
+		}
+
+		// Any decoded entry can be encoded and decoded again
+		encoded, err := Marshal(e)
+		require.NoError(t, err)
+		decoded, err := Unmarshal(encoded)
+		require.NoError(t, err)
+		reencoded, err := Marshal(decoded)
+		require.NoError(t, err)
+		require.Len(t, reencoded, len(encoded))
+	})
+}
@@ -0,0 +1,218 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func codecTestEntry() *Entry {
+	location := time.FixedZone("test", 3*60*60)
+	e := New()
+	e.ObservedTimestamp = time.Date(2022, time.May, 1, 12, 0, 1, 123456789, time.UTC)
+	e.Timestamp = time.Date(2022, time.May, 1, 15, 0, 0, 1, location)
+	e.Body = map[string]interface{}{
+		"string":    "value",
+		"empty":     "",
+		"bool":      true,
+		"nil":       nil,
+		"int":       -1,
+		"int8":      int8(math.MinInt8),
+		"int16":     int16(math.MaxInt16),
+		"int32":     int32(math.MinInt32),
+		"int64":     int64(math.MaxInt64),
+		"uint":      uint(1),
+		"uint8":     uint8(math.MaxUint8),
+		"uint16":    uint16(math.MaxUint16),
+		"uint32":    uint32(math.MaxUint32),
+		"uint64":    uint64(math.MaxUint64),
+		"float32":   float32(1.5),
+		"float64":   math.Pi,
+		"bytes":     []byte{0, 1, 2},
+		"time":      time.Date(2021, time.January, 2, 3, 4, 5, 6, location),
+		"map":       map[string]interface{}{"nested": []interface{}{1, "two", 3.0}},
+		"stringMap": map[string]string{"a": "b"},
+		"strings":   []string{"a", "b"},
+		"ints":      []int{1, 2, 3},
+		"array":     []interface{}{map[string]interface{}{"k": "v"}, []byte("b"), nil},
+	}
+	e.Attributes = map[string]interface{}{
+		"key":    "value",
+		"nested": map[string]interface{}{"key": float64(2)},
+	}
+	e.Resource = map[string]interface{}{"host": "localhost"}
+	e.SeverityText = "ERROR"
+	e.Severity = Error
+	e.SpanId = []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	e.TraceId = []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	e.TraceFlags = []byte{1}
+	e.ScopeName = "scope"
+	return e
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	e := codecTestEntry()
+	data, err := Marshal(e)
+	require.NoError(t, err)
+	require.Equal(t, byte(CodecVersion), data[0])
+
+	decoded, err := Unmarshal(data)
+	require.NoError(t, err)
+	body := decoded.Body.(map[string]interface{})
+	requireSameTime(t, e.Body.(map[string]interface{})["time"].(time.Time), body["time"].(time.Time))
+	body["time"] = e.Body.(map[string]interface{})["time"]
+	require.Equal(t, e.Body, decoded.Body)
+	require.Equal(t, e.Attributes, decoded.Attributes)
+	require.Equal(t, e.Resource, decoded.Resource)
+	requireSameTime(t, e.ObservedTimestamp, decoded.ObservedTimestamp)
+	requireSameTime(t, e.Timestamp, decoded.Timestamp)
+	require.Equal(t, e.SeverityText, decoded.SeverityText)
+	require.Equal(t, e.Severity, decoded.Severity)
+	require.Equal(t, e.SpanId, decoded.SpanId)
+	require.Equal(t, e.TraceId, decoded.TraceId)
+	require.Equal(t, e.TraceFlags, decoded.TraceFlags)
+	require.Equal(t, e.ScopeName, decoded.ScopeName)
+}
+
+func requireSameTime(t *testing.T, expected, actual time.Time) {
+	require.True(t, expected.Equal(actual), "expected %s, got %s", expected, actual)
+	_, expectedOffset := expected.Zone()
+	_, actualOffset := actual.Zone()
+	require.Equal(t, expectedOffset, actualOffset)
+}
+
+func TestCodecEmptyEntry(t *testing.T) {
+	data, err := Marshal(&Entry{})
+	require.NoError(t, err)
+
+	decoded, err := Unmarshal(data)
+	require.NoError(t, err)
+	require.Equal(t, &Entry{}, decoded)
+}
+
+func TestCodecPreservesEmptyValues(t *testing.T) {
+	e := &Entry{
+		Body:       []interface{}{},
+		Attributes: map[string]interface{}{},
+		SpanId:     []byte{},
+	}
+	data, err := Marshal(e)
+	require.NoError(t, err)
+
+	decoded, err := Unmarshal(data)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{}, decoded.Body)
+	require.Equal(t, map[string]interface{}{}, decoded.Attributes)
+	require.Nil(t, decoded.Resource)
+	require.Equal(t, []byte{}, decoded.SpanId)
+	require.Nil(t, decoded.TraceId)
+}
+
+func TestCodecDoesNotAliasData(t *testing.T) {
+	e := &Entry{Body: []byte("body"), TraceId: []byte{1, 2}}
+	data, err := Marshal(e)
+	require.NoError(t, err)
+
+	decoded, err := Unmarshal(data)
+	require.NoError(t, err)
+	for i := range data {
+		data[i] = 0
+	}
+	require.Equal(t, []byte("body"), decoded.Body)
+	require.Equal(t, []byte{1, 2}, decoded.TraceId)
+}
+
+type codecTestStruct struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestCodecUnknownType(t *testing.T) {
+	e := &Entry{Body: codecTestStruct{Name: "test", Count: 2}}
+	data, err := Marshal(e)
+	require.NoError(t, err)
+
+	decoded, err := Unmarshal(data)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"name": "test", "count": float64(2)}, decoded.Body)
+}
+
+func TestCodecUnsupportedType(t *testing.T) {
+	_, err := Marshal(&Entry{Body: make(chan int)})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "body")
+}
+
+func TestCodecInvalidData(t *testing.T) {
+	valid, err := Marshal(codecTestEntry())
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{"Empty", []byte{}, "version"},
+		{"ZeroVersion", []byte{0}, "unsupported encoding version 0"},
+		{"FutureVersion", []byte{CodecVersion + 1}, "unsupported encoding version"},
+		{"Truncated", valid[:len(valid)/2], ""},
+		{"TrailingBytes", append(append([]byte{}, valid...), 0), "unexpected bytes"},
+		{"UnknownTag", []byte{CodecVersion, 0, 0, 0xff}, "unknown value tag"},
+		{"LongString", []byte{CodecVersion, 0, 0, byte(tagString), 0x7f, 'a'}, "exceeds the remaining data"},
+		{"AttributesNotMap", []byte{CodecVersion, 0, 0, byte(tagNil), byte(tagString), 0}, "attributes"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Unmarshal(tc.data)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestCodecDepthLimit(t *testing.T) {
+	data := []byte{CodecVersion, 0, 0}
+	for i := 0; i <= maxCodecDepth; i++ {
+		data = append(data, byte(tagInterfaceArray), 1)
+	}
+	data = append(data, byte(tagNil))
+
+	_, err := Unmarshal(data)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "nested")
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	e := codecTestEntry()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Marshal(e)
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	data, err := Marshal(codecTestEntry())
+	require.NoError(b, err)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e, _ := Unmarshal(data)
+		e.Release()
+	}
+}
@@ -49,11 +49,15 @@ func copyStringMap(m map[string]string) map[string]string {
 
 // copyInterfaceMap will deep copy a map of interfaces.
 func copyInterfaceMap(m map[string]interface{}) map[string]interface{} {
-	mapCopy := make(map[string]interface{})
+	return copyInterfaceMapTo(make(map[string]interface{}, len(m)), m)
+}
+
+// copyInterfaceMapTo will deep copy a map of interfaces into an empty map.
+func copyInterfaceMapTo(dst, m map[string]interface{}) map[string]interface{} {
 	for k, v := range m {
-		mapCopy[k] = copyValue(v)
+		dst[k] = copyValue(v)
 	}
-	return mapCopy
+	return dst
 }
 
 // copyStringArray will deep copy an array of strings.
@@ -34,33 +34,43 @@ type Entry struct {
 	TraceFlags        []byte                 `json:"trace_flags,omitempty"   yaml:"trace_flags,omitempty"`
 	Severity          Severity               `json:"severity"                yaml:"severity"`
 	ScopeName         string                 `json:"scope_name"              yaml:"scope_name"`
+
+	acks []*ackToken
+
+	// shared counts the entries that share the body, attributes and resource of the entry
+	shared   *int32
+	released bool
 }
 
 // New will create a new log entry with current timestamp and an empty body.
+// The entry is taken from a pool, to which it can be returned with Release.
 func New() *Entry {
-	return &Entry{
-		ObservedTimestamp: timeNow(),
-	}
+	entry := entryPool.Get().(*Entry)
+	entry.ObservedTimestamp = timeNow()
+	return entry
 }
 
 // AddAttribute will add a key/value pair to the entry's attributes.
 func (entry *Entry) AddAttribute(key, value string) {
+	entry.Unshare()
 	if entry.Attributes == nil {
-		entry.Attributes = make(map[string]interface{})
+		entry.Attributes = newMap()
 	}
 	entry.Attributes[key] = value
 }
 
 // AddResourceKey wil add a key/value pair to the entry's resource.
 func (entry *Entry) AddResourceKey(key, value string) {
+	entry.Unshare()
 	if entry.Resource == nil {
-		entry.Resource = make(map[string]interface{})
+		entry.Resource = newMap()
 	}
 	entry.Resource[key] = value
 }
 
 // Get will return the value of a field on the entry, including a boolean indicating if the field exists.
 func (entry *Entry) Get(field FieldInterface) (interface{}, bool) {
+	entry.checkReleased()
 	return field.Get(entry)
 }
 
@@ -76,6 +86,7 @@ func (entry *Entry) Delete(field FieldInterface) (interface{}, bool) {
 
 // Read will read the value of a field into a designated interface.
 func (entry *Entry) Read(field FieldInterface, dest interface{}) error {
+	entry.checkReleased()
 	switch dest := dest.(type) {
 	case *string:
 		return entry.readToString(field, dest)
@@ -175,17 +186,20 @@ func (entry *Entry) readToStringMap(field FieldInterface, dest *map[string]strin
 
 // Copy will return a deep copy of the entry.
 func (entry *Entry) Copy() *Entry {
-	return &Entry{
-		ObservedTimestamp: entry.ObservedTimestamp,
-		Timestamp:         entry.Timestamp,
-		Severity:          entry.Severity,
-		SeverityText:      entry.SeverityText,
-		Attributes:        copyInterfaceMap(entry.Attributes),
-		Resource:          copyInterfaceMap(entry.Resource),
-		Body:              copyValue(entry.Body),
-		TraceId:           copyByteArray(entry.TraceId),
-		SpanId:            copyByteArray(entry.SpanId),
-		TraceFlags:        copyByteArray(entry.TraceFlags),
-		ScopeName:         entry.ScopeName,
-	}
+	entry.checkReleased()
+
+	entryCopy := entryPool.Get().(*Entry)
+	entryCopy.ObservedTimestamp = entry.ObservedTimestamp
+	entryCopy.Timestamp = entry.Timestamp
+	entryCopy.Severity = entry.Severity
+	entryCopy.SeverityText = entry.SeverityText
+	entryCopy.Attributes = copyInterfaceMapTo(newMap(), entry.Attributes)
+	entryCopy.Resource = copyInterfaceMapTo(newMap(), entry.Resource)
+	entryCopy.Body = copyValue(entry.Body)
+	entryCopy.TraceId = copyByteArray(entry.TraceId)
+	entryCopy.SpanId = copyByteArray(entry.SpanId)
+	entryCopy.TraceFlags = copyByteArray(entry.TraceFlags)
+	entryCopy.ScopeName = entry.ScopeName
+	entryCopy.acks = entry.copyAcks()
+	return entryCopy
 }
@@ -17,6 +17,7 @@ package entry
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -64,29 +65,46 @@ func (f *Field) UnmarshalYAML(unmarshal func(interface{}) error) error {
 }
 
 func NewField(s string) (Field, error) {
-	keys, err := fromJSONDot(s)
+	keys, kinds, err := splitField(s)
 	if err != nil {
 		return Field{}, fmt.Errorf("splitting field: %s", err)
 	}
+	if kinds != nil && kinds[0] != literalKey {
+		return Field{}, fmt.Errorf("unrecognized prefix")
+	}
 
 	switch keys[0] {
 	case AttributesPrefix:
 		if len(keys) == 1 {
 			return Field{}, fmt.Errorf("attributes cannot be referenced without subfield")
 		}
-		return NewAttributeField(keys[1:]...), nil
+		return newPrefixedField(AttributesPrefix, keys[1:], subKinds(kinds)), nil
 	case ResourcePrefix:
 		if len(keys) == 1 {
 			return Field{}, fmt.Errorf("resource cannot be referenced without subfield")
 		}
-		return NewResourceField(keys[1:]...), nil
+		return newPrefixedField(ResourcePrefix, keys[1:], subKinds(kinds)), nil
 	case BodyPrefix:
-		return NewBodyField(keys[1:]...), nil
+		return newPrefixedField(BodyPrefix, keys[1:], subKinds(kinds)), nil
 	default:
 		return Field{}, fmt.Errorf("unrecognized prefix")
 	}
 }
 
+// Expand returns a field for each value on an entry that is matched by the field.
+// Wildcards are replaced by the keys and indices that they match, and negative
+// indices are resolved. Fields that do not exist on the entry are not returned.
+func (f Field) Expand(entry *Entry) []Field {
+	if field, ok := f.FieldInterface.(selectorField); ok {
+		return field.expand(entry)
+	}
+
+	if _, ok := f.Get(entry); ok {
+		return []Field{f}
+	}
+	return nil
+}
+
 // MarshalJSON will marshal a field into JSON
 func (f Field) MarshalJSON() ([]byte, error) {
 	return []byte(fmt.Sprintf(`"%s"`, f.String())), nil
@@ -112,15 +130,41 @@ const (
 	OutBracket
 	// InUnbracketedToken is the state field split on any token outside brackets
 	InUnbracketedToken
+	// InBracketedSelector is the state of a field split on an index or wildcard inside a bracket
+	InBracketedSelector
 )
 
+// fromJSONDot splits a field of literal keys into its keys.
 func fromJSONDot(s string) ([]string, error) {
+	keys, kinds, err := splitField(s)
+	if err != nil {
+		return nil, err
+	}
+	if kinds != nil {
+		return nil, fmt.Errorf("indices and wildcards are not supported: %s", s)
+	}
+	return keys, nil
+}
+
+// splitField splits a field into its keys, and the kinds of those keys.
+// The kinds are nil if every key is literal.
+func splitField(s string) ([]string, []keyKind, error) {
 	fields := make([]string, 0, 1)
+	kinds := make([]keyKind, 0, 1)
 
 	state := Begin
 	var quoteChar rune
 	var tokenStart int
 
+	addUnbracketed := func(token string) {
+		fields = append(fields, token)
+		if isWildcard(token) {
+			kinds = append(kinds, wildcardKey)
+		} else {
+			kinds = append(kinds, literalKey)
+		}
+	}
+
 	for i, c := range s {
 		switch state {
 		case Begin:
@@ -131,20 +175,41 @@ func fromJSONDot(s string) ([]string, error) {
 			tokenStart = i
 			state = InUnbracketedToken
 		case InBracket:
+			if c == '*' || c == '-' || (c >= '0' && c <= '9') {
+				state = InBracketedSelector
+				tokenStart = i
+				continue
+			}
 			if !(c == '\'' || c == '"') {
-				return nil, fmt.Errorf("strings in brackets must be surrounded by quotes")
+				return nil, nil, fmt.Errorf("strings in brackets must be surrounded by quotes")
 			}
 			state = InQuote
 			quoteChar = c
 			tokenStart = i + 1
+		case InBracketedSelector:
+			if c != ']' {
+				continue
+			}
+			token := s[tokenStart:i]
+			if token == "*" {
+				fields = append(fields, token)
+				kinds = append(kinds, elementsKey)
+			} else if _, err := strconv.Atoi(token); err == nil {
+				fields = append(fields, token)
+				kinds = append(kinds, indexKey)
+			} else {
+				return nil, nil, fmt.Errorf("brackets must contain a quoted string, an index, or a wildcard")
+			}
+			state = OutBracket
 		case InQuote:
 			if c == quoteChar {
 				fields = append(fields, s[tokenStart:i])
+				kinds = append(kinds, literalKey)
 				state = OutQuote
 			}
 		case OutQuote:
 			if c != ']' {
-				return nil, fmt.Errorf("found characters between closed quote and closing bracket")
+				return nil, nil, fmt.Errorf("found characters between closed quote and closing bracket")
 			}
 			state = OutBracket
 		case OutBracket:
@@ -155,36 +220,36 @@ func fromJSONDot(s string) ([]string, error) {
 			case '[':
 				state = InBracket
 			default:
-				return nil, fmt.Errorf("bracketed access must be followed by a dot or another bracketed access")
+				return nil, nil, fmt.Errorf("bracketed access must be followed by a dot or another bracketed access")
 			}
 		case InUnbracketedToken:
 			if c == '.' {
-				fields = append(fields, s[tokenStart:i])
+				addUnbracketed(s[tokenStart:i])
 				tokenStart = i + 1
 			} else if c == '[' {
-				fields = append(fields, s[tokenStart:i])
+				addUnbracketed(s[tokenStart:i])
 				state = InBracket
 			}
 		}
 	}
 
 	switch state {
-	case InBracket, OutQuote:
-		return nil, fmt.Errorf("found unclosed left bracket")
+	case InBracket, OutQuote, InBracketedSelector:
+		return nil, nil, fmt.Errorf("found unclosed left bracket")
 	case InQuote:
 		if quoteChar == '"' {
-			return nil, fmt.Errorf("found unclosed double quote")
+			return nil, nil, fmt.Errorf("found unclosed double quote")
 		}
-		return nil, fmt.Errorf("found unclosed single quote")
+		return nil, nil, fmt.Errorf("found unclosed single quote")
 	case InUnbracketedToken:
-		fields = append(fields, s[tokenStart:])
+		addUnbracketed(s[tokenStart:])
 	}
 
 	if len(fields) == 0 {
-		return nil, fmt.Errorf("fields size is 0")
+		return nil, nil, fmt.Errorf("fields size is 0")
 	}
 
-	return fields, nil
+	return fields, selectorKinds(kinds), nil
 }
 
 // toJSONDot returns the JSON dot notation for a field.
@@ -193,16 +258,17 @@ func toJSONDot(prefix string, keys []string) string {
 		return prefix
 	}
 
-	containsDots := false
+	// Keys that would otherwise be read as selectors are quoted in brackets
+	bracketed := false
 	for _, key := range keys {
-		if strings.Contains(key, ".") {
-			containsDots = true
+		if strings.ContainsAny(key, ".*?[]") {
+			bracketed = true
 		}
 	}
 
 	var b strings.Builder
 	b.WriteString(prefix)
-	if containsDots {
+	if bracketed {
 		for _, key := range keys {
 			b.WriteString(`['`)
 			b.WriteString(key)
@@ -0,0 +1,155 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	entryPool = sync.Pool{
+		New: func() interface{} { return &Entry{} },
+	}
+
+	// mapPool holds the cleared attribute and resource maps of released entries
+	mapPool = sync.Pool{
+		New: func() interface{} { return make(map[string]interface{}) },
+	}
+
+	poolDebug int32
+)
+
+// SetPoolDebug enables or disables the detection of entries that are used after they were released.
+// While enabled, released entries are never reused, and using an entry after it was released,
+// releasing it twice, or releasing it before it was acknowledged will panic.
+func SetPoolDebug(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&poolDebug, 1)
+	} else {
+		atomic.StoreInt32(&poolDebug, 0)
+	}
+}
+
+// Release returns the entry to the pool used by New and Copy. It is called by the last operator
+// to handle an entry, such as an output after the entry was written and acknowledged, or an
+// operator that drops the entry. Neither the entry nor its attributes or resource may be used
+// after it is released.
+func (entry *Entry) Release() {
+	entry.checkReleased()
+
+	if atomic.LoadInt32(&poolDebug) == 1 {
+		if len(entry.acks) > 0 {
+			panic("entry was released before it was acknowledged")
+		}
+		entry.dropShared()
+		entry.released = true
+		return
+	}
+
+	// Maps shared with copies of the entry are still in use, so only owned maps are reused
+	if entry.dropShared() {
+		releaseMap(entry.Attributes)
+		releaseMap(entry.Resource)
+	}
+	*entry = Entry{}
+	entryPool.Put(entry)
+}
+
+// Share returns a copy of the entry that shares its body, attributes and resource with the entry,
+// rather than copying them. Either entry copies them before they are first modified through a field,
+// AddAttribute, AddResourceKey or Unshare, so the copy can be used in place of Copy when entries are sent to
+// several operators. Values read from a shared entry must not be modified directly.
+func (entry *Entry) Share() *Entry {
+	entry.checkReleased()
+
+	if entry.shared == nil {
+		entry.shared = new(int32)
+		*entry.shared = 1
+	}
+	atomic.AddInt32(entry.shared, 1)
+
+	shared := entryPool.Get().(*Entry)
+	shared.ObservedTimestamp = entry.ObservedTimestamp
+	shared.Timestamp = entry.Timestamp
+	shared.Body = entry.Body
+	shared.Attributes = entry.Attributes
+	shared.Resource = entry.Resource
+	shared.SeverityText = entry.SeverityText
+	shared.SpanId = entry.SpanId
+	shared.TraceId = entry.TraceId
+	shared.TraceFlags = entry.TraceFlags
+	shared.Severity = entry.Severity
+	shared.ScopeName = entry.ScopeName
+	shared.shared = entry.shared
+	shared.acks = entry.copyAcks()
+	return shared
+}
+
+// Unshare copies the body, attributes and resource of the entry if they are shared with other
+// entries. It is called by the methods that modify an entry, and must be called before values
+// read from the entry are modified directly.
+func (entry *Entry) Unshare() {
+	entry.checkReleased()
+	if entry.shared == nil {
+		return
+	}
+
+	// The values are copied before the entry stops counting as a sharer,
+	// so that the last sharer does not modify them while they are copied
+	if atomic.LoadInt32(entry.shared) > 1 {
+		entry.Body = copyValue(entry.Body)
+		if entry.Attributes != nil {
+			entry.Attributes = copyInterfaceMapTo(newMap(), entry.Attributes)
+		}
+		if entry.Resource != nil {
+			entry.Resource = copyInterfaceMapTo(newMap(), entry.Resource)
+		}
+	}
+	atomic.AddInt32(entry.shared, -1)
+	entry.shared = nil
+}
+
+// dropShared stops the entry from counting as a sharer of its values,
+// returning true if no other entry shares them.
+func (entry *Entry) dropShared() bool {
+	if entry.shared == nil {
+		return true
+	}
+	owned := atomic.AddInt32(entry.shared, -1) == 0
+	entry.shared = nil
+	return owned
+}
+
+func (entry *Entry) checkReleased() {
+	if entry.released {
+		panic("entry was used after it was released")
+	}
+}
+
+// newMap returns an empty map, reusing the maps of released entries
+func newMap() map[string]interface{} {
+	return mapPool.Get().(map[string]interface{})
+}
+
+func releaseMap(m map[string]interface{}) {
+	if m == nil {
+		return
+	}
+	for key := range m {
+		delete(m, key)
+	}
+	mapPool.Put(m)
+}
@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAfterRelease(t *testing.T) {
+	entry := New()
+	entry.Body = "test"
+	entry.AddAttribute("key", "value")
+	entry.Release()
+
+	for i := 0; i < 10; i++ {
+		reused := New()
+		require.Nil(t, reused.Body)
+		require.Nil(t, reused.Attributes)
+		require.Nil(t, reused.Resource)
+		require.False(t, reused.ObservedTimestamp.IsZero())
+		reused.AddAttribute("key", "other")
+		require.Equal(t, map[string]interface{}{"key": "other"}, reused.Attributes)
+	}
+}
+
+func TestShare(t *testing.T) {
+	entry := New()
+	entry.Body = map[string]interface{}{"message": "test"}
+	entry.AddAttribute("key", "value")
+
+	shared := entry.Share()
+	require.Equal(t, entry.Body, shared.Body)
+	require.Equal(t, mapPointer(entry.Attributes), mapPointer(shared.Attributes), "values should not be copied until modified")
+
+	require.NoError(t, shared.Set(NewBodyField("message"), "modified"))
+	shared.AddAttribute("key", "modified")
+	require.Equal(t, map[string]interface{}{"message": "test"}, entry.Body)
+	require.Equal(t, map[string]interface{}{"key": "value"}, entry.Attributes)
+	require.Equal(t, map[string]interface{}{"message": "modified"}, shared.Body)
+	require.Equal(t, map[string]interface{}{"key": "modified"}, shared.Attributes)
+
+	// The last entry to share the values modifies them in place
+	attributes := mapPointer(entry.Attributes)
+	entry.AddAttribute("key", "last")
+	require.Equal(t, attributes, mapPointer(entry.Attributes))
+	require.Equal(t, map[string]interface{}{"key": "modified"}, shared.Attributes)
+}
+
+func TestShareRelease(t *testing.T) {
+	entry := New()
+	entry.AddAttribute("key", "value")
+	attributes := mapPointer(entry.Attributes)
+
+	shared := entry.Share()
+	shared.Release()
+
+	entry.AddAttribute("key", "modified")
+	require.Equal(t, attributes, mapPointer(entry.Attributes), "released entries should not share values")
+	require.Equal(t, map[string]interface{}{"key": "modified"}, entry.Attributes)
+}
+
+func TestShareAck(t *testing.T) {
+	acked := 0
+	entry := New()
+	entry.OnAck(func() { acked++ })
+
+	shared := entry.Share()
+	entry.Ack()
+	require.Equal(t, 0, acked, "callback should wait for every shared entry")
+
+	shared.Ack()
+	require.Equal(t, 1, acked)
+}
+
+func TestPoolDebug(t *testing.T) {
+	SetPoolDebug(true)
+	defer SetPoolDebug(false)
+
+	entry := New()
+	entry.Release()
+	require.PanicsWithValue(t, "entry was used after it was released", func() { entry.Get(NewBodyField()) })
+	require.PanicsWithValue(t, "entry was used after it was released", func() { _ = entry.Set(NewAttributeField("key"), "value") })
+	require.PanicsWithValue(t, "entry was used after it was released", entry.Release)
+	require.NotSame(t, entry, New(), "released entries should not be reused")
+
+	unacked := New()
+	unacked.OnAck(func() {})
+	require.PanicsWithValue(t, "entry was released before it was acknowledged", unacked.Release)
+}
+
+func mapPointer(m map[string]interface{}) uintptr {
+	return reflect.ValueOf(m).Pointer()
+}
+
+func newBenchmarkEntry() *Entry {
+	entry := New()
+	entry.Body = map[string]interface{}{"message": "test", "level": "info"}
+	entry.AddAttribute("log.file.name", "test.log")
+	entry.AddAttribute("log.file.path", "/var/log/test.log")
+	return entry
+}
+
+func BenchmarkCopy(b *testing.B) {
+	entry := newBenchmarkEntry()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		entry.Copy().Release()
+	}
+}
+
+func BenchmarkShare(b *testing.B) {
+	entry := newBenchmarkEntry()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		entry.Share().Release()
+	}
+}
@@ -96,8 +96,9 @@ func (f ResourceField) Get(entry *Entry) (interface{}, bool) {
 // Set will set a value on an entry's resource using the field.
 // If a key already exists, it will be overwritten.
 func (f ResourceField) Set(entry *Entry, value interface{}) error {
+	entry.Unshare()
 	if entry.Resource == nil {
-		entry.Resource = map[string]interface{}{}
+		entry.Resource = newMap()
 	}
 
 	mapValue, isMapValue := value.(map[string]interface{})
@@ -124,6 +125,7 @@ func (f ResourceField) Set(entry *Entry, value interface{}) error {
 // Merge will attempt to merge the contents of a map into an entry's resource.
 // It will overwrite any intermediate values as necessary.
 func (f ResourceField) Merge(entry *Entry, mapValues map[string]interface{}) {
+	entry.Unshare()
 	currentMap := entry.Resource
 
 	for _, key := range f.Keys {
@@ -138,6 +140,7 @@ func (f ResourceField) Merge(entry *Entry, mapValues map[string]interface{}) {
 // Delete removes a value from an entry's resource using the field.
 // It will return the deleted value and whether the field existed.
 func (f ResourceField) Delete(entry *Entry) (interface{}, bool) {
+	entry.Unshare()
 	if entry.Resource == nil {
 		return "", false
 	}
@@ -0,0 +1,585 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// keyKind determines how a key of a field is matched
+type keyKind uint8
+
+const (
+	// literalKey matches the map key equal to the key
+	literalKey keyKind = iota
+	// indexKey matches the slice element at an index, counted from the end if it is negative
+	indexKey
+	// wildcardKey matches the map keys that match a pattern of '*' and '?' wildcards,
+	// and every slice element if the pattern is '*'
+	wildcardKey
+	// elementsKey is a bracketed '*', which matches in the same way as a '*' wildcardKey
+	elementsKey
+)
+
+// matchKind returns the kind that determines how a key is matched
+func matchKind(kind keyKind) keyKind {
+	if kind == elementsKey {
+		return wildcardKey
+	}
+	return kind
+}
+
+// hasWildcard returns true if any of the kinds is a wildcard
+func hasWildcard(kinds []keyKind) bool {
+	for _, kind := range kinds {
+		if matchKind(kind) == wildcardKey {
+			return true
+		}
+	}
+	return false
+}
+
+// selectorKinds returns the kinds of a field's keys, or nil if every key is literal
+func selectorKinds(kinds []keyKind) []keyKind {
+	for _, kind := range kinds {
+		if kind != literalKey {
+			return kinds
+		}
+	}
+	return nil
+}
+
+// fieldPath is a field that has been resolved against the values of an entry
+type fieldPath struct {
+	keys  []string
+	kinds []keyKind
+}
+
+// getPath returns the value at a path, or the values matched by its wildcards.
+// Wildcards return a map of the matched map keys, or a slice of the matched elements.
+func getPath(value interface{}, keys []string, kinds []keyKind) (interface{}, bool) {
+	if len(keys) == 0 {
+		return value, true
+	}
+
+	key, kind := keys[0], matchKind(kinds[0])
+	switch kind {
+	case indexKey:
+		slice, ok := value.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		i, ok := resolveIndex(key, len(slice))
+		if !ok {
+			return nil, false
+		}
+		return getPath(slice[i], keys[1:], kinds[1:])
+	case wildcardKey:
+		switch v := value.(type) {
+		case map[string]interface{}:
+			matched := make(map[string]interface{})
+			for k, child := range v {
+				if !matchWildcard(key, k) {
+					continue
+				}
+				if childValue, ok := getPath(child, keys[1:], kinds[1:]); ok {
+					matched[k] = childValue
+				}
+			}
+			if len(matched) == 0 {
+				return nil, false
+			}
+			return matched, true
+		case []interface{}:
+			if key != "*" {
+				return nil, false
+			}
+			matched := make([]interface{}, 0, len(v))
+			for _, child := range v {
+				if childValue, ok := getPath(child, keys[1:], kinds[1:]); ok {
+					matched = append(matched, childValue)
+				}
+			}
+			if len(matched) == 0 {
+				return nil, false
+			}
+			return matched, true
+		default:
+			return nil, false
+		}
+	default:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		child, ok := m[key]
+		if !ok {
+			return nil, false
+		}
+		return getPath(child, keys[1:], kinds[1:])
+	}
+}
+
+// setPath sets a value at a path, or at every value matched by its wildcards,
+// and returns the updated value. Missing maps and slices are created, and slices
+// may be extended by one element to set the index equal to their length. If a
+// wildcard in the path matches nothing, no maps or slices are created.
+func setPath(value interface{}, keys []string, kinds []keyKind, newValue interface{}) (interface{}, error) {
+	if len(keys) == 0 {
+		// Maps are merged into existing maps, as with Merge
+		newMap, ok := newValue.(map[string]interface{})
+		if !ok {
+			return newValue, nil
+		}
+		currentMap, ok := value.(map[string]interface{})
+		if !ok {
+			currentMap = map[string]interface{}{}
+		}
+		for k, v := range newMap {
+			currentMap[k] = v
+		}
+		return currentMap, nil
+	}
+
+	key, kind := keys[0], matchKind(kinds[0])
+	switch kind {
+	case indexKey:
+		var slice []interface{}
+		switch v := value.(type) {
+		case nil:
+		case []interface{}:
+			slice = v
+		default:
+			return nil, fmt.Errorf("cannot index a value of type %T", value)
+		}
+
+		i, ok := resolveIndex(key, len(slice))
+		if !ok {
+			if hasWildcard(kinds[1:]) {
+				return value, nil
+			}
+			i, _ = strconv.Atoi(key)
+			if i != len(slice) {
+				return nil, fmt.Errorf("index %s is out of range for a slice of length %d", key, len(slice))
+			}
+			slice = append(slice, nil)
+		}
+
+		child, err := setPath(slice[i], keys[1:], kinds[1:], newValue)
+		if err != nil {
+			return nil, err
+		}
+		slice[i] = child
+		return slice, nil
+	case wildcardKey:
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for k, child := range v {
+				if !matchWildcard(key, k) {
+					continue
+				}
+				child, err := setPath(child, keys[1:], kinds[1:], newValue)
+				if err != nil {
+					return nil, err
+				}
+				v[k] = child
+			}
+		case []interface{}:
+			if key != "*" {
+				return value, nil
+			}
+			for i, child := range v {
+				child, err := setPath(child, keys[1:], kinds[1:], newValue)
+				if err != nil {
+					return nil, err
+				}
+				v[i] = child
+			}
+		}
+		return value, nil
+	default:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			if hasWildcard(kinds[1:]) {
+				return value, nil
+			}
+			m = map[string]interface{}{}
+		}
+		if _, ok := m[key]; !ok && hasWildcard(kinds[1:]) {
+			return value, nil
+		}
+		child, err := setPath(m[key], keys[1:], kinds[1:], newValue)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = child
+		return m, nil
+	}
+}
+
+// deletePath deletes the value at a path, or every value matched by its wildcards.
+// It returns the updated value, and the deleted values as they would be returned by getPath.
+func deletePath(value interface{}, keys []string, kinds []keyKind) (interface{}, interface{}, bool) {
+	key, kind := keys[0], matchKind(kinds[0])
+	last := len(keys) == 1
+
+	switch kind {
+	case indexKey:
+		slice, ok := value.([]interface{})
+		if !ok {
+			return value, nil, false
+		}
+		i, ok := resolveIndex(key, len(slice))
+		if !ok {
+			return value, nil, false
+		}
+		if last {
+			deleted := slice[i]
+			return append(slice[:i:i], slice[i+1:]...), deleted, true
+		}
+		child, deleted, ok := deletePath(slice[i], keys[1:], kinds[1:])
+		if ok {
+			slice[i] = child
+		}
+		return slice, deleted, ok
+	case wildcardKey:
+		switch v := value.(type) {
+		case map[string]interface{}:
+			deleted := make(map[string]interface{})
+			for k, child := range v {
+				if !matchWildcard(key, k) {
+					continue
+				}
+				if last {
+					deleted[k] = child
+					delete(v, k)
+					continue
+				}
+				child, deletedValue, ok := deletePath(child, keys[1:], kinds[1:])
+				if ok {
+					v[k] = child
+					deleted[k] = deletedValue
+				}
+			}
+			if len(deleted) == 0 {
+				return v, nil, false
+			}
+			return v, deleted, true
+		case []interface{}:
+			if key != "*" {
+				return value, nil, false
+			}
+			if last {
+				if len(v) == 0 {
+					return v, nil, false
+				}
+				return []interface{}{}, v, true
+			}
+			deleted := make([]interface{}, 0, len(v))
+			for i, child := range v {
+				child, deletedValue, ok := deletePath(child, keys[1:], kinds[1:])
+				if ok {
+					v[i] = child
+					deleted = append(deleted, deletedValue)
+				}
+			}
+			if len(deleted) == 0 {
+				return v, nil, false
+			}
+			return v, deleted, true
+		default:
+			return value, nil, false
+		}
+	default:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return value, nil, false
+		}
+		child, ok := m[key]
+		if !ok {
+			return value, nil, false
+		}
+		if last {
+			delete(m, key)
+			return m, child, true
+		}
+		child, deleted, ok := deletePath(child, keys[1:], kinds[1:])
+		if ok {
+			m[key] = child
+		}
+		return m, deleted, ok
+	}
+}
+
+// expandPath returns the paths of the values that exist at a path. Wildcards are
+// replaced by the keys and indices that they match, and negative indices are resolved.
+func expandPath(value interface{}, keys []string, kinds []keyKind) []fieldPath {
+	if len(keys) == 0 {
+		return []fieldPath{{keys: []string{}, kinds: []keyKind{}}}
+	}
+
+	var paths []fieldPath
+	prepend := func(key string, kind keyKind, child interface{}) {
+		for _, path := range expandPath(child, keys[1:], kinds[1:]) {
+			paths = append(paths, fieldPath{
+				keys:  append([]string{key}, path.keys...),
+				kinds: append([]keyKind{kind}, path.kinds...),
+			})
+		}
+	}
+
+	key, kind := keys[0], matchKind(kinds[0])
+	switch kind {
+	case indexKey:
+		slice, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		if i, ok := resolveIndex(key, len(slice)); ok {
+			prepend(strconv.Itoa(i), indexKey, slice[i])
+		}
+	case wildcardKey:
+		switch v := value.(type) {
+		case map[string]interface{}:
+			matched := make([]string, 0, len(v))
+			for k := range v {
+				if matchWildcard(key, k) {
+					matched = append(matched, k)
+				}
+			}
+			sort.Strings(matched)
+			for _, k := range matched {
+				prepend(k, literalKey, v[k])
+			}
+		case []interface{}:
+			if key == "*" {
+				for i, child := range v {
+					prepend(strconv.Itoa(i), indexKey, child)
+				}
+			}
+		}
+	default:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if child, ok := m[key]; ok {
+			prepend(key, literalKey, child)
+		}
+	}
+	return paths
+}
+
+// resolveIndex returns the position of an index in a slice of a length
+func resolveIndex(key string, length int) (int, bool) {
+	i, err := strconv.Atoi(key)
+	if err != nil {
+		return 0, false
+	}
+	if i < 0 {
+		i += length
+	}
+	if i < 0 || i >= length {
+		return 0, false
+	}
+	return i, true
+}
+
+// isWildcard returns true if an unbracketed key contains wildcards
+func isWildcard(key string) bool {
+	return strings.ContainsAny(key, "*?")
+}
+
+// matchWildcard returns true if a string matches a pattern, where '*' matches
+// any sequence of characters, and '?' matches any single character.
+func matchWildcard(pattern, s string) bool {
+	p, str := []rune(pattern), []rune(s)
+	pi, si := 0, 0
+	starPattern, starString := -1, 0
+	for si < len(str) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == str[si]):
+			pi++
+			si++
+		case pi < len(p) && p[pi] == '*':
+			starPattern, starString = pi, si
+			pi++
+		case starPattern != -1:
+			// Let the last '*' match one more character
+			pi = starPattern + 1
+			starString++
+			si = starString
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+// toSelectorString returns the string representation of a field with selectors
+func toSelectorString(prefix string, keys []string, kinds []keyKind) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	for i, key := range keys {
+		switch kinds[i] {
+		case indexKey:
+			b.WriteString("[")
+			b.WriteString(key)
+			b.WriteString("]")
+		case wildcardKey:
+			b.WriteString(".")
+			b.WriteString(key)
+		case elementsKey:
+			b.WriteString("[*]")
+		default:
+			if key != "" && !strings.ContainsAny(key, ".[]'\"*?") {
+				b.WriteString(".")
+				b.WriteString(key)
+				continue
+			}
+			quote := "'"
+			if strings.Contains(key, "'") {
+				quote = `"`
+			}
+			b.WriteString("[")
+			b.WriteString(quote)
+			b.WriteString(key)
+			b.WriteString(quote)
+			b.WriteString("]")
+		}
+	}
+	return b.String()
+}
+
+// subKinds returns the kinds of the keys that follow a field's prefix
+func subKinds(kinds []keyKind) []keyKind {
+	if kinds == nil {
+		return nil
+	}
+	return selectorKinds(kinds[1:])
+}
+
+// selectorField is a field with index or wildcard keys. The kinds of its keys are
+// kept here rather than on the field types of each prefix, which only have literal keys.
+type selectorField struct {
+	prefix string
+	keys   []string
+	kinds  []keyKind
+}
+
+// newPrefixedField returns the field of a prefix with the given keys and kinds
+func newPrefixedField(prefix string, keys []string, kinds []keyKind) Field {
+	if kinds != nil {
+		return Field{selectorField{prefix: prefix, keys: keys, kinds: kinds}}
+	}
+
+	switch prefix {
+	case AttributesPrefix:
+		return Field{AttributeField{keys}}
+	case ResourcePrefix:
+		return Field{ResourceField{keys}}
+	default:
+		return Field{BodyField{keys}}
+	}
+}
+
+// root returns the value of an entry that the keys of the field are resolved against
+func (f selectorField) root(entry *Entry) interface{} {
+	switch f.prefix {
+	case AttributesPrefix:
+		if entry.Attributes == nil {
+			return nil
+		}
+		return entry.Attributes
+	case ResourcePrefix:
+		if entry.Resource == nil {
+			return nil
+		}
+		return entry.Resource
+	default:
+		return entry.Body
+	}
+}
+
+// setRoot replaces the value of an entry that the keys of the field are resolved against
+func (f selectorField) setRoot(entry *Entry, value interface{}) {
+	switch f.prefix {
+	case AttributesPrefix:
+		entry.Attributes, _ = value.(map[string]interface{})
+	case ResourcePrefix:
+		entry.Resource, _ = value.(map[string]interface{})
+	default:
+		entry.Body = value
+	}
+}
+
+// String returns the string representation of this field.
+func (f selectorField) String() string {
+	return toSelectorString(f.prefix, f.keys, f.kinds)
+}
+
+// Get will return the value at the field, or the values matched by its wildcards
+func (f selectorField) Get(entry *Entry) (interface{}, bool) {
+	root := f.root(entry)
+	if root == nil && f.prefix != BodyPrefix {
+		return nil, false
+	}
+	return getPath(root, f.keys, f.kinds)
+}
+
+// Set will set a value at the field, or at every value matched by its wildcards
+func (f selectorField) Set(entry *Entry, value interface{}) error {
+	entry.Unshare()
+	root := f.root(entry)
+	if root == nil && f.prefix != BodyPrefix {
+		root = newMap()
+	}
+
+	updated, err := setPath(root, f.keys, f.kinds, value)
+	if err != nil {
+		return err
+	}
+	f.setRoot(entry, updated)
+	return nil
+}
+
+// Delete removes the value at the field, or every value matched by its wildcards
+func (f selectorField) Delete(entry *Entry) (interface{}, bool) {
+	entry.Unshare()
+	root := f.root(entry)
+	if root == nil && f.prefix != BodyPrefix {
+		return nil, false
+	}
+
+	updated, deleted, ok := deletePath(root, f.keys, f.kinds)
+	f.setRoot(entry, updated)
+	return deleted, ok
+}
+
+// expand returns a field for each value on an entry that is matched by the field.
+func (f selectorField) expand(entry *Entry) []Field {
+	paths := expandPath(f.root(entry), f.keys, f.kinds)
+	fields := make([]Field, 0, len(paths))
+	for _, path := range paths {
+		fields = append(fields, newPrefixedField(f.prefix, path.keys, selectorKinds(path.kinds)))
+	}
+	return fields
+}
@@ -0,0 +1,518 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func selectorTestBody() map[string]interface{} {
+	return map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"id": "a", "count": 1},
+			map[string]interface{}{"id": "b", "count": 2},
+			map[string]interface{}{"id": "c"},
+		},
+		"http": map[string]interface{}{
+			"method":      "GET",
+			"status_code": 200,
+			"url":         "/index.html",
+		},
+		"key": "value",
+	}
+}
+
+func TestSplitFieldSelectors(t *testing.T) {
+	cases := []struct {
+		name      string
+		input     string
+		keys      []string
+		kinds     []keyKind
+		expectErr bool
+	}{
+		{"Index", "body.items[0]", []string{"body", "items", "0"}, []keyKind{literalKey, literalKey, indexKey}, false},
+		{"NegativeIndex", "body.items[-1]", []string{"body", "items", "-1"}, []keyKind{literalKey, literalKey, indexKey}, false},
+		{"IndexThenDot", "body.items[0].id", []string{"body", "items", "0", "id"}, []keyKind{literalKey, literalKey, indexKey, literalKey}, false},
+		{"IndexThenIndex", "body.matrix[1][2]", []string{"body", "matrix", "1", "2"}, []keyKind{literalKey, literalKey, indexKey, indexKey}, false},
+		{"BracketedWildcard", "body.items[*].id", []string{"body", "items", "*", "id"}, []keyKind{literalKey, literalKey, elementsKey, literalKey}, false},
+		{"Wildcard", "attributes.http.*", []string{"attributes", "http", "*"}, []keyKind{literalKey, literalKey, wildcardKey}, false},
+		{"Glob", "attributes.http.status_*", []string{"attributes", "http", "status_*"}, []keyKind{literalKey, literalKey, wildcardKey}, false},
+		{"SingleCharacterGlob", "attributes.k?y", []string{"attributes", "k?y"}, []keyKind{literalKey, wildcardKey}, false},
+		{"QuotedIndex", "body['0']", []string{"body", "0"}, nil, false},
+		{"QuotedWildcard", "body['*']", []string{"body", "*"}, nil, false},
+		{"InvalidIndex", "body.items[0a]", nil, nil, true},
+		{"InvalidWildcard", "body.items[*a]", nil, nil, true},
+		{"UnclosedIndex", "body.items[0", nil, nil, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, kinds, err := splitField(tc.input)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.keys, keys)
+			require.Equal(t, tc.kinds, kinds)
+		})
+	}
+}
+
+func TestSelectorFieldString(t *testing.T) {
+	cases := []string{
+		"body.items[0]",
+		"body.items[-1].id",
+		"body.items.*.id",
+		"body.items[*].id",
+		"body[*]",
+		"body['a.b'][0]",
+		"body['*'].items[0]",
+		"body['a[0]'][0]",
+		"attributes.http.*",
+		"attributes.http.status_?ode",
+		"resource[0]",
+	}
+
+	for _, tc := range cases {
+		t.Run(tc, func(t *testing.T) {
+			field, err := NewField(tc)
+			require.NoError(t, err)
+			require.Equal(t, tc, field.String())
+
+			raw, err := json.Marshal(field)
+			require.NoError(t, err)
+			var unmarshalled Field
+			require.NoError(t, json.Unmarshal(raw, &unmarshalled))
+			require.Equal(t, field, unmarshalled)
+		})
+	}
+}
+
+func TestLiteralFieldRoundTrip(t *testing.T) {
+	cases := []struct {
+		field    Field
+		expected string
+	}{
+		{NewBodyField("a*"), "body['a*']"},
+		{NewBodyField("items", "[0]"), "body['items']['[0]']"},
+		{NewAttributeField("status_?ode"), "attributes['status_?ode']"},
+		{NewAttributeField("a]"), "attributes['a]']"},
+		{NewResourceField("host.*"), "resource['host.*']"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expected, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.field.String())
+
+			field, err := NewField(tc.field.String())
+			require.NoError(t, err)
+			require.Equal(t, tc.field, field)
+
+			raw, err := json.Marshal(tc.field)
+			require.NoError(t, err)
+			var fromJSON Field
+			require.NoError(t, json.Unmarshal(raw, &fromJSON))
+			require.Equal(t, tc.field, fromJSON)
+
+			raw, err = yaml.Marshal(tc.field.FieldInterface)
+			require.NoError(t, err)
+			var fromYAML Field
+			require.NoError(t, yaml.UnmarshalStrict(raw, &fromYAML))
+			require.Equal(t, tc.field, fromYAML)
+		})
+	}
+}
+
+func TestLiteralFieldEquality(t *testing.T) {
+	field, err := NewField("body['*'].key")
+	require.NoError(t, err)
+	require.Equal(t, NewBodyField("*", "key"), field)
+}
+
+func TestSelectorFieldGet(t *testing.T) {
+	cases := []struct {
+		field    string
+		expected interface{}
+		found    bool
+	}{
+		{"body.items[0].id", "a", true},
+		{"body.items[-1].id", "c", true},
+		{"body.items[3]", nil, false},
+		{"body.items[-4]", nil, false},
+		{"body.key[0]", nil, false},
+		{"body.items[*].id", []interface{}{"a", "b", "c"}, true},
+		{"body.items[*].count", []interface{}{1, 2}, true},
+		{"body.items[*].missing", nil, false},
+		{"body.http.*", selectorTestBody()["http"], true},
+		{"body.http.status_*", map[string]interface{}{"status_code": 200}, true},
+		{"body.http.?rl", map[string]interface{}{"url": "/index.html"}, true},
+		{"body.http.missing_*", nil, false},
+		{"body.*.id", nil, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.field, func(t *testing.T) {
+			field, err := NewField(tc.field)
+			require.NoError(t, err)
+
+			e := New()
+			e.Body = selectorTestBody()
+			value, ok := e.Get(field)
+			require.Equal(t, tc.found, ok)
+			require.Equal(t, tc.expected, value)
+		})
+	}
+}
+
+func TestSelectorFieldSet(t *testing.T) {
+	cases := []struct {
+		name     string
+		field    string
+		body     interface{}
+		value    interface{}
+		expected interface{}
+	}{
+		{
+			"Index",
+			"body.items[1]",
+			map[string]interface{}{"items": []interface{}{"a", "b"}},
+			"c",
+			map[string]interface{}{"items": []interface{}{"a", "c"}},
+		},
+		{
+			"NegativeIndex",
+			"body.items[-1].id",
+			map[string]interface{}{"items": []interface{}{"a", map[string]interface{}{"id": "b"}}},
+			"c",
+			map[string]interface{}{"items": []interface{}{"a", map[string]interface{}{"id": "c"}}},
+		},
+		{
+			"ExtendSlice",
+			"body.items[1]",
+			map[string]interface{}{"items": []interface{}{"a"}},
+			"c",
+			map[string]interface{}{"items": []interface{}{"a", "c"}},
+		},
+		{
+			"CreateSlice",
+			"body.items[0].id",
+			nil,
+			"a",
+			map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": "a"}}},
+		},
+		{
+			"RootIndex",
+			"body[0]",
+			[]interface{}{"a", "b"},
+			"c",
+			[]interface{}{"c", "b"},
+		},
+		{
+			"Wildcard",
+			"body.items.*.id",
+			map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": "a"}, map[string]interface{}{}}},
+			"x",
+			map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": "x"}, map[string]interface{}{"id": "x"}}},
+		},
+		{
+			"Glob",
+			"body.status_*",
+			map[string]interface{}{"status_code": 200, "status_text": "OK", "url": "/"},
+			"redacted",
+			map[string]interface{}{"status_code": "redacted", "status_text": "redacted", "url": "/"},
+		},
+		{
+			"WildcardNoMatch",
+			"body.nope.*",
+			map[string]interface{}{"items": []interface{}{"a"}},
+			"x",
+			map[string]interface{}{"items": []interface{}{"a"}},
+		},
+		{
+			"WildcardMissingIndex",
+			"body.items[3][*].id",
+			map[string]interface{}{"items": []interface{}{"a"}},
+			"x",
+			map[string]interface{}{"items": []interface{}{"a"}},
+		},
+		{
+			"WildcardMissingParent",
+			"body.nope.items[*]",
+			map[string]interface{}{},
+			"x",
+			map[string]interface{}{},
+		},
+		{
+			"MergeMap",
+			"body.items[0]",
+			map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": "a"}}},
+			map[string]interface{}{"count": 1},
+			map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": "a", "count": 1}}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			field, err := NewField(tc.field)
+			require.NoError(t, err)
+
+			e := New()
+			e.Body = tc.body
+			require.NoError(t, e.Set(field, tc.value))
+			if tc.expected == nil {
+				value, ok := e.Get(field)
+				require.True(t, ok)
+				require.Equal(t, tc.value, value)
+				return
+			}
+			require.Equal(t, tc.expected, e.Body)
+		})
+	}
+}
+
+func TestSelectorFieldSetInvalid(t *testing.T) {
+	cases := []struct {
+		name  string
+		field string
+		body  interface{}
+	}{
+		{"NegativeIndexOutOfRange", "body.items[-2]", map[string]interface{}{"items": []interface{}{"a"}}},
+		{"IndexBeyondEnd", "body.items[2]", map[string]interface{}{"items": []interface{}{"a"}}},
+		{"LargeIndex", "body.items[100000000]", map[string]interface{}{"items": []interface{}{}}},
+		{"IndexString", "body.key[0]", map[string]interface{}{"key": "value"}},
+		{"IndexMap", "body[0]", map[string]interface{}{"key": "value"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			field, err := NewField(tc.field)
+			require.NoError(t, err)
+
+			e := New()
+			e.Body = tc.body
+			require.Error(t, e.Set(field, "value"))
+		})
+	}
+
+	e := New()
+	require.Error(t, e.Set(NewAttributeField(), "value"))
+
+	field, err := NewField("attributes[0]")
+	require.NoError(t, err)
+	require.Error(t, e.Set(field, "value"))
+}
+
+func TestSelectorFieldDelete(t *testing.T) {
+	cases := []struct {
+		name     string
+		field    string
+		deleted  interface{}
+		found    bool
+		expected func() map[string]interface{}
+	}{
+		{
+			"Index",
+			"body.items[1]",
+			map[string]interface{}{"id": "b", "count": 2},
+			true,
+			func() map[string]interface{} {
+				body := selectorTestBody()
+				items := body["items"].([]interface{})
+				body["items"] = []interface{}{items[0], items[2]}
+				return body
+			},
+		},
+		{
+			"NegativeIndexKey",
+			"body.items[-1].id",
+			"c",
+			true,
+			func() map[string]interface{} {
+				body := selectorTestBody()
+				body["items"].([]interface{})[2] = map[string]interface{}{}
+				return body
+			},
+		},
+		{
+			"WildcardKey",
+			"body.items[*].count",
+			[]interface{}{1, 2},
+			true,
+			func() map[string]interface{} {
+				body := selectorTestBody()
+				for _, item := range body["items"].([]interface{}) {
+					delete(item.(map[string]interface{}), "count")
+				}
+				return body
+			},
+		},
+		{
+			"WildcardElements",
+			"body.items[*]",
+			selectorTestBody()["items"],
+			true,
+			func() map[string]interface{} {
+				body := selectorTestBody()
+				body["items"] = []interface{}{}
+				return body
+			},
+		},
+		{
+			"Glob",
+			"body.http.*_code",
+			map[string]interface{}{"status_code": 200},
+			true,
+			func() map[string]interface{} {
+				body := selectorTestBody()
+				delete(body["http"].(map[string]interface{}), "status_code")
+				return body
+			},
+		},
+		{
+			"Missing",
+			"body.items[5]",
+			nil,
+			false,
+			selectorTestBody,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			field, err := NewField(tc.field)
+			require.NoError(t, err)
+
+			e := New()
+			e.Body = selectorTestBody()
+			deleted, ok := e.Delete(field)
+			require.Equal(t, tc.found, ok)
+			require.Equal(t, tc.deleted, deleted)
+			require.Equal(t, tc.expected(), e.Body)
+		})
+	}
+}
+
+func TestSelectorFieldDeleteDoesNotAlias(t *testing.T) {
+	field, err := NewField("body.items[0]")
+	require.NoError(t, err)
+
+	items := []interface{}{"a", "b", "c"}
+	e := New()
+	e.Body = map[string]interface{}{"items": items}
+	_, ok := e.Delete(field)
+	require.True(t, ok)
+	require.Equal(t, []interface{}{"b", "c"}, e.Body.(map[string]interface{})["items"])
+	require.Equal(t, []interface{}{"a", "b", "c"}, items)
+}
+
+func TestSelectorAttributeAndResourceFields(t *testing.T) {
+	e := New()
+	e.Attributes = map[string]interface{}{
+		"http.method": "GET",
+		"http.url":    "/",
+		"host":        "localhost",
+	}
+	e.Resource = map[string]interface{}{
+		"hosts": []interface{}{"a", "b"},
+	}
+
+	field, err := NewField("attributes.http*")
+	require.NoError(t, err)
+	value, ok := e.Get(field)
+	require.True(t, ok)
+	require.Equal(t, map[string]interface{}{"http.method": "GET", "http.url": "/"}, value)
+
+	_, ok = e.Delete(field)
+	require.True(t, ok)
+	require.Equal(t, map[string]interface{}{"host": "localhost"}, e.Attributes)
+
+	field, err = NewField("resource.hosts[-1]")
+	require.NoError(t, err)
+	require.NoError(t, e.Set(field, "c"))
+	value, ok = e.Get(field)
+	require.True(t, ok)
+	require.Equal(t, "c", value)
+
+	_, ok = e.Delete(field)
+	require.True(t, ok)
+	require.Equal(t, map[string]interface{}{"hosts": []interface{}{"a"}}, e.Resource)
+}
+
+func TestFieldExpand(t *testing.T) {
+	cases := []struct {
+		field    string
+		expected []string
+	}{
+		{"body.key", []string{"body.key"}},
+		{"body.missing", []string{}},
+		{"body.items[-1].id", []string{"body.items[2].id"}},
+		{"body.items[*].count", []string{"body.items[0].count", "body.items[1].count"}},
+		{"body.http.*", []string{"body.http.method", "body.http.status_code", "body.http.url"}},
+		{"body.*", []string{"body.http", "body.items", "body.key"}},
+		{"body", []string{"body"}},
+		{"attributes.*", []string{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.field, func(t *testing.T) {
+			field, err := NewField(tc.field)
+			require.NoError(t, err)
+
+			e := New()
+			e.Body = selectorTestBody()
+			expanded := []string{}
+			for _, f := range field.Expand(e) {
+				expanded = append(expanded, f.String())
+				_, ok := e.Get(f)
+				require.True(t, ok)
+			}
+			require.Equal(t, tc.expected, expanded)
+		})
+	}
+}
+
+func TestMatchWildcard(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		s        string
+		expected bool
+	}{
+		{"*", "", true},
+		{"*", "anything", true},
+		{"http.*", "http.method", true},
+		{"http.*", "https", false},
+		{"*_code", "status_code", true},
+		{"*_code", "status_codes", false},
+		{"a*b*c", "aXbYbZc", true},
+		{"a*b*c", "aXbYc", true},
+		{"a*b*c", "aXcYb", false},
+		{"?", "é", true},
+		{"k?y", "key", true},
+		{"k?y", "ky", false},
+		{"literal", "literal", true},
+		{"literal", "literals", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.pattern+"/"+tc.s, func(t *testing.T) {
+			require.Equal(t, tc.expected, matchWildcard(tc.pattern, tc.s))
+		})
+	}
+}
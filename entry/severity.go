@@ -16,6 +16,7 @@ package entry
 
 import (
 	"strconv"
+	"strings"
 )
 
 // Severity indicates the seriousness of a log entry
@@ -97,3 +98,14 @@ func (s Severity) String() string {
 	}
 	return strconv.Itoa(int(s))
 }
+
+// ParseSeverity returns the severity with a name, such as "warn" or "error2", ignoring case
+func ParseSeverity(name string) (Severity, bool) {
+	name = strings.ToLower(name)
+	for s, text := range sevText {
+		if text == name {
+			return s, true
+		}
+	}
+	return Default, false
+}
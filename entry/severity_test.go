@@ -47,3 +47,18 @@ func TestStringer(t *testing.T) {
 	require.Equal(t, "fatal3", Fatal3.String())
 	require.Equal(t, "fatal4", Fatal4.String())
 }
+
+func TestParseSeverity(t *testing.T) {
+	for s := Default; s <= Fatal4; s++ {
+		parsed, ok := ParseSeverity(s.String())
+		require.True(t, ok)
+		require.Equal(t, s, parsed)
+	}
+
+	parsed, ok := ParseSeverity("WARN")
+	require.True(t, ok)
+	require.Equal(t, Warn, parsed)
+
+	_, ok = ParseSeverity("warning")
+	require.False(t, ok)
+}
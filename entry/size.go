@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"fmt"
+	"time"
+)
+
+// Size returns an estimate of the number of bytes used by the values of an entry. It is the sum of
+// the lengths of the strings, byte slices and map keys, and the sizes of the numbers and booleans,
+// in the body, attributes, resource, severity text, scope name, and trace, span and flag IDs.
+// It does not include the timestamps, the severity, or the overhead of Go's data structures.
+func (entry *Entry) Size() int {
+	entry.checkReleased()
+	size := SizeOf(entry.Body) + SizeOf(entry.Attributes) + SizeOf(entry.Resource)
+	size += len(entry.SeverityText) + len(entry.ScopeName)
+	size += len(entry.TraceId) + len(entry.SpanId) + len(entry.TraceFlags)
+	return size
+}
+
+// SizeOf returns an estimate of the number of bytes used by a value, as counted by Entry.Size.
+// Values of types that can not be copied by Entry.Copy are counted by the length of their
+// default string representation.
+func SizeOf(v interface{}) int {
+	switch value := v.(type) {
+	case nil:
+		return 0
+	case string:
+		return len(value)
+	case []byte:
+		return len(value)
+	case bool, int8, uint8:
+		return 1
+	case int16, uint16:
+		return 2
+	case int32, uint32, float32:
+		return 4
+	case int, int64, uint, uint64, float64, time.Time:
+		return 8
+	case map[string]interface{}:
+		size := 0
+		for k, child := range value {
+			size += len(k) + SizeOf(child)
+		}
+		return size
+	case map[string]string:
+		size := 0
+		for k, child := range value {
+			size += len(k) + len(child)
+		}
+		return size
+	case []interface{}:
+		size := 0
+		for _, child := range value {
+			size += SizeOf(child)
+		}
+		return size
+	case []string:
+		size := 0
+		for _, child := range value {
+			size += len(child)
+		}
+		return size
+	case []int:
+		return 8 * len(value)
+	default:
+		return len(fmt.Sprint(value))
+	}
+}
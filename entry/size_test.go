@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeOf(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    interface{}
+		expected int
+	}{
+		{"Nil", nil, 0},
+		{"String", "value", 5},
+		{"Bytes", []byte{1, 2, 3}, 3},
+		{"Bool", true, 1},
+		{"Int", 1, 8},
+		{"Int8", int8(1), 1},
+		{"Int16", int16(1), 2},
+		{"Int32", int32(1), 4},
+		{"Uint64", uint64(1), 8},
+		{"Float32", float32(1), 4},
+		{"Float64", 1.5, 8},
+		{"Time", time.Now(), 8},
+		{"Map", map[string]interface{}{"key": "value", "nested": map[string]interface{}{"a": 1}}, 3 + 5 + 6 + 1 + 8},
+		{"StringMap", map[string]string{"key": "value"}, 8},
+		{"Slice", []interface{}{"a", []byte("bc"), nil}, 3},
+		{"Strings", []string{"a", "bc"}, 3},
+		{"Ints", []int{1, 2}, 16},
+		{"Unknown", struct{ A string }{"abc"}, 5},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, SizeOf(tc.value))
+		})
+	}
+}
+
+func TestEntrySize(t *testing.T) {
+	e := New()
+	require.Equal(t, 0, e.Size())
+
+	e.Body = "message"
+	e.Attributes = map[string]interface{}{"key": "value"}
+	e.Resource = map[string]interface{}{"host": "localhost"}
+	e.SeverityText = "INFO"
+	e.ScopeName = "scope"
+	e.TraceId = make([]byte, 16)
+	e.SpanId = make([]byte, 8)
+	e.TraceFlags = []byte{1}
+	require.Equal(t, 7+8+13+4+5+16+8+1, e.Size())
+}
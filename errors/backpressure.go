@@ -0,0 +1,35 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+)
+
+// ErrBackpressure is returned by an operator that is temporarily unable to
+// accept entries, such as an output whose destination is unavailable.
+// It signals to upstream operators that the entry should be retried later.
+var ErrBackpressure = stderrors.New("operator is applying backpressure")
+
+// NewBackpressureError creates an error that wraps ErrBackpressure with a reason.
+func NewBackpressureError(reason string) error {
+	return fmt.Errorf("%s: %w", reason, ErrBackpressure)
+}
+
+// IsBackpressure returns true if the error, or any error it wraps, is ErrBackpressure.
+func IsBackpressure(err error) bool {
+	return stderrors.Is(err, ErrBackpressure)
+}
@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
+)
+
+func TestIsBackpressure(t *testing.T) {
+	t.Run("Sentinel", func(t *testing.T) {
+		require.True(t, IsBackpressure(ErrBackpressure))
+	})
+
+	t.Run("Reason", func(t *testing.T) {
+		err := NewBackpressureError("queue is full")
+		require.True(t, IsBackpressure(err))
+		require.Equal(t, "queue is full: operator is applying backpressure", err.Error())
+	})
+
+	t.Run("Combined", func(t *testing.T) {
+		err := multierr.Append(NewError("parse failed", ""), NewBackpressureError("queue is full"))
+		require.True(t, IsBackpressure(err))
+	})
+
+	t.Run("Other", func(t *testing.T) {
+		require.False(t, IsBackpressure(nil))
+		require.False(t, IsBackpressure(fmt.Errorf("some error")))
+		require.False(t, IsBackpressure(NewError("some error", "")))
+	})
+}
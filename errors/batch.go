@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+)
+
+// BatchError is returned by an operator that accepted only part of a batch of entries.
+// The entries before Index were accepted, and the remaining entries should be retried.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+// NewBatchError creates an error reporting that a batch was rejected from the given index.
+func NewBatchError(index int, err error) error {
+	return &BatchError{Index: index, Err: err}
+}
+
+// Error will return the error message of the batch error.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch rejected from entry %d: %s", e.Index, e.Err)
+}
+
+// Unwrap returns the error that caused the batch to be rejected.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// BatchAccepted returns the number of entries of a batch that were accepted by an operator,
+// given the size of the batch and the error returned by the operator. Entries are only
+// rejected by backpressure, so a backpressure error without a BatchError rejects all of them.
+func BatchAccepted(err error, size int) int {
+	if !IsBackpressure(err) {
+		return size
+	}
+
+	var batchErr *BatchError
+	if !stderrors.As(err, &batchErr) {
+		return 0
+	}
+	if batchErr.Index > size {
+		return size
+	}
+	return batchErr.Index
+}
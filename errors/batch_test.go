@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
+)
+
+func TestBatchError(t *testing.T) {
+	err := NewBatchError(2, NewBackpressureError("queue is full"))
+	require.True(t, IsBackpressure(err))
+	require.Equal(t, "batch rejected from entry 2: queue is full: operator is applying backpressure", err.Error())
+}
+
+func TestBatchAccepted(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"Nil", nil, 5},
+		{"Other", fmt.Errorf("some error"), 5},
+		{"Backpressure", ErrBackpressure, 0},
+		{"BatchError", NewBatchError(3, ErrBackpressure), 3},
+		{"Combined", multierr.Append(NewError("parse failed", ""), NewBatchError(1, ErrBackpressure)), 1},
+		{"Nested", NewBatchError(2, multierr.Append(ErrBackpressure, NewBatchError(4, ErrBackpressure))), 2},
+		{"OutOfRange", NewBatchError(10, ErrBackpressure), 5},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, BatchAccepted(tc.err, 5))
+		})
+	}
+}
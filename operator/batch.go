@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+
+	"go.uber.org/multierr"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+)
+
+// BatchProcessor is implemented by operators that can process many entries at once,
+// paying the fixed costs of processing, such as taking locks, once per batch.
+type BatchProcessor interface {
+	// ProcessBatch will process a batch of entries from an operator. If only part of the batch
+	// is accepted because of backpressure, it returns an errors.BatchError with the index of
+	// the first rejected entry. Any other backpressure error rejects the whole batch.
+	ProcessBatch(context.Context, []*entry.Entry) error
+}
+
+// ProcessBatch will process a batch of entries with an operator. Operators that do not implement
+// BatchProcessor process the entries one at a time, until an entry is rejected with backpressure.
+func ProcessBatch(ctx context.Context, op Operator, entries []*entry.Entry) error {
+	if batchProcessor, ok := op.(BatchProcessor); ok {
+		return batchProcessor.ProcessBatch(ctx, entries)
+	}
+
+	var errs error
+	for i, e := range entries {
+		err := op.Process(ctx, e)
+		if errors.IsBackpressure(err) {
+			return errors.NewBatchError(i, multierr.Append(errs, err))
+		}
+		errs = multierr.Append(errs, err)
+	}
+	return errs
+}
@@ -19,13 +19,24 @@ package operator
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"go.uber.org/zap"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
 )
 
 // Config is the configuration of an operator
 type Config struct {
 	Builder
+
+	// unknownFields are the unmarshalled fields that are not part of the builder's schema
+	unknownFields []string
+
+	// deprecatedType is the deprecated operator type or alias used by the unmarshalled config
+	deprecatedType string
+	deprecation    string
 }
 
 // Builder is an entity that can build a single operator
@@ -36,8 +47,13 @@ type Builder interface {
 	SetID(string)
 }
 
-// UnmarshalJSON will unmarshal a config from JSON.
+// UnmarshalJSON will unmarshal a config from JSON, using the operator types of the default registry.
 func (c *Config) UnmarshalJSON(bytes []byte) error {
+	return c.UnmarshalJSONWithRegistry(bytes, DefaultRegistry)
+}
+
+// UnmarshalJSONWithRegistry will unmarshal a config from JSON, using the operator types of a registry.
+func (c *Config) UnmarshalJSONWithRegistry(bytes []byte, registry *Registry) error {
 	var typeUnmarshaller struct {
 		Type string
 	}
@@ -50,17 +66,42 @@ func (c *Config) UnmarshalJSON(bytes []byte) error {
 		return fmt.Errorf("missing required field 'type'")
 	}
 
-	builderFunc, ok := DefaultRegistry.Lookup(typeUnmarshaller.Type)
-	if !ok {
-		return fmt.Errorf("unsupported type '%s'", typeUnmarshaller.Type)
+	operatorType, builderFunc, err := registry.resolve(typeUnmarshaller.Type)
+	if err != nil {
+		return err
+	}
+
+	var rawConfig map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(string(bytes)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&rawConfig); err != nil {
+		return err
 	}
 
 	builder := builderFunc()
+	schema := NewSchema(builder)
+	substituted, changed, err := substitute(rawConfig, schema, "")
+	if err != nil {
+		return err
+	}
+
+	// Aliases are replaced by the operator type they refer to
+	if operatorType != typeUnmarshaller.Type {
+		rawConfig["type"] = operatorType
+		changed = true
+	}
+
+	if changed {
+		if bytes, err = json.Marshal(substituted); err != nil {
+			return err
+		}
+	}
+
 	if err := json.Unmarshal(bytes, builder); err != nil {
 		return fmt.Errorf("unmarshal to %s: %s", typeUnmarshaller.Type, err)
 	}
 
-	c.Builder = builder
+	c.setBuilder(builder, schema.UnknownFields(rawConfig), registry, typeUnmarshaller.Type)
 	return nil
 }
 
@@ -69,8 +110,13 @@ func (c Config) MarshalJSON() ([]byte, error) {
 	return json.Marshal(c.Builder)
 }
 
-// UnmarshalYAML will unmarshal a config from YAML.
+// UnmarshalYAML will unmarshal a config from YAML, using the operator types of the default registry.
 func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return c.UnmarshalYAMLWithRegistry(unmarshal, DefaultRegistry)
+}
+
+// UnmarshalYAMLWithRegistry will unmarshal a config from YAML, using the operator types of a registry.
+func (c *Config) UnmarshalYAMLWithRegistry(unmarshal func(interface{}) error, registry *Registry) error {
 	rawConfig := map[string]interface{}{}
 	err := unmarshal(&rawConfig)
 	if err != nil {
@@ -87,21 +133,119 @@ func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return fmt.Errorf("non-string type %T for field 'type'", typeInterface)
 	}
 
-	builderFunc, ok := DefaultRegistry.Lookup(typeString)
-	if !ok {
-		return fmt.Errorf("unsupported type '%s'", typeString)
+	operatorType, builderFunc, err := registry.resolve(typeString)
+	if err != nil {
+		return err
 	}
 
 	builder := builderFunc()
+	schema := NewSchema(builder)
+	substituted, changed, err := substitute(rawConfig, schema, "")
+	if err != nil {
+		return err
+	}
+
+	// Aliases are replaced by the operator type they refer to
+	if operatorType != typeString {
+		rawConfig["type"] = operatorType
+		changed = true
+	}
+
+	// Changed values are decoded from a copy of the config, rather than the original node
+	if changed {
+		unmarshal = func(out interface{}) error {
+			bytes, err := yaml.Marshal(substituted)
+			if err != nil {
+				return err
+			}
+			return yaml.Unmarshal(bytes, out)
+		}
+	}
+
 	if err = unmarshal(builder); err != nil {
 		return fmt.Errorf("unmarshal to %s: %s", typeString, err)
 	}
 
-	c.Builder = builder
+	c.setBuilder(builder, schema.UnknownFields(rawConfig), registry, typeString)
 	return nil
 }
 
+// setBuilder sets the builder of an unmarshalled config, along with any problems found while unmarshalling it
+func (c *Config) setBuilder(builder Builder, unknownFields []string, registry *Registry, typeName string) {
+	c.Builder = builder
+	c.unknownFields = unknownFields
+	c.deprecatedType = ""
+	c.deprecation = ""
+	if message, ok := registry.deprecation(typeName); ok {
+		c.deprecatedType = typeName
+		c.deprecation = message
+	}
+}
+
+// UnmarshalConfigs unmarshals a YAML or JSON list of operator configs, using the operator types of the registry.
+func (r *Registry) UnmarshalConfigs(bytes []byte) ([]Config, error) {
+	var rawConfigs []interface{}
+	if err := yaml.Unmarshal(bytes, &rawConfigs); err != nil {
+		return nil, err
+	}
+
+	configs := make([]Config, 0, len(rawConfigs))
+	for _, rawConfig := range rawConfigs {
+		rawBytes, err := yaml.Marshal(rawConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		var cfg Config
+		if err := yaml.Unmarshal(rawBytes, &registryConfig{config: &cfg, registry: r}); err != nil {
+			return nil, err
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
+
+// registryConfig unmarshals a config from YAML using a registry
+type registryConfig struct {
+	config   *Config
+	registry *Registry
+}
+
+func (r *registryConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return r.config.UnmarshalYAMLWithRegistry(unmarshal, r.registry)
+}
+
 // MarshalYAML will marshal a config to YAML.
 func (c Config) MarshalYAML() (interface{}, error) {
 	return c.Builder, nil
 }
+
+// Build will build an operator, failing if the config contained fields that the operator does not support.
+func (c Config) Build(logger *zap.SugaredLogger) (Operator, error) {
+	if err := c.CheckUnknownFields(); err != nil {
+		return nil, err
+	}
+
+	if c.deprecatedType != "" && logger != nil {
+		logger.Warnw("Operator type is deprecated",
+			"operator_id", c.ID(),
+			"deprecated_type", c.deprecatedType,
+			"message", c.deprecation,
+		)
+	}
+	return c.Builder.Build(logger)
+}
+
+// CheckUnknownFields returns an error if the config contained fields that the operator does not support.
+func (c Config) CheckUnknownFields() error {
+	if len(c.unknownFields) == 0 {
+		return nil
+	}
+	return errors.NewError(
+		"operator config contains unknown fields",
+		"remove the unknown fields, or check them for typos",
+		"operator_id", c.ID(),
+		"operator_type", c.Type(),
+		"fields", strings.Join(c.unknownFields, ", "),
+	)
+}
@@ -163,3 +163,49 @@ func TestMarshalYAML(t *testing.T) {
 	expected := "id: operator\ntype: operator\narray:\n- test\n"
 	require.Equal(t, expected, string(out))
 }
+
+type nestedBuilder struct {
+	FakeBuilder `yaml:",inline"`
+	Nested      struct {
+		Value string `json:"value" yaml:"value"`
+	} `json:"nested" yaml:"nested"`
+}
+
+func TestUnknownFields(t *testing.T) {
+	t.Cleanup(func() {
+		DefaultRegistry = NewRegistry()
+	})
+	Register("nested_operator", func() Builder { return &nestedBuilder{} })
+
+	t.Run("JSON", func(t *testing.T) {
+		raw := `{"type":"nested_operator","arrays":["a"],"nested":{"value":"v","valu":"v"}}`
+		var cfg Config
+		require.NoError(t, json.Unmarshal([]byte(raw), &cfg))
+
+		_, err := cfg.Build(zap.NewNop().Sugar())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "operator config contains unknown fields")
+		require.Equal(t, []string{"arrays", "nested.valu"}, cfg.unknownFields)
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		raw := "type: nested_operator\narrays: [a]\nnested:\n  valu: v\n"
+		var cfg Config
+		require.NoError(t, yaml.Unmarshal([]byte(raw), &cfg))
+
+		_, err := cfg.Build(zap.NewNop().Sugar())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "operator config contains unknown fields")
+		require.Equal(t, []string{"arrays", "nested.valu"}, cfg.unknownFields)
+	})
+
+	t.Run("Known", func(t *testing.T) {
+		raw := "id: op\ntype: nested_operator\narray: [a]\nnested:\n  value: v\n"
+		var cfg Config
+		require.NoError(t, yaml.Unmarshal([]byte(raw), &cfg))
+		require.Empty(t, cfg.unknownFields)
+
+		_, err := cfg.Build(zap.NewNop().Sugar())
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helper
+
+import (
+	"sync"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+)
+
+// AckTracker tracks the positions of the entries emitted by an input, in the
+// order they were read. The committed position is the position of the last
+// entry for which it and all preceding entries have been acknowledged, which
+// makes it safe to persist for at-least-once delivery.
+type AckTracker struct {
+	mux       sync.Mutex
+	pending   []*trackedPosition
+	committed interface{}
+	onCommit  func(position interface{})
+}
+
+type trackedPosition struct {
+	position interface{}
+	acked    bool
+}
+
+// NewAckTracker creates a new tracker starting at the given position. If set,
+// onCommit is called, in order, every time the committed position advances.
+// It must not call the tracker.
+func NewAckTracker(position interface{}, onCommit func(position interface{})) *AckTracker {
+	return &AckTracker{
+		committed: position,
+		onCommit:  onCommit,
+	}
+}
+
+// Track registers an entry that ends at the given position. The position will not be
+// committed until the entry has been acknowledged. The returned function must be
+// called if the entry was not accepted by downstream operators and will be emitted again.
+func (t *AckTracker) Track(e *entry.Entry, position interface{}) (cancel func()) {
+	tracked := &trackedPosition{position: position}
+
+	t.mux.Lock()
+	t.pending = append(t.pending, tracked)
+	t.mux.Unlock()
+
+	e.OnAck(func() { t.ack(tracked) })
+	return func() { t.remove(tracked) }
+}
+
+// Advance registers a position that does not need to be acknowledged, such as the
+// end of a line that was not emitted. It is committed once all preceding entries are.
+func (t *AckTracker) Advance(position interface{}) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.pending = append(t.pending, &trackedPosition{position: position, acked: true})
+	t.commit()
+}
+
+// Committed returns the committed position
+func (t *AckTracker) Committed() interface{} {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.committed
+}
+
+// Pending returns the number of positions waiting to be committed
+func (t *AckTracker) Pending() int {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return len(t.pending)
+}
+
+func (t *AckTracker) ack(tracked *trackedPosition) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	tracked.acked = true
+	t.commit()
+}
+
+func (t *AckTracker) remove(tracked *trackedPosition) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	for i, p := range t.pending {
+		if p == tracked {
+			t.pending = append(t.pending[:i], t.pending[i+1:]...)
+			break
+		}
+	}
+	t.commit()
+}
+
+// commit advances the committed position past all acknowledged positions
+// at the front of the queue. It must be called while holding the lock.
+func (t *AckTracker) commit() {
+	advanced := false
+	for len(t.pending) > 0 && t.pending[0].acked {
+		t.committed = t.pending[0].position
+		t.pending[0] = nil
+		t.pending = t.pending[1:]
+		advanced = true
+	}
+
+	if advanced && t.onCommit != nil {
+		t.onCommit(t.committed)
+	}
+}
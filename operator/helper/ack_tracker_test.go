@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+)
+
+func TestAckTrackerContiguous(t *testing.T) {
+	commits := []interface{}{}
+	tracker := NewAckTracker(0, func(position interface{}) {
+		commits = append(commits, position)
+	})
+
+	first, second, third := entry.New(), entry.New(), entry.New()
+	tracker.Track(first, 10)
+	tracker.Track(second, 20)
+	tracker.Track(third, 30)
+	require.Equal(t, 0, tracker.Committed())
+
+	second.Ack()
+	third.Ack()
+	require.Equal(t, 0, tracker.Committed(), "position should wait for the first entry")
+	require.Equal(t, 3, tracker.Pending())
+
+	first.Ack()
+	require.Equal(t, 30, tracker.Committed())
+	require.Equal(t, 0, tracker.Pending())
+	require.Equal(t, []interface{}{30}, commits)
+}
+
+func TestAckTrackerAdvance(t *testing.T) {
+	tracker := NewAckTracker(0, nil)
+
+	tracked := entry.New()
+	tracker.Track(tracked, 10)
+	tracker.Advance(20)
+	require.Equal(t, 0, tracker.Committed())
+
+	tracked.Ack()
+	require.Equal(t, 20, tracker.Committed())
+
+	tracker.Advance(30)
+	require.Equal(t, 30, tracker.Committed())
+}
+
+func TestAckTrackerCancel(t *testing.T) {
+	tracker := NewAckTracker(0, nil)
+
+	rejected := entry.New()
+	cancel := tracker.Track(rejected, 10)
+	cancel()
+	require.Equal(t, 0, tracker.Pending())
+
+	retried := entry.New()
+	tracker.Track(retried, 10)
+	rejected.Ack()
+	require.Equal(t, 0, tracker.Committed(), "cancelled entry should not be committed")
+
+	retried.Ack()
+	require.Equal(t, 10, tracker.Committed())
+}
+
+func TestAckTrackerCopies(t *testing.T) {
+	tracker := NewAckTracker(0, nil)
+
+	tracked := entry.New()
+	tracker.Track(tracked, 10)
+	copied := tracked.Copy()
+
+	tracked.Ack()
+	require.Equal(t, 0, tracker.Committed())
+
+	copied.Ack()
+	require.Equal(t, 10, tracker.Committed())
+}
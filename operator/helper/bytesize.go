@@ -20,10 +20,17 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 )
 
 type ByteSize int64
 
+// JSONSchema describes a byte size as a number of bytes, or a string with units
+func (h ByteSize) JSONSchema() *operator.Schema {
+	return &operator.Schema{AnyOf: []*operator.Schema{{Type: "integer"}, {Type: "number"}, {Type: "string"}}}
+}
+
 func (h *ByteSize) UnmarshalJSON(raw []byte) error {
 	return h.unmarshalShared(func(i interface{}) error {
 		return json.Unmarshal(raw, &i)
@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"strconv"
 	"time"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 )
 
 // Duration is the representation of a length of time
@@ -43,6 +45,11 @@ func (d Duration) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + d.Duration.String() + `"`), nil
 }
 
+// JSONSchema describes a duration as a string with units, or a number of seconds
+func (d Duration) JSONSchema() *operator.Schema {
+	return &operator.Schema{AnyOf: []*operator.Schema{{Type: "string"}, {Type: "number"}}}
+}
+
 // UnmarshalJSON will unmarshal json as a duration
 func (d *Duration) UnmarshalJSON(raw []byte) error {
 	var v interface{}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helper
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/ast"
+	"github.com/antonmedv/expr/vm"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+)
+
+// ExprCompile compiles an expression that is evaluated with the environment returned by GetExprEnv.
+// Severity names that are compared with `severity`, such as in `severity >= "warn"`, are replaced
+// by the number of the severity, so that severities can be compared by name. Comparing `severity`
+// with a value that is not a severity name or a number, such as an attribute, is an error. The trace
+// fields of an entry are encoded as hex strings only when the expression evaluates them.
+func ExprCompile(input string, options ...expr.Option) (*vm.Program, error) {
+	patcher := &severityPatcher{}
+	options = append([]expr.Option{expr.AllowUndefinedVariables(), expr.Patch(patcher), expr.Patch(traceFieldPatcher{})}, options...)
+	program, err := expr.Compile(input, options...)
+	if err != nil {
+		return nil, err
+	}
+	if patcher.err != nil {
+		return nil, patcher.err
+	}
+	return program, nil
+}
+
+// ExprCompileBool compiles an expression that evaluates to a boolean, as with ExprCompile
+func ExprCompileBool(input string) (*vm.Program, error) {
+	return ExprCompile(input, expr.AsBool())
+}
+
+// severityPatcher replaces the severity names compared with `severity` by their numbers
+type severityPatcher struct {
+	err error
+}
+
+func (p *severityPatcher) Enter(_ *ast.Node) {}
+
+func (p *severityPatcher) Exit(node *ast.Node) {
+	binary, ok := (*node).(*ast.BinaryNode)
+	if !ok {
+		return
+	}
+
+	switch binary.Operator {
+	case "==", "!=", "<", "<=", ">", ">=":
+		if isSeverity(binary.Left) {
+			p.patch(&binary.Right)
+		} else if isSeverity(binary.Right) {
+			p.patch(&binary.Left)
+		}
+	case "in", "not in":
+		if !isSeverity(binary.Left) {
+			return
+		}
+		array, ok := binary.Right.(*ast.ArrayNode)
+		if !ok {
+			p.fail(binary.Right)
+			return
+		}
+		for i := range array.Nodes {
+			p.patch(&array.Nodes[i])
+		}
+	}
+}
+
+// patch replaces a severity name with its number. Values that are not known to be
+// numbers are rejected, since a severity name could only be converted at runtime.
+func (p *severityPatcher) patch(node *ast.Node) {
+	name, ok := (*node).(*ast.StringNode)
+	if !ok {
+		if !isSeverity(*node) && !isNumber((*node).Type()) {
+			p.fail(*node)
+		}
+		return
+	}
+
+	severity, ok := entry.ParseSeverity(name.Value)
+	if !ok {
+		if p.err == nil {
+			p.err = fmt.Errorf("unknown severity '%s'", name.Value)
+		}
+		return
+	}
+	ast.Patch(node, &ast.IntegerNode{Value: int(severity)})
+}
+
+func (p *severityPatcher) fail(node ast.Node) {
+	if p.err == nil {
+		p.err = fmt.Errorf("severity can only be compared with a severity name or number, such as `severity >= \"warn\"`, at line %d, column %d", node.Location().Line, node.Location().Column)
+	}
+}
+
+func isNumber(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func isSeverity(node ast.Node) bool {
+	identifier, ok := node.(*ast.IdentifierNode)
+	return ok && identifier.Value == "severity"
+}
+
+// traceFields maps the trace fields of an entry to the keys of their bytes in an expression environment
+var traceFields = map[string]string{
+	"trace_id":    "#trace_id",
+	"span_id":     "#span_id",
+	"trace_flags": "#trace_flags",
+}
+
+// hexFunction is the key of the function that encodes trace fields in an expression environment
+const hexFunction = "#hex"
+
+// traceFieldPatcher replaces the trace fields of an entry with calls that encode their bytes,
+// so that they are only encoded when they are evaluated
+type traceFieldPatcher struct{}
+
+func (traceFieldPatcher) Enter(_ *ast.Node) {}
+
+func (traceFieldPatcher) Exit(node *ast.Node) {
+	identifier, ok := (*node).(*ast.IdentifierNode)
+	if !ok {
+		return
+	}
+	if key, ok := traceFields[identifier.Value]; ok {
+		ast.Patch(node, &ast.FunctionNode{
+			Name:      hexFunction,
+			Arguments: []ast.Node{&ast.IdentifierNode{Value: key}},
+		})
+	}
+}
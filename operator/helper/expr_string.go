@@ -15,12 +15,12 @@
 package helper
 
 import (
+	"encoding/hex"
 	"fmt"
 	"os"
 	"strings"
 	"sync"
 
-	"github.com/antonmedv/expr"
 	"github.com/antonmedv/expr/vm"
 
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
@@ -90,7 +90,7 @@ func (e ExprStringConfig) Build() (*ExprString, error) {
 
 	subExprs := make([]*vm.Program, 0, len(subExprStrings))
 	for _, subExprString := range subExprStrings {
-		program, err := expr.Compile(subExprString, expr.AllowUndefinedVariables())
+		program, err := ExprCompile(subExprString)
 		if err != nil {
 			return nil, errors.Wrap(err, "compile embedded expression")
 		}
@@ -133,7 +133,8 @@ func (e *ExprString) Render(env map[string]interface{}) (string, error) {
 var envPool = sync.Pool{
 	New: func() interface{} {
 		return map[string]interface{}{
-			"env": os.Getenv,
+			"env":       os.Getenv,
+			hexFunction: hex.EncodeToString,
 		}
 	},
 }
@@ -141,13 +142,25 @@ var envPool = sync.Pool{
 // GetExprEnv returns a map of key/value pairs that can be be used to evaluate an expression
 func GetExprEnv(e *entry.Entry) map[string]interface{} {
 	env := envPool.Get().(map[string]interface{})
+	setExprEnv(env, e)
+	return env
+}
+
+// setExprEnv sets the values of an entry in an expression environment. The trace fields are set
+// as bytes, which are encoded by the expressions that use them, as compiled by ExprCompile.
+func setExprEnv(env map[string]interface{}, e *entry.Entry) {
 	env["$"] = e.Body
 	env["body"] = e.Body
 	env["attributes"] = e.Attributes
 	env["resource"] = e.Resource
 	env["timestamp"] = e.Timestamp
-
-	return env
+	env["observed_timestamp"] = e.ObservedTimestamp
+	env["severity"] = int(e.Severity)
+	env["severity_text"] = e.SeverityText
+	env["scope_name"] = e.ScopeName
+	env["#trace_id"] = e.TraceId
+	env["#span_id"] = e.SpanId
+	env["#trace_flags"] = e.TraceFlags
 }
 
 // PutExprEnv adds a key/value pair that will can be used to evaluate an expression
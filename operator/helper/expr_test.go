@@ -0,0 +1,161 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonmedv/expr/vm"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+)
+
+func TestExprCompileSeverity(t *testing.T) {
+	cases := []struct {
+		expression string
+		severity   entry.Severity
+		expected   bool
+	}{
+		{`severity >= "warn"`, entry.Warn, true},
+		{`severity >= "warn"`, entry.Error, true},
+		{`severity >= "warn"`, entry.Info4, false},
+		{`severity > "WARN"`, entry.Warn, false},
+		{`severity == "error2"`, entry.Error2, true},
+		{`severity != "error2"`, entry.Error2, false},
+		{`"info" <= severity`, entry.Info, true},
+		{`"info" > severity`, entry.Info, false},
+		{`severity < "debug"`, entry.Default, true},
+		{`severity in ["error", "fatal"]`, entry.Fatal, true},
+		{`severity in ["error", "fatal"]`, entry.Warn, false},
+		{`severity not in ["error", "fatal"]`, entry.Warn, true},
+		{`severity >= 17`, entry.Error, true},
+		{`severity == "warn" && body == "message"`, entry.Warn, true},
+		{`severity_text == "warn"`, entry.Warn, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expression+"/"+tc.severity.String(), func(t *testing.T) {
+			program, err := ExprCompileBool(tc.expression)
+			require.NoError(t, err)
+
+			e := entry.New()
+			e.Body = "message"
+			e.Severity = tc.severity
+			env := GetExprEnv(e)
+			defer PutExprEnv(env)
+
+			result, err := vm.Run(program, env)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestExprCompileUnknownSeverity(t *testing.T) {
+	_, err := ExprCompileBool(`severity >= "warning"`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unknown severity 'warning'")
+
+	// Strings that are not compared with the severity are not severity names
+	_, err = ExprCompileBool(`severity_text == "warning"`)
+	require.NoError(t, err)
+}
+
+func TestExprEnv(t *testing.T) {
+	observed := time.Date(2022, time.May, 1, 12, 0, 1, 0, time.UTC)
+	e := entry.New()
+	e.ObservedTimestamp = observed
+	e.Severity = entry.Error
+	e.SeverityText = "E"
+	e.ScopeName = "scope"
+	e.TraceId = []byte{0x48, 0x01, 0x40, 0xf3, 0xd7, 0x70, 0xa5, 0xae, 0x32, 0xf0, 0xa2, 0x2b, 0x6a, 0x81, 0x2c, 0xff}
+	e.SpanId = []byte{0x32, 0xf0, 0xa2, 0x2b, 0x6a, 0x81, 0x2c, 0xff}
+	e.TraceFlags = []byte{0x01}
+
+	env := GetExprEnv(e)
+	defer PutExprEnv(env)
+
+	require.Equal(t, observed, env["observed_timestamp"])
+	require.Equal(t, int(entry.Error), env["severity"])
+	require.Equal(t, "E", env["severity_text"])
+	require.Equal(t, "scope", env["scope_name"])
+	require.Equal(t, "480140f3d770a5ae32f0a22b6a812cff", runExpr(t, `trace_id`, env))
+	require.Equal(t, "32f0a22b6a812cff", runExpr(t, `span_id`, env))
+	require.Equal(t, "01", runExpr(t, `trace_flags`, env))
+	require.Equal(t, true, runExpr(t, `trace_id != "" && scope_name == "scope" && observed_timestamp.Year() == 2022`, env))
+}
+
+func TestExprEnvEmptyTraceContext(t *testing.T) {
+	env := GetExprEnv(entry.New())
+	defer PutExprEnv(env)
+
+	require.Equal(t, "", runExpr(t, `trace_id`, env))
+	require.Equal(t, "", runExpr(t, `span_id`, env))
+	require.Equal(t, "", runExpr(t, `trace_flags`, env))
+}
+
+func TestExprTraceFieldsNotEncoded(t *testing.T) {
+	e := entry.New()
+	e.TraceId = []byte{0x48, 0x01}
+
+	// The trace fields are only encoded by the expressions that use them
+	env := GetExprEnv(e)
+	defer PutExprEnv(env)
+	require.NotContains(t, env, "trace_id")
+	require.Equal(t, "4801", runExpr(t, `trace_id`, env))
+
+	// Keys of maps with the names of trace fields are not replaced
+	env["attributes"] = map[string]interface{}{"trace_id": "attribute"}
+	require.Equal(t, "attribute", runExpr(t, `attributes.trace_id`, env))
+}
+
+func TestExprCompileSeverityComparison(t *testing.T) {
+	cases := []struct {
+		expression string
+		valid      bool
+	}{
+		{`severity >= attributes.level`, false},
+		{`attributes.level < severity`, false},
+		{`severity == severity_text`, false},
+		{`severity in attributes.levels`, false},
+		{`severity in ["error", attributes.level]`, false},
+		{`severity >= 17`, true},
+		{`severity >= 17.5`, true},
+		{`severity >= len(body)`, true},
+		{`severity == severity`, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expression, func(t *testing.T) {
+			_, err := ExprCompileBool(tc.expression)
+			if tc.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "severity can only be compared with a severity name or number")
+		})
+	}
+}
+
+func runExpr(t *testing.T, expression string, env map[string]interface{}) interface{} {
+	program, err := ExprCompile(expression)
+	require.NoError(t, err)
+	result, err := vm.Run(program, env)
+	require.NoError(t, err)
+	return result
+}
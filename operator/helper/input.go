@@ -74,7 +74,7 @@ type InputOperator struct {
 
 // NewEntry will create a new entry using the `attributes`, and `resource` configuration.
 func (i *InputOperator) NewEntry(value interface{}) (*entry.Entry, error) {
-	entry := entry.New()
+	entry := i.WriterOperator.NewEntry()
 	entry.Body = value
 
 	if err := i.Attribute(entry); err != nil {
@@ -22,6 +22,8 @@ import (
 	"time"
 
 	"golang.org/x/text/encoding"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/clock"
 )
 
 // FlusherConfig is a configuration of Flusher helper
@@ -59,6 +61,22 @@ type Flusher struct {
 	// if previousDataLength = 0 - no new data have been received after flush
 	// if previousDataLength > 0 - there is data which has not been flushed yet and it doesn't changed since lastDataChange
 	previousDataLength int
+
+	// clock tells the time of data changes, and uses the system time if it is not set
+	clock clock.Clock
+}
+
+// SetClock sets the clock used to tell the time of data changes
+func (f *Flusher) SetClock(c clock.Clock) {
+	f.clock = c
+	f.lastDataChange = c.Now()
+}
+
+func (f *Flusher) now() time.Time {
+	if f.clock == nil {
+		return time.Now()
+	}
+	return f.clock.Now()
 }
 
 func (f *Flusher) UpdateDataChangeTime(length int) {
@@ -70,7 +88,7 @@ func (f *Flusher) UpdateDataChangeTime(length int) {
 	// update internal properties with new values if data length changed
 	// because it means that data is flowing and being processed
 	f.previousDataLength = length
-	f.lastDataChange = time.Now()
+	f.lastDataChange = f.now()
 }
 
 // Flushed reset data length
@@ -81,7 +99,7 @@ func (f *Flusher) Flushed() {
 // ShouldFlush returns true if data should be forcefully flushed
 func (f *Flusher) ShouldFlush() bool {
 	// Returns true if there is f.forcePeriod after f.lastDataChange and data length is greater than 0
-	return f.forcePeriod > 0 && time.Since(f.lastDataChange) > f.forcePeriod && f.previousDataLength > 0
+	return f.forcePeriod > 0 && f.now().Sub(f.lastDataChange) > f.forcePeriod && f.previousDataLength > 0
 }
 
 func (f *Flusher) SplitFunc(splitFunc bufio.SplitFunc) bufio.SplitFunc {
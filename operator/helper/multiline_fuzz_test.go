@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package helper
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
+)
+
+func FuzzSplitFunc(f *testing.F) {
+	for _, flushAtEOF := range []bool{false, true} {
+		for _, tc := range lineStartSplitFuncCases() {
+			f.Add("utf-8", tc.Pattern, "", tc.Raw, flushAtEOF)
+		}
+		for _, tc := range lineEndSplitFuncCases() {
+			f.Add("utf-8", "", tc.Pattern, tc.Raw, flushAtEOF)
+		}
+		for _, tc := range newlineSplitFuncCases() {
+			f.Add("utf-8", "", "", tc.Raw, flushAtEOF)
+		}
+	}
+
+	const maxLogSize = 1024
+	f.Fuzz(func(t *testing.T, encoding string, lineStartPattern string, lineEndPattern string, data []byte, flushAtEOF bool) {
+		encodingConfig := NewEncodingConfig()
+		encodingConfig.Encoding = encoding
+		enc, err := encodingConfig.Build()
+		if err != nil {
+			return
+		}
+
+		flusherConfig := NewFlusherConfig()
+		flusher := flusherConfig.Build()
+		clock := testutil.NewFakeClock(time.Unix(0, 0))
+		flusher.SetClock(clock)
+
+		multilineConfig := NewMultilineConfig()
+		multilineConfig.LineStartPattern = lineStartPattern
+		multilineConfig.LineEndPattern = lineEndPattern
+		splitFunc, err := multilineConfig.Build(enc.Encoding, flushAtEOF, flusher, maxLogSize)
+		if err != nil {
+			return
+		}
+
+		// Read the data in small pieces, to exercise tokens that span several reads
+		scanner := bufio.NewScanner(iotest.HalfReader(bytes.NewReader(data)))
+		scanner.Buffer(make([]byte, 0, 16), maxLogSize)
+		scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+			clock.Advance(time.Second)
+			return splitFunc(data, atEOF)
+		})
+		for scanner.Scan() {
+			_, _ = enc.Decode(scanner.Bytes())
+		}
+
+		err = scanner.Err()
+		if errors.Is(err, bufio.ErrNegativeAdvance) || errors.Is(err, bufio.ErrAdvanceTooFar) {
+			t.Fatalf("split function returned an invalid advance: %s", err)
+		}
+	})
+}
@@ -27,6 +27,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/unicode"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
 )
 
 type tokenizerTestCase struct {
@@ -56,8 +58,9 @@ func (tc tokenizerTestCase) RunFunc(splitFunc bufio.SplitFunc) func(t *testing.T
 	}
 }
 
-func TestLineStartSplitFunc(t *testing.T) {
-	testCases := []tokenizerTestCase{
+// lineStartSplitFuncCases are also used to seed FuzzSplitFunc
+func lineStartSplitFuncCases() []tokenizerTestCase {
+	return []tokenizerTestCase{
 		{
 			Name:    "OneLogSimple",
 			Pattern: `LOGSTART \d+ `,
@@ -196,8 +199,10 @@ func TestLineStartSplitFunc(t *testing.T) {
 			},
 		},
 	}
+}
 
-	for _, tc := range testCases {
+func TestLineStartSplitFunc(t *testing.T) {
+	for _, tc := range lineStartSplitFuncCases() {
 		cfg := &MultilineConfig{
 			LineStartPattern: tc.Pattern,
 		}
@@ -227,8 +232,9 @@ func TestLineStartSplitFunc(t *testing.T) {
 	})
 }
 
-func TestLineEndSplitFunc(t *testing.T) {
-	testCases := []tokenizerTestCase{
+// lineEndSplitFuncCases are also used to seed FuzzSplitFunc
+func lineEndSplitFuncCases() []tokenizerTestCase {
+	return []tokenizerTestCase{
 		{
 			Name:    "OneLogSimple",
 			Pattern: `LOGEND \d+`,
@@ -362,8 +368,10 @@ func TestLineEndSplitFunc(t *testing.T) {
 			},
 		},
 	}
+}
 
-	for _, tc := range testCases {
+func TestLineEndSplitFunc(t *testing.T) {
+	for _, tc := range lineEndSplitFuncCases() {
 		cfg := &MultilineConfig{
 			LineEndPattern: tc.Pattern,
 		}
@@ -374,8 +382,9 @@ func TestLineEndSplitFunc(t *testing.T) {
 	}
 }
 
-func TestNewlineSplitFunc(t *testing.T) {
-	testCases := []tokenizerTestCase{
+// newlineSplitFuncCases are also used to seed FuzzSplitFunc
+func newlineSplitFuncCases() []tokenizerTestCase {
+	return []tokenizerTestCase{
 		{
 			Name: "OneLogSimple",
 			Raw:  []byte("my log\n"),
@@ -478,8 +487,10 @@ func TestNewlineSplitFunc(t *testing.T) {
 			},
 		},
 	}
+}
 
-	for _, tc := range testCases {
+func TestNewlineSplitFunc(t *testing.T) {
+	for _, tc := range newlineSplitFuncCases() {
 		splitFunc, err := NewNewlineSplitFunc(unicode.UTF8, false)
 		require.NoError(t, err)
 		if tc.Flusher != nil {
@@ -682,3 +693,33 @@ func generatedByteSliceOfLength(length int) []byte {
 	}
 	return newSlice
 }
+
+func TestFlusherClock(t *testing.T) {
+	clock := testutil.NewFakeClock(time.Unix(0, 0))
+	cfg := NewFlusherConfig()
+	flusher := cfg.Build()
+	flusher.SetClock(clock)
+
+	splitFunc, err := NewNewlineSplitFunc(unicode.UTF8, false)
+	require.NoError(t, err)
+	splitFunc = flusher.SplitFunc(splitFunc)
+
+	data := []byte("LOGPART log1")
+	advance, token, err := splitFunc(data, false)
+	require.NoError(t, err)
+	require.Equal(t, 0, advance)
+	require.Nil(t, token)
+
+	// The unterminated log is not flushed before the force period passes
+	clock.Advance(cfg.Period.Raw())
+	advance, token, err = splitFunc(data, false)
+	require.NoError(t, err)
+	require.Equal(t, 0, advance)
+	require.Nil(t, token)
+
+	clock.Advance(time.Millisecond)
+	advance, token, err = splitFunc(data, false)
+	require.NoError(t, err)
+	require.Equal(t, len(data), advance)
+	require.Equal(t, data, token)
+}
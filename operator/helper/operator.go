@@ -17,8 +17,10 @@ package helper
 import (
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-log-collection/clock"
 	"github.com/open-telemetry/opentelemetry-log-collection/errors"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/telemetry"
 )
 
 // NewBasicConfig creates a new basic config
@@ -86,6 +88,8 @@ type BasicOperator struct {
 	OperatorID   string
 	OperatorType string
 	*zap.SugaredLogger
+	telemetry *OperatorTelemetry
+	clock     clock.Clock
 }
 
 // ID will return the operator id.
@@ -106,6 +110,29 @@ func (p *BasicOperator) Logger() *zap.SugaredLogger {
 	return p.SugaredLogger
 }
 
+// SetTelemetry will set the provider used to record the operator's metrics.
+func (p *BasicOperator) SetTelemetry(provider telemetry.Provider) {
+	p.telemetry = NewOperatorTelemetry(provider, p.ID(), p.Type())
+}
+
+// Telemetry returns the operator's metrics, or nil if telemetry has not been set.
+func (p *BasicOperator) Telemetry() *OperatorTelemetry {
+	return p.telemetry
+}
+
+// SetClock will set the clock used by the operator to tell and wait for time.
+func (p *BasicOperator) SetClock(c clock.Clock) {
+	p.clock = c
+}
+
+// Clock returns the operator's clock, which uses the system time if a clock has not been set.
+func (p *BasicOperator) Clock() clock.Clock {
+	if p.clock == nil {
+		return clock.New()
+	}
+	return p.clock
+}
+
 // Start will start the operator.
 func (p *BasicOperator) Start(_ operator.Persister) error {
 	return nil
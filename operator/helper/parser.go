@@ -16,9 +16,11 @@ package helper
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-log-collection/clock"
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
 	"github.com/open-telemetry/opentelemetry-log-collection/errors"
 )
@@ -61,7 +63,9 @@ func (c ParserConfig) Build(logger *zap.SugaredLogger) (ParserOperator, error) {
 		if err := c.TimeParser.Validate(); err != nil {
 			return ParserOperator{}, err
 		}
-		parserOperator.TimeParser = c.TimeParser
+		// The time parser is copied, because its clock is set on the operator
+		timeParser := *c.TimeParser
+		parserOperator.TimeParser = &timeParser
 	}
 
 	if c.SeverityParserConfig != nil {
@@ -97,23 +101,32 @@ type ParserOperator struct {
 	ScopeNameParser *ScopeNameParser
 }
 
+// SetClock will set the clock used by the operator and its time parser.
+func (p *ParserOperator) SetClock(c clock.Clock) {
+	p.TransformerOperator.SetClock(c)
+	if p.TimeParser != nil {
+		p.TimeParser.SetClock(c)
+	}
+}
+
 // ProcessWith will run ParseWith on the entry, then forward the entry on to the next operators.
 func (p *ParserOperator) ProcessWith(ctx context.Context, entry *entry.Entry, parse ParseFunction) error {
 	return p.ProcessWithCallback(ctx, entry, parse, nil)
 }
 
-func (p *ParserOperator) ProcessWithCallback(ctx context.Context, entry *entry.Entry, parse ParseFunction, cb func(*entry.Entry) error) error {
+func (p *ParserOperator) ProcessWithCallback(ctx context.Context, entry *entry.Entry, parse ParseFunction, cb func(*entry.Entry) error) (err error) {
+	defer func() { p.telemetry.ReceivedUnlessRejected(ctx, err) }()
+
 	// Short circuit if the "if" condition does not match
 	skip, err := p.Skip(ctx, entry)
 	if err != nil {
 		return p.HandleEntryError(ctx, entry, err)
 	}
 	if skip {
-		p.Write(ctx, entry)
-		return nil
+		return p.Write(ctx, entry)
 	}
 
-	if err := p.ParseWith(ctx, entry, parse); err != nil {
+	if err = p.ParseWith(ctx, entry, parse); err != nil {
 		return err
 	}
 	if cb != nil {
@@ -123,29 +136,45 @@ func (p *ParserOperator) ProcessWithCallback(ctx context.Context, entry *entry.E
 		}
 	}
 
-	p.Write(ctx, entry)
-	return nil
+	return p.Write(ctx, entry)
+}
+
+// ProcessBatchWith will parse a batch of entries, and then forward them on to the next operators as a single batch.
+func (p *ParserOperator) ProcessBatchWith(ctx context.Context, entries []*entry.Entry, parse ParseFunction) error {
+	return p.TransformerOperator.ProcessBatchWith(ctx, entries, func(e *entry.Entry) error {
+		return p.parseWith(e, parse)
+	})
 }
 
 // ParseWith will process an entry's field with a parser function.
 func (p *ParserOperator) ParseWith(ctx context.Context, entry *entry.Entry, parse ParseFunction) error {
+	start := time.Now()
+	err := p.parseWith(entry, parse)
+	p.telemetry.RecordDuration(ctx, start)
+	if err != nil {
+		return p.HandleEntryError(ctx, entry, err)
+	}
+	return nil
+}
+
+// parseWith parses an entry's field, returning any error that should be handled.
+func (p *ParserOperator) parseWith(entry *entry.Entry, parse ParseFunction) error {
 	value, ok := entry.Get(p.ParseFrom)
 	if !ok {
-		err := errors.NewError(
+		return errors.NewError(
 			"Entry is missing the expected parse_from field.",
 			"Ensure that all incoming entries contain the parse_from field.",
 			"parse_from", p.ParseFrom.String(),
 		)
-		return p.HandleEntryError(ctx, entry, err)
 	}
 
 	newValue, err := parse(value)
 	if err != nil {
-		return p.HandleEntryError(ctx, entry, err)
+		return err
 	}
 
 	if err := entry.Set(p.ParseTo, newValue); err != nil {
-		return p.HandleEntryError(ctx, entry, errors.Wrap(err, "set parse_to"))
+		return errors.Wrap(err, "set parse_to")
 	}
 
 	var timeParseErr error
@@ -170,16 +199,16 @@ func (p *ParserOperator) ParseWith(ctx context.Context, entry *entry.Entry, pars
 
 	// Handle time or severity parsing errors after attempting to parse both
 	if timeParseErr != nil {
-		return p.HandleEntryError(ctx, entry, errors.Wrap(timeParseErr, "time parser"))
+		return errors.Wrap(timeParseErr, "time parser")
 	}
 	if severityParseErr != nil {
-		return p.HandleEntryError(ctx, entry, errors.Wrap(severityParseErr, "severity parser"))
+		return errors.Wrap(severityParseErr, "severity parser")
 	}
 	if traceParseErr != nil {
-		return p.HandleEntryError(ctx, entry, errors.Wrap(traceParseErr, "trace parser"))
+		return errors.Wrap(traceParseErr, "trace parser")
 	}
 	if scopeNameParserErr != nil {
-		return p.HandleEntryError(ctx, entry, errors.Wrap(scopeNameParserErr, "scope_name parser"))
+		return errors.Wrap(scopeNameParserErr, "scope_name parser")
 	}
 	return nil
 }
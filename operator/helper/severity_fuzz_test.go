@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
+)
+
+func FuzzSeverityParser(f *testing.F) {
+	for _, tc := range severityParserCases() {
+		switch sample := tc.sample.(type) {
+		case string:
+			f.Add(sample, float64(0))
+		case []byte:
+			f.Add(string(sample), float64(0))
+		case int:
+			f.Add("", float64(sample))
+		case float64:
+			f.Add("", sample)
+		}
+	}
+
+	parseFrom := entry.NewBodyField()
+	config := NewSeverityParserConfig()
+	config.ParseFrom = &parseFrom
+	config.Preset = "http"
+	config.Mapping = map[interface{}]interface{}{
+		"error": []interface{}{"e", map[interface{}]interface{}{"min": 1, "max": 9}},
+	}
+	parser, err := config.Build(testutil.Logger(f))
+	require.NoError(f, err)
+
+	f.Fuzz(func(t *testing.T, text string, number float64) {
+		for _, value := range []interface{}{text, []byte(text), number, int(number)} {
+			e := entry.New()
+			e.Body = value
+			_ = parser.Parse(e)
+		}
+	})
+}
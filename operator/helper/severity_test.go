@@ -142,7 +142,8 @@ func otlpSevCases() []severityTestCase {
 	return cases
 }
 
-func TestSeverityParser(t *testing.T) {
+// severityParserCases are also used to seed FuzzSeverityParser
+func severityParserCases() []severityTestCase {
 	allTheThingsMap := map[interface{}]interface{}{
 		"info":   "3xx",
 		"error3": "4xx",
@@ -365,11 +366,14 @@ func TestSeverityParser(t *testing.T) {
 
 	testCases = append(testCases, otlpSevCases()...)
 	testCases = append(testCases, validMappingKeyCases()...)
+	return testCases
+}
 
+func TestSeverityParser(t *testing.T) {
 	rootField := entry.NewBodyField()
 	someField := entry.NewBodyField("some_field")
 
-	for _, tc := range testCases {
+	for _, tc := range severityParserCases() {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Run("root", tc.run(rootField))
 			t.Run("non-root", tc.run(someField))
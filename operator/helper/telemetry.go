@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helper
+
+import (
+	"context"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+	"github.com/open-telemetry/opentelemetry-log-collection/telemetry"
+)
+
+const (
+	// EntriesReceivedMetric counts the entries received by an operator.
+	EntriesReceivedMetric = "operator.entries.received"
+	// EntriesEmittedMetric counts the entries written by an operator to its outputs.
+	EntriesEmittedMetric = "operator.entries.emitted"
+	// EntriesDroppedMetric counts the entries discarded by an operator.
+	EntriesDroppedMetric = "operator.entries.dropped"
+	// EntriesFailedMetric counts the entries an operator failed to process.
+	EntriesFailedMetric = "operator.entries.failed"
+	// ProcessDurationMetric records the time an operator spent processing an entry.
+	ProcessDurationMetric = "operator.process.duration"
+)
+
+// OperatorTelemetry records the metrics of a single operator.
+// All methods are safe to call on a nil OperatorTelemetry.
+type OperatorTelemetry struct {
+	attrs    []telemetry.Attribute
+	received telemetry.Counter
+	emitted  telemetry.Counter
+	dropped  telemetry.Counter
+	failed   telemetry.Counter
+	duration telemetry.Histogram
+}
+
+// NewOperatorTelemetry creates the instruments of an operator.
+func NewOperatorTelemetry(provider telemetry.Provider, operatorID, operatorType string) *OperatorTelemetry {
+	return &OperatorTelemetry{
+		attrs: []telemetry.Attribute{
+			telemetry.String("operator_id", operatorID),
+			telemetry.String("operator_type", operatorType),
+		},
+		received: provider.Counter(EntriesReceivedMetric, "Number of entries received by the operator"),
+		emitted:  provider.Counter(EntriesEmittedMetric, "Number of entries emitted by the operator"),
+		dropped:  provider.Counter(EntriesDroppedMetric, "Number of entries dropped by the operator"),
+		failed:   provider.Counter(EntriesFailedMetric, "Number of entries the operator failed to process"),
+		duration: provider.Histogram(ProcessDurationMetric, "Time spent processing an entry", telemetry.Milliseconds),
+	}
+}
+
+// Received records that an entry was received.
+func (t *OperatorTelemetry) Received(ctx context.Context) {
+	if t != nil {
+		t.received.Add(ctx, 1, t.attrs...)
+	}
+}
+
+// ReceivedUnlessRejected records that an entry was received, unless it was rejected with
+// backpressure, since it is then received again when it is retried.
+func (t *OperatorTelemetry) ReceivedUnlessRejected(ctx context.Context, err error) {
+	t.ReceivedBatchUnlessRejected(ctx, 1, err)
+}
+
+// ReceivedBatchUnlessRejected records the entries of a batch that were received,
+// except for those rejected with backpressure.
+func (t *OperatorTelemetry) ReceivedBatchUnlessRejected(ctx context.Context, size int, err error) {
+	if accepted := errors.BatchAccepted(err, size); t != nil && accepted > 0 {
+		t.received.Add(ctx, int64(accepted), t.attrs...)
+	}
+}
+
+// Emitted records that an entry was written to the outputs.
+func (t *OperatorTelemetry) Emitted(ctx context.Context) {
+	if t != nil {
+		t.emitted.Add(ctx, 1, t.attrs...)
+	}
+}
+
+// ReceivedBatch records that a batch of entries was received.
+func (t *OperatorTelemetry) ReceivedBatch(ctx context.Context, size int) {
+	if t != nil {
+		t.received.Add(ctx, int64(size), t.attrs...)
+	}
+}
+
+// EmittedBatch records that a batch of entries was written to the outputs.
+func (t *OperatorTelemetry) EmittedBatch(ctx context.Context, size int) {
+	if t != nil {
+		t.emitted.Add(ctx, int64(size), t.attrs...)
+	}
+}
+
+// Dropped records that an entry was discarded.
+func (t *OperatorTelemetry) Dropped(ctx context.Context) {
+	if t != nil {
+		t.dropped.Add(ctx, 1, t.attrs...)
+	}
+}
+
+// Failed records that an entry could not be processed.
+func (t *OperatorTelemetry) Failed(ctx context.Context) {
+	if t != nil {
+		t.failed.Add(ctx, 1, t.attrs...)
+	}
+}
+
+// RecordDuration records the time elapsed since start.
+func (t *OperatorTelemetry) RecordDuration(ctx context.Context, start time.Time) {
+	if t != nil {
+		t.duration.Record(ctx, float64(time.Since(start))/float64(time.Millisecond), t.attrs...)
+	}
+}
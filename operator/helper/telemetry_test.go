@@ -0,0 +1,193 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helper
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/telemetry"
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
+)
+
+var testAttrs = []telemetry.Attribute{
+	telemetry.String("operator_id", "test-id"),
+	telemetry.String("operator_type", "test-type"),
+}
+
+func TestOperatorTelemetryNil(t *testing.T) {
+	var metrics *OperatorTelemetry
+	ctx := context.Background()
+	require.NotPanics(t, func() {
+		metrics.Received(ctx)
+		metrics.ReceivedUnlessRejected(ctx, nil)
+		metrics.ReceivedBatchUnlessRejected(ctx, 2, nil)
+		metrics.Emitted(ctx)
+		metrics.Dropped(ctx)
+		metrics.Failed(ctx)
+		metrics.RecordDuration(ctx, time.Now())
+	})
+}
+
+func TestTransformerTelemetry(t *testing.T) {
+	cases := []struct {
+		name     string
+		onError  string
+		fail     bool
+		received int64
+		emitted  int64
+		dropped  int64
+		failed   int64
+	}{
+		{"Success", SendOnError, false, 1, 1, 0, 0},
+		{"SendOnError", SendOnError, true, 1, 1, 0, 1},
+		{"DropOnError", DropOnError, true, 1, 0, 1, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := telemetry.NewMemoryProvider()
+			writer, _ := writerWithFakeOut(t)
+			writer.SetTelemetry(provider)
+			transformer := TransformerOperator{
+				WriterOperator: *writer,
+				OnError:        tc.onError,
+			}
+
+			transform := func(e *entry.Entry) error {
+				if tc.fail {
+					return fmt.Errorf("failure")
+				}
+				return nil
+			}
+			_ = transformer.ProcessWith(context.Background(), entry.New(), transform)
+
+			require.Equal(t, tc.received, provider.CounterValue(EntriesReceivedMetric, testAttrs...))
+			require.Equal(t, tc.emitted, provider.CounterValue(EntriesEmittedMetric, testAttrs...))
+			require.Equal(t, tc.dropped, provider.CounterValue(EntriesDroppedMetric, testAttrs...))
+			require.Equal(t, tc.failed, provider.CounterValue(EntriesFailedMetric, testAttrs...))
+			require.Len(t, provider.HistogramValues(ProcessDurationMetric, testAttrs...), 1)
+		})
+	}
+}
+
+func TestParserTelemetry(t *testing.T) {
+	provider := telemetry.NewMemoryProvider()
+	writer, fakeOut := writerWithFakeOut(t)
+	writer.SetTelemetry(provider)
+	parser := ParserOperator{
+		TransformerOperator: TransformerOperator{
+			WriterOperator: *writer,
+			OnError:        DropOnError,
+		},
+		ParseFrom: entry.NewBodyField(),
+		ParseTo:   entry.NewBodyField(),
+	}
+	parse := func(i interface{}) (interface{}, error) {
+		if i == "invalid" {
+			return nil, fmt.Errorf("parse failure")
+		}
+		return i, nil
+	}
+
+	ctx := context.Background()
+	valid := entry.New()
+	valid.Body = "valid"
+	require.NoError(t, parser.ProcessWith(ctx, valid, parse))
+	fakeOut.ExpectEntry(t, valid)
+
+	invalid := entry.New()
+	invalid.Body = "invalid"
+	require.Error(t, parser.ProcessWith(ctx, invalid, parse))
+
+	require.Equal(t, int64(2), provider.CounterValue(EntriesReceivedMetric, testAttrs...))
+	require.Equal(t, int64(1), provider.CounterValue(EntriesEmittedMetric, testAttrs...))
+	require.Equal(t, int64(1), provider.CounterValue(EntriesDroppedMetric, testAttrs...))
+	require.Equal(t, int64(1), provider.CounterValue(EntriesFailedMetric, testAttrs...))
+	require.Len(t, provider.HistogramValues(ProcessDurationMetric, testAttrs...), 2)
+}
+
+// telemetryTransformer creates a transformer that records telemetry and writes to outputs
+func telemetryTransformer(t *testing.T, provider telemetry.Provider, outputs ...operator.Operator) *TransformerOperator {
+	transformer := &TransformerOperator{
+		WriterOperator: WriterOperator{
+			BasicOperator: BasicOperator{
+				OperatorID:    "test-id",
+				OperatorType:  "test-type",
+				SugaredLogger: testutil.Logger(t),
+			},
+			OutputOperators: outputs,
+		},
+		OnError: SendOnError,
+	}
+	transformer.SetTelemetry(provider)
+	return transformer
+}
+
+func noopTransform(*entry.Entry) error {
+	return nil
+}
+
+func TestTransformerTelemetryBackpressure(t *testing.T) {
+	provider := telemetry.NewMemoryProvider()
+	output := &testutil.Operator{}
+	output.On("Process", mock.Anything, mock.Anything).Return(errors.NewBackpressureError("queue is full")).Once()
+	output.On("Process", mock.Anything, mock.Anything).Return(nil)
+	transformer := telemetryTransformer(t, provider, output)
+
+	// The rejected entry is counted once, when its retry is accepted
+	e := entry.New()
+	require.True(t, errors.IsBackpressure(transformer.ProcessWith(context.Background(), e, noopTransform)))
+	require.Equal(t, int64(0), provider.CounterValue(EntriesReceivedMetric, testAttrs...))
+	require.Equal(t, int64(0), provider.CounterValue(EntriesEmittedMetric, testAttrs...))
+
+	require.NoError(t, transformer.ProcessWith(context.Background(), e, noopTransform))
+	require.Equal(t, int64(1), provider.CounterValue(EntriesReceivedMetric, testAttrs...))
+	require.Equal(t, int64(1), provider.CounterValue(EntriesEmittedMetric, testAttrs...))
+}
+
+func TestTransformerTelemetryBatchBackpressure(t *testing.T) {
+	provider := telemetry.NewMemoryProvider()
+	output := &testutil.Operator{}
+	output.On("Process", mock.Anything, mock.Anything).Return(nil).Once()
+	output.On("Process", mock.Anything, mock.Anything).Return(errors.NewBackpressureError("queue is full"))
+	transformer := telemetryTransformer(t, provider, output)
+
+	// Only the accepted part of the batch is counted
+	entries := []*entry.Entry{entry.New(), entry.New(), entry.New()}
+	err := transformer.ProcessBatchWith(context.Background(), entries, noopTransform)
+	require.Equal(t, 1, errors.BatchAccepted(err, len(entries)))
+	require.Equal(t, int64(1), provider.CounterValue(EntriesReceivedMetric, testAttrs...))
+	require.Equal(t, int64(1), provider.CounterValue(EntriesEmittedMetric, testAttrs...))
+}
+
+func TestTransformerTelemetryNoOutputs(t *testing.T) {
+	provider := telemetry.NewMemoryProvider()
+	transformer := telemetryTransformer(t, provider)
+
+	// Entries are not emitted when there are no outputs, whether or not they are written as a batch
+	require.NoError(t, transformer.ProcessWith(context.Background(), entry.New(), noopTransform))
+	require.NoError(t, transformer.ProcessBatchWith(context.Background(), []*entry.Entry{entry.New(), entry.New()}, noopTransform))
+	require.Equal(t, int64(3), provider.CounterValue(EntriesReceivedMetric, testAttrs...))
+	require.Equal(t, int64(0), provider.CounterValue(EntriesEmittedMetric, testAttrs...))
+}
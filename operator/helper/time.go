@@ -22,6 +22,7 @@ import (
 
 	strptime "github.com/observiq/ctimefmt"
 
+	"github.com/open-telemetry/opentelemetry-log-collection/clock"
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
 	"github.com/open-telemetry/opentelemetry-log-collection/errors"
 )
@@ -49,10 +50,16 @@ func NewTimeParser() TimeParser {
 type TimeParser struct {
 	ParseFrom  *entry.Field `mapstructure:"parse_from,omitempty"  json:"parse_from,omitempty"  yaml:"parse_from,omitempty"`
 	Layout     string       `mapstructure:"layout,omitempty"      json:"layout,omitempty"      yaml:"layout,omitempty"`
-	LayoutType string       `mapstructure:"layout_type,omitempty" json:"layout_type,omitempty" yaml:"layout_type,omitempty"`
+	LayoutType string       `mapstructure:"layout_type,omitempty" json:"layout_type,omitempty" yaml:"layout_type,omitempty" jsonschema:"enum=strptime|gotime|epoch"`
 	Location   string       `mapstructure:"location,omitempty"    json:"location,omitempty"    yaml:"location,omitempty"`
 
 	location *time.Location
+	clock    clock.Clock
+}
+
+// SetClock sets the clock used to tell the year of timestamps that do not include one.
+func (t *TimeParser) SetClock(c clock.Clock) {
+	t.clock = c
 }
 
 // IsZero returns true if the TimeParser is not a valid config
@@ -146,19 +153,19 @@ func (t *TimeParser) Parse(entry *entry.Entry) error {
 		if !ok {
 			return fmt.Errorf("native time.Time field required, but found %v of type %T", value, value)
 		}
-		entry.Timestamp = setTimestampYear(timeValue)
+		entry.Timestamp = setTimestampYear(timeValue, t.clock)
 	case GotimeKey:
 		timeValue, err := t.parseGotime(value)
 		if err != nil {
 			return err
 		}
-		entry.Timestamp = setTimestampYear(timeValue)
+		entry.Timestamp = setTimestampYear(timeValue, t.clock)
 	case EpochKey:
 		timeValue, err := t.parseEpochTime(value)
 		if err != nil {
 			return err
 		}
-		entry.Timestamp = setTimestampYear(timeValue)
+		entry.Timestamp = setTimestampYear(timeValue, t.clock)
 	default:
 		return fmt.Errorf("unsupported layout type: %s", t.LayoutType)
 	}
@@ -275,13 +282,16 @@ var toTime = map[string]toTimeFunc{
 }
 var subsecToNs = map[string]int64{"s.ms": 1e6, "s.us": 1e3, "s.ns": 1}
 
-// setTimestampYear sets the year of a timestamp to the current year.
+// setTimestampYear sets the year of a timestamp to the current year of a clock.
 // This is needed because year is missing from some time formats, such as rfc3164.
-func setTimestampYear(t time.Time) time.Time {
+func setTimestampYear(t time.Time, clk clock.Clock) time.Time {
 	if t.Year() > 0 {
 		return t
 	}
-	n := now()
+	if clk == nil {
+		clk = clock.New()
+	}
+	n := clk.Now()
 	d := time.Date(n.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
 	// If the timestamp would be more than 7 days in the future using this year,
 	// assume it's from last year.
@@ -290,6 +300,3 @@ func setTimestampYear(t time.Time) time.Time {
 	}
 	return d
 }
-
-// Allows tests to override with deterministic value
-var now = time.Now
@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package helper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
+)
+
+func FuzzTimeParser(f *testing.F) {
+	mst, err := time.LoadLocation("MST")
+	require.NoError(f, err)
+	hst, err := time.LoadLocation("HST")
+	require.NoError(f, err)
+
+	now := testutil.NewFakeClock(time.Date(2020, 12, 16, 17, 0, 0, 0, mst)).Now
+	for _, tc := range timeParserCases(now, mst, hst) {
+		if sample, ok := tc.sample.(string); ok {
+			f.Add(GotimeKey, tc.gotimeLayout, sample)
+			f.Add(StrptimeKey, tc.strptimeLayout, sample)
+		}
+	}
+	for _, tc := range timeEpochCases() {
+		switch sample := tc.sample.(type) {
+		case string:
+			f.Add(EpochKey, tc.layout, sample)
+		case []byte:
+			f.Add(EpochKey, tc.layout, string(sample))
+		}
+	}
+	for _, tc := range timeErrorCases() {
+		if sample, ok := tc.sample.(string); ok {
+			f.Add(tc.layoutType, tc.layout, sample)
+		}
+	}
+
+	clock := testutil.NewFakeClock(time.Date(2022, time.March, 1, 0, 0, 0, 0, time.UTC))
+	f.Fuzz(func(t *testing.T, layoutType string, layout string, value string) {
+		parseFrom := entry.NewBodyField()
+		parser := NewTimeParser()
+		parser.ParseFrom = &parseFrom
+		parser.LayoutType = layoutType
+		parser.Layout = layout
+		parser.Location = "UTC"
+		if err := parser.Validate(); err != nil {
+			return
+		}
+		parser.SetClock(clock)
+
+		e := entry.New()
+		e.Body = value
+		_ = parser.Parse(e)
+	})
+}
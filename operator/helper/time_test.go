@@ -26,49 +26,42 @@ import (
 	yaml "gopkg.in/yaml.v2"
 
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
 )
 
 func Test_setTimestampYear(t *testing.T) {
 	t.Run("Normal", func(t *testing.T) {
-		now = func() time.Time {
-			return time.Date(2020, 06, 16, 3, 31, 34, 525, time.UTC)
-		}
+		clock := testutil.NewFakeClock(time.Date(2020, 06, 16, 3, 31, 34, 525, time.UTC))
 
 		noYear := time.Date(0, 06, 16, 3, 31, 34, 525, time.UTC)
-		yearAdded := setTimestampYear(noYear)
+		yearAdded := setTimestampYear(noYear, clock)
 		expected := time.Date(2020, 06, 16, 3, 31, 34, 525, time.UTC)
 		require.Equal(t, expected, yearAdded)
 	})
 
 	t.Run("FutureOneDay", func(t *testing.T) {
-		now = func() time.Time {
-			return time.Date(2020, 01, 16, 3, 31, 34, 525, time.UTC)
-		}
+		clock := testutil.NewFakeClock(time.Date(2020, 01, 16, 3, 31, 34, 525, time.UTC))
 
 		noYear := time.Date(0, 01, 17, 3, 31, 34, 525, time.UTC)
-		yearAdded := setTimestampYear(noYear)
+		yearAdded := setTimestampYear(noYear, clock)
 		expected := time.Date(2020, 01, 17, 3, 31, 34, 525, time.UTC)
 		require.Equal(t, expected, yearAdded)
 	})
 
 	t.Run("FutureEightDays", func(t *testing.T) {
-		now = func() time.Time {
-			return time.Date(2020, 01, 16, 3, 31, 34, 525, time.UTC)
-		}
+		clock := testutil.NewFakeClock(time.Date(2020, 01, 16, 3, 31, 34, 525, time.UTC))
 
 		noYear := time.Date(0, 01, 24, 3, 31, 34, 525, time.UTC)
-		yearAdded := setTimestampYear(noYear)
+		yearAdded := setTimestampYear(noYear, clock)
 		expected := time.Date(2019, 01, 24, 3, 31, 34, 525, time.UTC)
 		require.Equal(t, expected, yearAdded)
 	})
 
 	t.Run("RolloverYear", func(t *testing.T) {
-		now = func() time.Time {
-			return time.Date(2020, 01, 01, 3, 31, 34, 525, time.UTC)
-		}
+		clock := testutil.NewFakeClock(time.Date(2020, 01, 01, 3, 31, 34, 525, time.UTC))
 
 		noYear := time.Date(0, 12, 31, 3, 31, 34, 525, time.UTC)
-		yearAdded := setTimestampYear(noYear)
+		yearAdded := setTimestampYear(noYear, clock)
 		expected := time.Date(2019, 12, 31, 3, 31, 34, 525, time.UTC)
 		require.Equal(t, expected, yearAdded)
 	})
@@ -79,26 +72,18 @@ func TestIsZero(t *testing.T) {
 	require.False(t, (&TimeParser{Layout: "strptime"}).IsZero())
 }
 
-func TestTimeParser(t *testing.T) {
-	// Mountain Standard Time
-	mst, err := time.LoadLocation("MST")
-	require.NoError(t, err)
-
-	// Hawaiian Standard Time
-	hst, err := time.LoadLocation("HST")
-	require.NoError(t, err)
-
-	// override with deterministic value
-	now = func() time.Time { return time.Date(2020, 12, 16, 17, 0, 0, 0, mst) }
+type timeParserCase struct {
+	name           string
+	sample         interface{}
+	expected       time.Time
+	gotimeLayout   string
+	strptimeLayout string
+	location       string
+}
 
-	testCases := []struct {
-		name           string
-		sample         interface{}
-		expected       time.Time
-		gotimeLayout   string
-		strptimeLayout string
-		location       string
-	}{
+// timeParserCases are also used to seed FuzzTimeParser
+func timeParserCases(now func() time.Time, mst, hst *time.Location) []timeParserCase {
+	return []timeParserCase{
 		{
 			name:           "unix-utc",
 			sample:         "Mon Jan 2 15:04:05 UTC 2006",
@@ -322,35 +307,56 @@ func TestTimeParser(t *testing.T) {
 			strptimeLayout: "%Y-%m-%dT%H:%M:%S.%LZ",
 		},
 	}
+}
+
+func TestTimeParser(t *testing.T) {
+	// Mountain Standard Time
+	mst, err := time.LoadLocation("MST")
+	require.NoError(t, err)
+
+	// Hawaiian Standard Time
+	hst, err := time.LoadLocation("HST")
+	require.NoError(t, err)
+
+	// use a deterministic clock
+	clock := testutil.NewFakeClock(time.Date(2020, 12, 16, 17, 0, 0, 0, mst))
+	now := clock.Now
 
 	rootField := entry.NewBodyField()
 	someField := entry.NewBodyField("some_field")
 
-	for _, tc := range testCases {
+	for _, tc := range timeParserCases(now, mst, hst) {
 		t.Run(tc.name, func(t *testing.T) {
 			gotimeRootCfg := parseTimeTestConfig(GotimeKey, tc.gotimeLayout, tc.location, rootField)
+			gotimeRootCfg.SetClock(clock)
 			t.Run("gotime-root", runTimeParseTest(gotimeRootCfg, makeTestEntry(rootField, tc.sample), false, false, tc.expected))
 
 			gotimeNonRootCfg := parseTimeTestConfig(GotimeKey, tc.gotimeLayout, tc.location, someField)
+			gotimeNonRootCfg.SetClock(clock)
 			t.Run("gotime-non-root", runTimeParseTest(gotimeNonRootCfg, makeTestEntry(someField, tc.sample), false, false, tc.expected))
 
 			strptimeRootCfg := parseTimeTestConfig(StrptimeKey, tc.strptimeLayout, tc.location, rootField)
+			strptimeRootCfg.SetClock(clock)
 			t.Run("strptime-root", runTimeParseTest(strptimeRootCfg, makeTestEntry(rootField, tc.sample), false, false, tc.expected))
 
 			strptimeNonRootCfg := parseTimeTestConfig(StrptimeKey, tc.strptimeLayout, tc.location, someField)
+			strptimeNonRootCfg.SetClock(clock)
 			t.Run("strptime-non-root", runTimeParseTest(strptimeNonRootCfg, makeTestEntry(someField, tc.sample), false, false, tc.expected))
 		})
 	}
 }
 
-func TestTimeEpochs(t *testing.T) {
-	testCases := []struct {
-		name     string
-		sample   interface{}
-		layout   string
-		expected time.Time
-		maxLoss  time.Duration
-	}{
+type timeEpochCase struct {
+	name     string
+	sample   interface{}
+	layout   string
+	expected time.Time
+	maxLoss  time.Duration
+}
+
+// timeEpochCases are also used to seed FuzzTimeParser
+func timeEpochCases() []timeEpochCase {
+	return []timeEpochCase{
 		{
 			name:     "s-default-string",
 			sample:   "1136214245",
@@ -489,11 +495,13 @@ func TestTimeEpochs(t *testing.T) {
 			maxLoss:  time.Nanosecond * 100,
 		},
 	}
+}
 
+func TestTimeEpochs(t *testing.T) {
 	rootField := entry.NewBodyField()
 	someField := entry.NewBodyField("some_field")
 
-	for _, tc := range testCases {
+	for _, tc := range timeEpochCases() {
 		t.Run(tc.name, func(t *testing.T) {
 			rootCfg := parseTimeTestConfig(EpochKey, tc.layout, "", rootField)
 			t.Run("epoch-root", runLossyTimeParseTest(rootCfg, makeTestEntry(rootField, tc.sample), false, false, tc.expected, tc.maxLoss))
@@ -504,16 +512,19 @@ func TestTimeEpochs(t *testing.T) {
 	}
 }
 
-func TestTimeErrors(t *testing.T) {
-	testCases := []struct {
-		name       string
-		sample     interface{}
-		layoutType string
-		layout     string
-		location   string
-		buildErr   bool
-		parseErr   bool
-	}{
+type timeErrorCase struct {
+	name       string
+	sample     interface{}
+	layoutType string
+	layout     string
+	location   string
+	buildErr   bool
+	parseErr   bool
+}
+
+// timeErrorCases are also used to seed FuzzTimeParser
+func timeErrorCases() []timeErrorCase {
+	return []timeErrorCase{
 		{
 			name:       "bad-layout-type",
 			layoutType: "fake",
@@ -564,11 +575,13 @@ func TestTimeErrors(t *testing.T) {
 			parseErr:   true,
 		},
 	}
+}
 
+func TestTimeErrors(t *testing.T) {
 	rootField := entry.NewBodyField()
 	someField := entry.NewBodyField("some_field")
 
-	for _, tc := range testCases {
+	for _, tc := range timeErrorCases() {
 		t.Run(tc.name, func(t *testing.T) {
 			rootCfg := parseTimeTestConfig(tc.layoutType, tc.layout, tc.location, rootField)
 			t.Run("err-root", runTimeParseTest(rootCfg, makeTestEntry(rootField, tc.sample), tc.buildErr, tc.parseErr, time.Now()))
@@ -16,14 +16,17 @@ package helper
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"time"
 
-	"github.com/antonmedv/expr"
 	"github.com/antonmedv/expr/vm"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
 	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 )
 
 // NewTransformerConfig creates a new transformer config with default values
@@ -36,9 +39,10 @@ func NewTransformerConfig(operatorID, operatorType string) TransformerConfig {
 
 // TransformerConfig provides a basic implementation of a transformer config.
 type TransformerConfig struct {
-	WriterConfig `mapstructure:",squash"  yaml:",inline"`
-	OnError      string `mapstructure:"on_error" json:"on_error" yaml:"on_error"`
-	IfExpr       string `mapstructure:"if"       json:"if"       yaml:"if"`
+	WriterConfig     `mapstructure:",squash"  yaml:",inline"`
+	OnError          string    `mapstructure:"on_error"           json:"on_error"           yaml:"on_error"           jsonschema:"enum=send|drop|dead_letter"`
+	IfExpr           string    `mapstructure:"if"                 json:"if"                 yaml:"if"`
+	DeadLetterOutput OutputIDs `mapstructure:"dead_letter_output" json:"dead_letter_output" yaml:"dead_letter_output"`
 }
 
 // Build will build a transformer operator.
@@ -50,21 +54,36 @@ func (c TransformerConfig) Build(logger *zap.SugaredLogger) (TransformerOperator
 
 	switch c.OnError {
 	case SendOnError, DropOnError:
+		if len(c.DeadLetterOutput) > 0 {
+			return TransformerOperator{}, errors.NewError(
+				"operator config has a `dead_letter_output` field, but does not use it.",
+				"ensure that the `on_error` field is set to `dead_letter` when using `dead_letter_output`.",
+				"on_error", c.OnError,
+			)
+		}
+	case DeadLetterOnError:
+		if len(c.DeadLetterOutput) == 0 {
+			return TransformerOperator{}, errors.NewError(
+				"operator config is missing the `dead_letter_output` field.",
+				"ensure that the `dead_letter_output` field is set when `on_error` is set to `dead_letter`.",
+			)
+		}
 	default:
 		return TransformerOperator{}, errors.NewError(
 			"operator config has an invalid `on_error` field.",
-			"ensure that the `on_error` field is set to either `send` or `drop`.",
+			"ensure that the `on_error` field is set to either `send`, `drop` or `dead_letter`.",
 			"on_error", c.OnError,
 		)
 	}
 
 	transformerOperator := TransformerOperator{
-		WriterOperator: writerOperator,
-		OnError:        c.OnError,
+		WriterOperator:      writerOperator,
+		OnError:             c.OnError,
+		DeadLetterOutputIDs: c.DeadLetterOutput,
 	}
 
 	if c.IfExpr != "" {
-		compiled, err := expr.Compile(c.IfExpr, expr.AsBool(), expr.AllowUndefinedVariables())
+		compiled, err := ExprCompileBool(c.IfExpr)
 		if err != nil {
 			return TransformerOperator{}, fmt.Errorf("failed to compile expression '%s': %w", c.IfExpr, err)
 		}
@@ -77,8 +96,10 @@ func (c TransformerConfig) Build(logger *zap.SugaredLogger) (TransformerOperator
 // TransformerOperator provides a basic implementation of a transformer operator.
 type TransformerOperator struct {
 	WriterOperator
-	OnError string
-	IfExpr  *vm.Program
+	OnError             string
+	IfExpr              *vm.Program
+	DeadLetterOutputIDs []string
+	DeadLetterOperators []operator.Operator
 }
 
 // CanProcess will always return true for a transformer operator.
@@ -86,34 +107,193 @@ func (t *TransformerOperator) CanProcess() bool {
 	return true
 }
 
+// Outputs returns the outputs of the transformer, including its dead letter outputs.
+func (t *TransformerOperator) Outputs() []operator.Operator {
+	if len(t.DeadLetterOperators) == 0 {
+		return t.OutputOperators
+	}
+
+	outputs := make([]operator.Operator, 0, len(t.OutputOperators)+len(t.DeadLetterOperators))
+	outputs = append(outputs, t.OutputOperators...)
+	for _, deadLetter := range t.DeadLetterOperators {
+		if _, ok := t.findOperator(outputs, deadLetter.ID()); !ok {
+			outputs = append(outputs, deadLetter)
+		}
+	}
+	return outputs
+}
+
+// SetOutputs will set the outputs and dead letter outputs of the transformer.
+func (t *TransformerOperator) SetOutputs(operators []operator.Operator) error {
+	if err := t.WriterOperator.SetOutputs(operators); err != nil {
+		return err
+	}
+
+	deadLetterOperators := make([]operator.Operator, 0, len(t.DeadLetterOutputIDs))
+	for _, operatorID := range t.DeadLetterOutputIDs {
+		operator, ok := t.findOperator(operators, operatorID)
+		if !ok {
+			return fmt.Errorf("dead letter operator '%s' does not exist", operatorID)
+		}
+
+		if !operator.CanProcess() {
+			return fmt.Errorf("dead letter operator '%s' can not process entries", operatorID)
+		}
+
+		deadLetterOperators = append(deadLetterOperators, operator)
+	}
+
+	t.DeadLetterOperators = deadLetterOperators
+	return nil
+}
+
 // ProcessWith will process an entry with a transform function.
-func (t *TransformerOperator) ProcessWith(ctx context.Context, entry *entry.Entry, transform TransformFunction) error {
+func (t *TransformerOperator) ProcessWith(ctx context.Context, entry *entry.Entry, transform TransformFunction) (err error) {
+	defer func() { t.telemetry.ReceivedUnlessRejected(ctx, err) }()
+
 	// Short circuit if the "if" condition does not match
 	skip, err := t.Skip(ctx, entry)
 	if err != nil {
 		return t.HandleEntryError(ctx, entry, err)
 	}
 	if skip {
-		t.Write(ctx, entry)
-		return nil
+		return t.Write(ctx, entry)
 	}
 
-	if err := transform(entry); err != nil {
+	start := time.Now()
+	err = transform(entry)
+	t.telemetry.RecordDuration(ctx, start)
+	if err != nil {
 		return t.HandleEntryError(ctx, entry, err)
 	}
-	t.Write(ctx, entry)
-	return nil
+	return t.Write(ctx, entry)
+}
+
+// ProcessBatchWith will process a batch of entries with a transform function, and then
+// write the entries that were not dropped to the outputs as a single batch.
+func (t *TransformerOperator) ProcessBatchWith(ctx context.Context, entries []*entry.Entry, transform TransformFunction) (err error) {
+	defer func() { t.telemetry.ReceivedBatchUnlessRejected(ctx, len(entries), err) }()
+
+	// A single expression environment is reused for the whole batch
+	var env map[string]interface{}
+	if t.IfExpr != nil {
+		env = envPool.Get().(map[string]interface{})
+		defer PutExprEnv(env)
+	}
+
+	// indices holds the position in entries of each entry in batch
+	batch := make([]*entry.Entry, 0, len(entries))
+	indices := make([]int, 0, len(entries))
+
+	var errs error
+	for i, e := range entries {
+		send, err := t.transformWith(ctx, env, e, transform)
+		if errors.IsBackpressure(err) {
+			// The dead letter outputs rejected the entry, so the rest of the batch is rejected with it
+			return t.writeBatch(ctx, len(entries), batch, indices, i, multierr.Append(errs, err))
+		}
+		errs = multierr.Append(errs, err)
+		if send {
+			batch = append(batch, e)
+			indices = append(indices, i)
+		}
+	}
+	return t.writeBatch(ctx, len(entries), batch, indices, len(entries), errs)
+}
+
+// transformWith transforms an entry of a batch, returning true if it should be written to the outputs.
+func (t *TransformerOperator) transformWith(ctx context.Context, env map[string]interface{}, entry *entry.Entry, transform TransformFunction) (bool, error) {
+	if env != nil {
+		setExprEnv(env, entry)
+		skip, err := t.skip(env)
+		if err != nil {
+			return t.handleEntryError(ctx, entry, err)
+		}
+		if skip {
+			return true, nil
+		}
+	}
+
+	start := time.Now()
+	err := transform(entry)
+	t.telemetry.RecordDuration(ctx, start)
+	if err != nil {
+		return t.handleEntryError(ctx, entry, err)
+	}
+	return true, nil
+}
+
+// writeBatch writes the transformed entries of a batch of the given size, of which only the
+// entries before processed were transformed. If the batch is not fully accepted, the returned
+// error reports the index of the first rejected entry in the original batch.
+func (t *TransformerOperator) writeBatch(ctx context.Context, size int, batch []*entry.Entry, indices []int, processed int, errs error) error {
+	err := t.WriteBatch(ctx, batch)
+	if accepted := errors.BatchAccepted(err, len(batch)); accepted < len(batch) {
+		processed = indices[accepted]
+	}
+	errs = multierr.Append(errs, err)
+
+	if processed < size {
+		return errors.NewBatchError(processed, errs)
+	}
+	return errs
 }
 
 // HandleEntryError will handle an entry error using the on_error strategy.
 func (t *TransformerOperator) HandleEntryError(ctx context.Context, entry *entry.Entry, err error) error {
-	t.Errorw("Failed to process entry", zap.Any("error", err), zap.Any("action", t.OnError), zap.Any("entry", entry))
-	if t.OnError == SendOnError {
-		t.Write(ctx, entry)
+	send, err := t.handleEntryError(ctx, entry, err)
+	if send {
+		return multierr.Append(err, t.Write(ctx, entry))
 	}
 	return err
 }
 
+// handleEntryError applies the on_error strategy to an entry,
+// returning true if the entry should still be written to the outputs.
+func (t *TransformerOperator) handleEntryError(ctx context.Context, entry *entry.Entry, err error) (bool, error) {
+	t.Errorw("Failed to process entry", zap.Any("error", err), zap.Any("action", t.OnError), zap.Any("entry", entry))
+	t.telemetry.Failed(ctx)
+	switch t.OnError {
+	case SendOnError:
+		return true, err
+	case DeadLetterOnError:
+		t.annotateError(entry, err)
+		return false, multierr.Append(err, t.writeDeadLetter(ctx, entry))
+	}
+	t.telemetry.Dropped(ctx)
+	entry.Ack()
+	return false, err
+}
+
+// annotateError adds the details of an error to the attributes of an entry
+func (t *TransformerOperator) annotateError(entry *entry.Entry, err error) {
+	entry.AddAttribute("error.operator_id", t.ID())
+	entry.AddAttribute("error.operator_type", t.Type())
+
+	var agentErr errors.AgentError
+	if !stderrors.As(err, &agentErr) {
+		entry.AddAttribute("error.description", err.Error())
+		return
+	}
+
+	entry.AddAttribute("error.description", agentErr.Description)
+	if agentErr.Suggestion != "" {
+		entry.AddAttribute("error.suggestion", agentErr.Suggestion)
+	}
+	if len(agentErr.Details) > 0 {
+		details := make(map[string]interface{}, len(agentErr.Details))
+		for key, value := range agentErr.Details {
+			details[key] = value
+		}
+		entry.Attributes["error.details"] = details
+	}
+}
+
+// writeDeadLetter will write an entry to the dead letter outputs of the operator.
+func (t *TransformerOperator) writeDeadLetter(ctx context.Context, e *entry.Entry) error {
+	return WriteTo(ctx, t.DeadLetterOperators, e)
+}
+
 func (t *TransformerOperator) Skip(ctx context.Context, entry *entry.Entry) (bool, error) {
 	if t.IfExpr == nil {
 		return false, nil
@@ -122,6 +302,11 @@ func (t *TransformerOperator) Skip(ctx context.Context, entry *entry.Entry) (boo
 	env := GetExprEnv(entry)
 	defer PutExprEnv(env)
 
+	return t.skip(env)
+}
+
+// skip evaluates the "if" expression with an environment prepared for an entry
+func (t *TransformerOperator) skip(env map[string]interface{}) (bool, error) {
 	matches, err := vm.Run(t.IfExpr, env)
 	if err != nil {
 		return false, fmt.Errorf("running if expr: %s", err)
@@ -138,3 +323,6 @@ const SendOnError = "send"
 
 // DropOnError specifies an on_error mode for dropping entries after an error.
 const DropOnError = "drop"
+
+// DeadLetterOnError specifies an on_error mode for sending entries to the dead letter outputs after an error.
+const DeadLetterOnError = "dead_letter"
@@ -18,11 +18,13 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
 )
@@ -63,6 +65,111 @@ func TestTransformerOnErrorInvalid(t *testing.T) {
 	require.Contains(t, err.Error(), "operator config has an invalid `on_error` field.")
 }
 
+func TestTransformerDeadLetterMissingOutput(t *testing.T) {
+	cfg := NewTransformerConfig("test", "test")
+	cfg.OnError = DeadLetterOnError
+	_, err := cfg.Build(testutil.Logger(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing the `dead_letter_output` field")
+}
+
+func TestTransformerDeadLetterOutputUnused(t *testing.T) {
+	cfg := NewTransformerConfig("test", "test")
+	cfg.DeadLetterOutput = []string{"dead-letter"}
+	_, err := cfg.Build(testutil.Logger(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has a `dead_letter_output` field, but does not use it")
+}
+
+func TestTransformerDeadLetterSetOutputs(t *testing.T) {
+	cfg := NewTransformerConfig("test", "test")
+	cfg.OutputIDs = []string{"test-output"}
+	cfg.OnError = DeadLetterOnError
+	cfg.DeadLetterOutput = []string{"dead-letter"}
+	transformer, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	output := testutil.NewMockOperator("test-output")
+	deadLetter := testutil.NewMockOperator("dead-letter")
+
+	err = transformer.SetOutputs([]operator.Operator{output})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "dead letter operator 'dead-letter' does not exist")
+
+	err = transformer.SetOutputs([]operator.Operator{output, deadLetter})
+	require.NoError(t, err)
+	require.Equal(t, []string{"test-output"}, transformer.GetOutputIDs())
+	require.Equal(t, []operator.Operator{output, deadLetter}, transformer.Outputs())
+}
+
+func TestTransformerDeadLetterOnError(t *testing.T) {
+	output := testutil.NewMockOperator("test-output")
+	output.On("Process", mock.Anything, mock.Anything).Return(nil)
+
+	var deadLettered *entry.Entry
+	deadLetter := testutil.NewMockOperator("dead-letter")
+	deadLetter.On("Process", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		deadLettered = args.Get(1).(*entry.Entry)
+	})
+
+	transformer := TransformerOperator{
+		OnError: DeadLetterOnError,
+		WriterOperator: WriterOperator{
+			BasicOperator: BasicOperator{
+				OperatorID:    "test-id",
+				OperatorType:  "test-type",
+				SugaredLogger: testutil.Logger(t),
+			},
+			OutputOperators: []operator.Operator{output},
+			OutputIDs:       []string{"test-output"},
+		},
+		DeadLetterOperators: []operator.Operator{deadLetter},
+		DeadLetterOutputIDs: []string{"dead-letter"},
+	}
+
+	cases := []struct {
+		name     string
+		err      error
+		expected map[string]interface{}
+	}{
+		{
+			"AgentError",
+			errors.NewError("failed to parse", "check the format", "field", "body"),
+			map[string]interface{}{
+				"error.operator_id":   "test-id",
+				"error.operator_type": "test-type",
+				"error.description":   "failed to parse",
+				"error.suggestion":    "check the format",
+				"error.details":       map[string]interface{}{"field": "body"},
+			},
+		},
+		{
+			"OtherError",
+			fmt.Errorf("Failure"),
+			map[string]interface{}{
+				"error.operator_id":   "test-id",
+				"error.operator_type": "test-type",
+				"error.description":   "Failure",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			deadLettered = nil
+			transform := func(e *entry.Entry) error {
+				return tc.err
+			}
+
+			err := transformer.ProcessWith(context.Background(), entry.New(), transform)
+			require.Error(t, err)
+			require.NotNil(t, deadLettered)
+			require.Equal(t, tc.expected, deadLettered.Attributes)
+			output.AssertNotCalled(t, "Process", mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func TestTransformerOperatorCanProcess(t *testing.T) {
 	cfg := NewTransformerConfig("test", "test")
 	transformer, err := cfg.Build(testutil.Logger(t))
@@ -124,6 +231,33 @@ func TestTransformerSendOnError(t *testing.T) {
 	output.AssertCalled(t, "Process", mock.Anything, mock.Anything)
 }
 
+func TestTransformerProcessWithBackpressure(t *testing.T) {
+	output := &testutil.Operator{}
+	output.On("ID").Return("test-output")
+	output.On("Process", mock.Anything, mock.Anything).Return(errors.NewBackpressureError("queue is full"))
+	transformer := TransformerOperator{
+		OnError: SendOnError,
+		WriterOperator: WriterOperator{
+			BasicOperator: BasicOperator{
+				OperatorID:    "test-id",
+				OperatorType:  "test-type",
+				SugaredLogger: testutil.Logger(t),
+			},
+			OutputOperators: []operator.Operator{output},
+			OutputIDs:       []string{"test-output"},
+		},
+	}
+	ctx := context.Background()
+	testEntry := entry.New()
+	transform := func(e *entry.Entry) error {
+		return nil
+	}
+
+	err := transformer.ProcessWith(ctx, testEntry, transform)
+	require.Error(t, err)
+	require.True(t, errors.IsBackpressure(err))
+}
+
 func TestTransformerProcessWithValid(t *testing.T) {
 	output := &testutil.Operator{}
 	output.On("ID").Return("test-output")
@@ -232,3 +366,68 @@ func TestTransformerIf(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestTransformerProcessBatchWith(t *testing.T) {
+	cfg := NewTransformerConfig("test", "test")
+	cfg.IfExpr = "body != 'skip'"
+	cfg.OnError = DropOnError
+	transformer, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	fake := testutil.NewFakeOutput(t)
+	transformer.OutputOperators = []operator.Operator{fake}
+
+	entries := make([]*entry.Entry, 0, 3)
+	for _, body := range []string{"test", "skip", "fail"} {
+		e := entry.New()
+		e.Body = body
+		entries = append(entries, e)
+	}
+
+	err = transformer.ProcessBatchWith(context.Background(), entries, func(e *entry.Entry) error {
+		if e.Body == "fail" {
+			return fmt.Errorf("Failure")
+		}
+		e.Body = "parsed"
+		return nil
+	})
+	require.Error(t, err)
+	require.False(t, errors.IsBackpressure(err))
+
+	fake.ExpectBody(t, "parsed")
+	fake.ExpectBody(t, "skip")
+	fake.ExpectNoEntry(t, 10*time.Millisecond)
+}
+
+func TestTransformerProcessBatchWithBackpressure(t *testing.T) {
+	output := &testutil.Operator{}
+	output.On("ID").Return("test-output")
+	output.On("Process", mock.Anything, mock.Anything).Return(nil).Once()
+	output.On("Process", mock.Anything, mock.Anything).Return(errors.NewBackpressureError("queue is full"))
+	transformer := TransformerOperator{
+		OnError: DropOnError,
+		WriterOperator: WriterOperator{
+			BasicOperator: BasicOperator{
+				OperatorID:    "test-id",
+				OperatorType:  "test-type",
+				SugaredLogger: testutil.Logger(t),
+			},
+			OutputOperators: []operator.Operator{output},
+			OutputIDs:       []string{"test-output"},
+		},
+	}
+
+	entries := []*entry.Entry{entry.New(), entry.New(), entry.New(), entry.New()}
+	entries[1].Body = "fail"
+	err := transformer.ProcessBatchWith(context.Background(), entries, func(e *entry.Entry) error {
+		if e.Body == "fail" {
+			return fmt.Errorf("Failure")
+		}
+		return nil
+	})
+	require.True(t, errors.IsBackpressure(err))
+
+	// The dropped entry is not written, so the second entry written is the third in the batch
+	require.Equal(t, 2, errors.BatchAccepted(err, len(entries)))
+	output.AssertNumberOfCalls(t, "Process", 2)
+}
@@ -18,10 +18,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/jpillora/backoff"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 )
 
@@ -58,15 +62,173 @@ type WriterOperator struct {
 	OutputOperators []operator.Operator
 }
 
-// Write will write an entry to the outputs of the operator.
-func (w *WriterOperator) Write(ctx context.Context, e *entry.Entry) {
-	for i, operator := range w.OutputOperators {
-		if i == len(w.OutputOperators)-1 {
-			_ = operator.Process(ctx, e)
-			return
+// NewEntry creates an empty entry, observed at the current time of the operator's clock.
+func (w *WriterOperator) NewEntry() *entry.Entry {
+	e := entry.New()
+	if w.clock != nil {
+		e.ObservedTimestamp = w.clock.Now()
+	}
+	return e
+}
+
+// Write will write an entry to the outputs of the operator. If only some of the outputs
+// apply backpressure, the entry is retried on those outputs until they accept it, so that
+// the outputs that accepted it do not receive it twice. Backpressure is returned to the
+// caller if every output rejects the entry, or if the context is done before the entry is
+// retried, in which case outputs that accepted it may receive it again. The entry is
+// recorded as emitted once it is accepted.
+func (w *WriterOperator) Write(ctx context.Context, e *entry.Entry) error {
+	if len(w.OutputOperators) == 0 {
+		return nil
+	}
+
+	err := WriteTo(ctx, w.OutputOperators, e)
+	if !errors.IsBackpressure(err) {
+		w.telemetry.Emitted(ctx)
+	}
+	return err
+}
+
+// WriteTo writes an entry to a set of outputs in the same way as WriterOperator.Write.
+// It is used by operators that choose the outputs of each entry, such as router.
+func WriteTo(ctx context.Context, outputs []operator.Operator, e *entry.Entry) error {
+	switch len(outputs) {
+	case 0:
+		return nil
+	case 1:
+		return outputs[0].Process(ctx, e)
+	default:
+		_, err := fanOut(ctx, outputs, []*entry.Entry{e})
+		return err
+	}
+}
+
+// WriteBatch will write a batch of entries to the outputs of the operator.
+// If any output applies backpressure, the returned error is an errors.BatchError
+// with the number of entries that were accepted by every output. Outputs that
+// accepted fewer entries than the others are retried as in Write. Only the accepted
+// entries are recorded as emitted.
+func (w *WriterOperator) WriteBatch(ctx context.Context, entries []*entry.Entry) error {
+	if len(entries) == 0 || len(w.OutputOperators) == 0 {
+		return nil
+	}
+
+	var accepted int
+	var err error
+	if len(w.OutputOperators) == 1 {
+		err = operator.ProcessBatch(ctx, w.OutputOperators[0], entries)
+		accepted = errors.BatchAccepted(err, len(entries))
+	} else {
+		accepted, err = fanOut(ctx, w.OutputOperators, entries)
+	}
+	if accepted > 0 {
+		w.telemetry.EmittedBatch(ctx, accepted)
+	}
+
+	if accepted < len(entries) {
+		return errors.NewBatchError(accepted, err)
+	}
+	return err
+}
+
+// fanOut writes shared copies of a batch of entries to each output. Outputs that accept
+// fewer entries than the others are retried with the entries they rejected, until every
+// output has accepted the same entries or the context is done. It returns the number of
+// entries accepted by every output, which are acknowledged and released.
+func fanOut(ctx context.Context, outputs []operator.Operator, entries []*entry.Entry) (int, error) {
+	var errs error
+	accepted := make([]int, len(outputs))
+	rejected := make([]error, len(outputs))
+
+	target := 0
+	for i, output := range outputs {
+		accepted[i], rejected[i] = writeShared(ctx, output, entries)
+		if !errors.IsBackpressure(rejected[i]) {
+			errs = multierr.Append(errs, rejected[i])
+			rejected[i] = nil
+		}
+		if accepted[i] > target {
+			target = accepted[i]
+		}
+	}
+
+	retry := backoff.Backoff{
+		Min: 10 * time.Millisecond,
+		Max: time.Second,
+	}
+	for !caughtUp(accepted, target) && wait(ctx, retry.Duration()) {
+		for i, output := range outputs {
+			if accepted[i] >= target {
+				continue
+			}
+			n, err := writeShared(ctx, output, entries[accepted[i]:target])
+			accepted[i] += n
+			rejected[i] = nil
+			if errors.IsBackpressure(err) {
+				rejected[i] = err
+			} else {
+				errs = multierr.Append(errs, err)
+			}
+		}
+	}
+
+	done := target
+	for i := range accepted {
+		if accepted[i] < done {
+			done = accepted[i]
+		}
+		errs = multierr.Append(errs, rejected[i])
+	}
+
+	for _, e := range entries[:done] {
+		e.Ack()
+		e.Release()
+	}
+	return done, errs
+}
+
+// writeShared writes shared copies of entries to an output, and returns the number of entries
+// that it accepted. The rejected copies are acknowledged and released, since the entries they
+// were copied from are still pending.
+func writeShared(ctx context.Context, output operator.Operator, entries []*entry.Entry) (int, error) {
+	batch := make([]*entry.Entry, 0, len(entries))
+	for _, e := range entries {
+		batch = append(batch, e.Share())
+	}
+
+	var err error
+	if len(batch) == 1 {
+		err = output.Process(ctx, batch[0])
+	} else {
+		err = operator.ProcessBatch(ctx, output, batch)
+	}
+
+	accepted := errors.BatchAccepted(err, len(batch))
+	for _, e := range batch[accepted:] {
+		e.Ack()
+		e.Release()
+	}
+	return accepted, err
+}
+
+// wait returns false if the context is done before the duration elapses
+func wait(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// caughtUp returns true if every output has accepted the target number of entries
+func caughtUp(accepted []int, target int) bool {
+	for _, n := range accepted {
+		if n < target {
+			return false
 		}
-		_ = operator.Process(ctx, e.Copy())
 	}
+	return true
 }
 
 // CanOutput always returns true for a writer operator.
@@ -123,6 +285,14 @@ func (w *WriterOperator) findOperator(operators []operator.Operator, operatorID
 // OutputIDs is a collection of operator IDs used as outputs.
 type OutputIDs []string
 
+// JSONSchema describes OutputIDs as a string or array of strings.
+func (o OutputIDs) JSONSchema() *operator.Schema {
+	return &operator.Schema{AnyOf: []*operator.Schema{
+		{Type: "string"},
+		{Type: "array", Items: &operator.Schema{Type: "string"}},
+	}}
+}
+
 // UnmarshalJSON will unmarshal a string or array of strings to OutputIDs.
 func (o *OutputIDs) UnmarshalJSON(bytes []byte) error {
 	var value interface{}
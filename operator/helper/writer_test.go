@@ -18,12 +18,14 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	yaml "gopkg.in/yaml.v2"
 
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
 )
@@ -61,11 +63,97 @@ func TestWriterOperatorWrite(t *testing.T) {
 	ctx := context.Background()
 	testEntry := entry.New()
 
-	writer.Write(ctx, testEntry)
+	err := writer.Write(ctx, testEntry)
+	require.NoError(t, err)
 	output1.AssertCalled(t, "Process", ctx, mock.Anything)
 	output2.AssertCalled(t, "Process", ctx, mock.Anything)
 }
 
+func TestWriterOperatorWriteBackpressure(t *testing.T) {
+	output1 := &testutil.Operator{}
+	output1.On("Process", mock.Anything, mock.Anything).Return(errors.NewBackpressureError("queue is full"))
+	output2 := &testutil.Operator{}
+	output2.On("Process", mock.Anything, mock.Anything).Return(errors.NewBackpressureError("queue is full"))
+	writer := WriterOperator{
+		OutputOperators: []operator.Operator{output1, output2},
+	}
+
+	ctx := context.Background()
+	testEntry := entry.New()
+
+	// Backpressure from every output is returned without retrying
+	err := writer.Write(ctx, testEntry)
+	require.Error(t, err)
+	require.True(t, errors.IsBackpressure(err))
+	output1.AssertNumberOfCalls(t, "Process", 1)
+	output2.AssertNumberOfCalls(t, "Process", 1)
+}
+
+func TestWriterOperatorWriteRetriesRejectingOutput(t *testing.T) {
+	output1 := &testutil.Operator{}
+	output1.On("Process", mock.Anything, mock.Anything).Return(errors.NewBackpressureError("queue is full")).Once()
+	output1.On("Process", mock.Anything, mock.Anything).Return(nil)
+	output2 := &testutil.Operator{}
+	output2.On("Process", mock.Anything, mock.Anything).Return(nil)
+	writer := WriterOperator{
+		OutputOperators: []operator.Operator{output1, output2},
+	}
+
+	acked := 0
+	testEntry := entry.New()
+	testEntry.OnAck(func() { acked++ })
+
+	err := writer.Write(context.Background(), testEntry)
+	require.NoError(t, err)
+	output1.AssertNumberOfCalls(t, "Process", 2)
+	output2.AssertNumberOfCalls(t, "Process", 1)
+
+	// The entry is acknowledged once the copies accepted by the outputs are acknowledged
+	require.Equal(t, 0, acked)
+	for _, output := range []*testutil.Operator{output1, output2} {
+		for _, call := range output.Calls {
+			if call.Method == "Process" {
+				call.Arguments.Get(1).(*entry.Entry).Ack()
+			}
+		}
+	}
+	require.Equal(t, 1, acked)
+}
+
+func TestWriterOperatorWritePartialBackpressure(t *testing.T) {
+	output1 := &testutil.Operator{}
+	output1.On("Process", mock.Anything, mock.Anything).Return(errors.NewBackpressureError("queue is full"))
+	output2 := &testutil.Operator{}
+	output2.On("Process", mock.Anything, mock.Anything).Return(nil)
+	writer := WriterOperator{
+		OutputOperators: []operator.Operator{output1, output2},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The entry is retried only on the output that rejected it, until the context is done
+	err := writer.Write(ctx, entry.New())
+	require.True(t, errors.IsBackpressure(err))
+	require.Greater(t, len(output1.Calls), 1)
+	output2.AssertNumberOfCalls(t, "Process", 1)
+}
+
+func TestWriterOperatorNewEntry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, time.May, 1, 12, 0, 0, 0, time.UTC)
+	writer := WriterOperator{}
+	writer.SetClock(testutil.NewFakeClock(now))
+
+	e := writer.NewEntry()
+	require.Equal(t, now, e.ObservedTimestamp)
+	require.True(t, e.Timestamp.IsZero())
+
+	// Entries created without a clock are observed at the system time
+	require.WithinDuration(t, time.Now(), (&WriterOperator{}).NewEntry().ObservedTimestamp, time.Minute)
+}
+
 func TestWriterOperatorCanOutput(t *testing.T) {
 	writer := WriterOperator{}
 	require.True(t, writer.CanOutput())
@@ -208,3 +296,60 @@ func TestUnmarshalYAMLInvalidArray(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "value in array is not of type string")
 }
+
+func TestWriterOperatorWriteBatch(t *testing.T) {
+	output1 := &testutil.Operator{}
+	output1.On("Process", mock.Anything, mock.Anything).Return(nil)
+	output2 := &testutil.Operator{}
+	output2.On("Process", mock.Anything, mock.Anything).Return(nil)
+	writer := WriterOperator{
+		OutputOperators: []operator.Operator{output1, output2},
+	}
+
+	entries := []*entry.Entry{entry.New(), entry.New()}
+	err := writer.WriteBatch(context.Background(), entries)
+	require.NoError(t, err)
+	output1.AssertNumberOfCalls(t, "Process", 2)
+	output2.AssertNumberOfCalls(t, "Process", 2)
+}
+
+func TestWriterOperatorWriteBatchBackpressure(t *testing.T) {
+	output1 := &testutil.Operator{}
+	output1.On("Process", mock.Anything, mock.Anything).Return(nil).Once()
+	output1.On("Process", mock.Anything, mock.Anything).Return(errors.NewBackpressureError("queue is full"))
+	output2 := &testutil.Operator{}
+	output2.On("Process", mock.Anything, mock.Anything).Return(nil).Once()
+	output2.On("Process", mock.Anything, mock.Anything).Return(errors.NewBackpressureError("queue is full"))
+	writer := WriterOperator{
+		OutputOperators: []operator.Operator{output1, output2},
+	}
+
+	entries := []*entry.Entry{entry.New(), entry.New(), entry.New()}
+	err := writer.WriteBatch(context.Background(), entries)
+	require.True(t, errors.IsBackpressure(err))
+	require.Equal(t, 1, errors.BatchAccepted(err, len(entries)))
+
+	// Entries are processed one at a time until an output applies backpressure
+	output1.AssertNumberOfCalls(t, "Process", 2)
+	output2.AssertNumberOfCalls(t, "Process", 2)
+}
+
+func TestWriterOperatorWriteBatchRetriesRejectingOutput(t *testing.T) {
+	output1 := &testutil.Operator{}
+	output1.On("Process", mock.Anything, mock.Anything).Return(nil).Once()
+	output1.On("Process", mock.Anything, mock.Anything).Return(errors.NewBackpressureError("queue is full")).Once()
+	output1.On("Process", mock.Anything, mock.Anything).Return(nil)
+	output2 := &testutil.Operator{}
+	output2.On("Process", mock.Anything, mock.Anything).Return(nil)
+	writer := WriterOperator{
+		OutputOperators: []operator.Operator{output1, output2},
+	}
+
+	entries := []*entry.Entry{entry.New(), entry.New(), entry.New()}
+	err := writer.WriteBatch(context.Background(), entries)
+	require.NoError(t, err)
+
+	// The first output is retried with the entries it rejected, and neither output receives an entry twice
+	output1.AssertNumberOfCalls(t, "Process", 4)
+	output2.AssertNumberOfCalls(t, "Process", 3)
+}
@@ -140,6 +140,7 @@ func BenchmarkFileInput(b *testing.B) {
 
 	for _, bench := range cases {
 		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
 			rootDir, err := ioutil.TempDir("", "")
 			require.NoError(b, err)
 
@@ -185,8 +186,10 @@ func BenchmarkFileInput(b *testing.B) {
 				}
 			}()
 
+			// Entries are released as they would be by an output, so that they are reused
 			for i := 0; i < b.N*len(files); i++ {
-				<-fakeOutput.Received
+				e := <-fakeOutput.Received
+				e.Release()
 			}
 		})
 	}
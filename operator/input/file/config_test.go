@@ -451,6 +451,15 @@ func TestUnmarshal(t *testing.T) {
 				return cfg
 			}(),
 		},
+		{
+			Name:      "at_least_once",
+			ExpectErr: false,
+			Expect: func() *InputConfig {
+				cfg := defaultCfg()
+				cfg.AtLeastOnce = true
+				return cfg
+			}(),
+		},
 		{
 			Name:      "max_log_size_mib_lower",
 			ExpectErr: false,
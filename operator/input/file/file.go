@@ -58,6 +58,8 @@ type InputOperator struct {
 
 	encoding helper.Encoding
 
+	atLeastOnce bool
+
 	wg         sync.WaitGroup
 	firstCheck bool
 	cancel     context.CancelFunc
@@ -98,17 +100,17 @@ func (f *InputOperator) Stop() error {
 // startPoller kicks off a goroutine that will poll the filesystem periodically,
 // checking if there are new files or new logs in the watched files
 func (f *InputOperator) startPoller(ctx context.Context) {
+	globTicker := f.Clock().NewTicker(f.PollInterval)
 	f.wg.Add(1)
 	go func() {
 		defer f.wg.Done()
-		globTicker := time.NewTicker(f.PollInterval)
 		defer globTicker.Stop()
 
 		for {
 			select {
 			case <-ctx.Done():
 				return
-			case <-globTicker.C:
+			case <-globTicker.C():
 			}
 
 			f.poll(ctx)
@@ -295,6 +297,9 @@ func (f *InputOperator) findFingerprintMatch(fp *Fingerprint) (*Reader, bool) {
 	return nil, false
 }
 
+// knownFilesKey stores the known files as a stream of JSON encoded readers, which is
+// version 0 of the file_input state. Changes to the encoding of Reader or Fingerprint
+// must register a migration of the state with operator.RegisterStateMigration.
 const knownFilesKey = "knownFiles"
 
 // syncLastPollFiles syncs the most recent set of files to the database
@@ -310,7 +315,7 @@ func (f *InputOperator) syncLastPollFiles(ctx context.Context) {
 
 	// Encode each known file
 	for _, fileReader := range f.knownFiles {
-		if err := enc.Encode(fileReader); err != nil {
+		if err := enc.Encode(fileReader.checkpoint()); err != nil {
 			f.Errorw("Failed to encode known files", zap.Error(err))
 		}
 	}
@@ -362,5 +367,10 @@ func (f *InputOperator) loadLastPollFiles(ctx context.Context) error {
 
 // getMultiline returns helper.Splitter structure and error eventually
 func (f *InputOperator) getMultiline() (*helper.Splitter, error) {
-	return f.Splitter.Build(f.encoding.Encoding, false, f.MaxLogSize)
+	splitter, err := f.Splitter.Build(f.encoding.Encoding, false, f.MaxLogSize)
+	if err != nil {
+		return nil, err
+	}
+	splitter.Flusher.SetClock(f.Clock())
+	return splitter, nil
 }
@@ -21,13 +21,17 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
 	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
 )
@@ -205,6 +209,77 @@ func TestReadExistingLogs(t *testing.T) {
 	waitForMessage(t, logReceived, "testlog2")
 }
 
+// backpressureOutput rejects the first entries it receives with backpressure
+type backpressureOutput struct {
+	*testutil.FakeOutput
+	rejections int32
+}
+
+func (o *backpressureOutput) Process(ctx context.Context, e *entry.Entry) error {
+	if atomic.AddInt32(&o.rejections, -1) >= 0 {
+		return errors.NewBackpressureError("output is unavailable")
+	}
+	return o.FakeOutput.Process(ctx, e)
+}
+
+// TestReadWithBackpressure tests that entries rejected with backpressure are
+// read again during a later poll, rather than being skipped
+func TestReadWithBackpressure(t *testing.T) {
+	t.Parallel()
+	fileInput, _, tempDir := newTestFileOperator(t, nil, nil)
+	output := &backpressureOutput{
+		FakeOutput: testutil.NewFakeOutput(t),
+		rejections: 2,
+	}
+	require.NoError(t, fileInput.SetOutputs([]operator.Operator{output}))
+
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "testlog1\ntestlog2\n")
+
+	require.NoError(t, fileInput.Start(testutil.NewMockPersister("test")))
+	defer fileInput.Stop()
+
+	waitForMessage(t, output.Received, "testlog1")
+	waitForMessage(t, output.Received, "testlog2")
+	expectNoMessages(t, output.Received)
+}
+
+// partialBackpressureOutput accepts a number of entries before rejecting the next one with backpressure
+type partialBackpressureOutput struct {
+	*testutil.FakeOutput
+	accepted int32
+}
+
+func (o *partialBackpressureOutput) Process(ctx context.Context, e *entry.Entry) error {
+	if atomic.AddInt32(&o.accepted, -1) == -1 {
+		return errors.NewBackpressureError("output is unavailable")
+	}
+	return o.FakeOutput.Process(ctx, e)
+}
+
+// TestReadWithPartialBackpressure tests that when part of a batch of entries is
+// rejected with backpressure, only the rejected entries are read again
+func TestReadWithPartialBackpressure(t *testing.T) {
+	t.Parallel()
+	fileInput, _, tempDir := newTestFileOperator(t, nil, nil)
+	output := &partialBackpressureOutput{
+		FakeOutput: testutil.NewFakeOutput(t),
+		accepted:   1,
+	}
+	require.NoError(t, fileInput.SetOutputs([]operator.Operator{output}))
+
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "testlog1\ntestlog2\ntestlog3\n")
+
+	require.NoError(t, fileInput.Start(testutil.NewMockPersister("test")))
+	defer fileInput.Stop()
+
+	waitForMessage(t, output.Received, "testlog1")
+	waitForMessage(t, output.Received, "testlog2")
+	waitForMessage(t, output.Received, "testlog3")
+	expectNoMessages(t, output.Received)
+}
+
 // TestReadUsingNopEncoding tests when nop encoding is set, that the splitfunction returns all bytes unchanged.
 func TestReadUsingNopEncoding(t *testing.T) {
 	tcs := []struct {
@@ -642,6 +717,112 @@ func TestOffsetsAfterRestart(t *testing.T) {
 	waitForMessage(t, logReceived, "testlog2")
 }
 
+// unackedOutput receives entries without acknowledging them
+type unackedOutput struct {
+	*testutil.FakeOutput
+}
+
+func (o *unackedOutput) Process(_ context.Context, e *entry.Entry) error {
+	o.Received <- e
+	return nil
+}
+
+// TestOffsetsAfterRestart_AtLeastOnce tests that entries which were not
+// acknowledged before a restart are read again
+func TestOffsetsAfterRestart_AtLeastOnce(t *testing.T) {
+	t.Parallel()
+	fileInput, _, tempDir := newTestFileOperator(t, func(cfg *InputConfig) {
+		cfg.AtLeastOnce = true
+	}, nil)
+	output := &unackedOutput{testutil.NewFakeOutput(t)}
+	require.NoError(t, fileInput.SetOutputs([]operator.Operator{output}))
+	persister := testutil.NewMockPersister("test")
+
+	temp1 := openTemp(t, tempDir)
+	writeString(t, temp1, "testlog1\ntestlog2\ntestlog3\n")
+
+	require.NoError(t, fileInput.Start(persister))
+	defer fileInput.Stop()
+
+	// Acknowledge all but the second entry
+	for _, expected := range []string{"testlog1", "testlog2", "testlog3"} {
+		select {
+		case e := <-output.Received:
+			require.Equal(t, expected, e.Body)
+			if expected != "testlog2" {
+				e.Ack()
+			}
+		case <-time.After(3 * time.Second):
+			require.FailNow(t, "Timed out waiting for message", expected)
+		}
+	}
+
+	// Wait for the offsets to be synced by a later poll
+	time.Sleep(2 * fileInput.PollInterval)
+	require.NoError(t, fileInput.Stop())
+	require.NoError(t, fileInput.Start(persister))
+
+	// Only the acknowledged prefix of the file is skipped
+	waitForMessage(t, output.Received, "testlog2")
+	waitForMessage(t, output.Received, "testlog3")
+	expectNoMessages(t, output.Received)
+}
+
+// heldOutput holds the entries it receives without acknowledging them
+type heldOutput struct {
+	*testutil.FakeOutput
+	mux     sync.Mutex
+	entries []*entry.Entry
+}
+
+func (o *heldOutput) Process(_ context.Context, e *entry.Entry) error {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	o.entries = append(o.entries, e)
+	return nil
+}
+
+func (o *heldOutput) count() int {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	return len(o.entries)
+}
+
+// TestReadPausesWithTooManyPendingAcks tests that a reader stops reading while too many
+// of its entries are waiting to be acknowledged, and resumes once they are
+func TestReadPausesWithTooManyPendingAcks(t *testing.T) {
+	t.Parallel()
+	fileInput, _, tempDir := newTestFileOperator(t, func(cfg *InputConfig) {
+		cfg.AtLeastOnce = true
+	}, nil)
+	output := &heldOutput{FakeOutput: testutil.NewFakeOutput(t)}
+	require.NoError(t, fileInput.SetOutputs([]operator.Operator{output}))
+
+	total := maxPendingAcks + 500
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, strings.Repeat("testlog\n", total))
+
+	require.NoError(t, fileInput.Start(testutil.NewMockPersister("test")))
+	defer fileInput.Stop()
+
+	require.Eventually(t, func() bool {
+		return output.count() >= maxPendingAcks
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Never(t, func() bool {
+		return output.count() >= maxPendingAcks+maxBatchSize
+	}, 5*fileInput.PollInterval, 10*time.Millisecond)
+
+	output.mux.Lock()
+	for _, e := range output.entries {
+		e.Ack()
+	}
+	output.mux.Unlock()
+
+	require.Eventually(t, func() bool {
+		return output.count() == total
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
 func TestOffsetsAfterRestart_BigFiles(t *testing.T) {
 	t.Parallel()
 	operator, logReceived, tempDir := newTestFileOperator(t, nil, nil)
@@ -30,6 +30,13 @@ import (
 	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
 )
 
+// maxBatchSize is the maximum number of entries a reader emits at once
+const maxBatchSize = 100
+
+// maxPendingAcks is the maximum number of entries that a reader waits to be acknowledged
+// before it pauses, so that entries that are never acknowledged do not use unbounded memory
+const maxPendingAcks = 10000
+
 // File attributes contains information about file paths
 type fileAttributes struct {
 	Name         string
@@ -64,6 +71,9 @@ type Reader struct {
 	Fingerprint *Fingerprint
 	Offset      int64
 
+	// readOffset is the position of the file, which is ahead of the offset while entries are batched
+	readOffset int64
+
 	generation     int
 	fileInput      *InputOperator
 	file           *os.File
@@ -74,6 +84,11 @@ type Reader struct {
 
 	splitter *helper.Splitter
 
+	// acks tracks the offsets of emitted entries until they are acknowledged
+	acks *helper.AckTracker
+	// acksFull is true while the reader is paused by too many pending acknowledgements
+	acksFull bool
+
 	*zap.SugaredLogger `json:"-"`
 }
 
@@ -99,6 +114,8 @@ func (r *Reader) Copy(file *os.File) (*Reader, error) {
 		return nil, err
 	}
 	reader.Offset = r.Offset
+	reader.acks = r.acks
+	reader.acksFull = r.acksFull
 	return reader, nil
 }
 
@@ -120,9 +137,18 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		r.Errorw("Failed to seek", zap.Error(err))
 		return
 	}
+	r.readOffset = r.Offset
+
+	if r.fileInput.atLeastOnce && r.acks == nil {
+		r.acks = helper.NewAckTracker(r.Offset, nil)
+	}
 
 	scanner := NewPositionalScanner(r, r.fileInput.MaxLogSize, r.Offset, r.splitter.SplitFunc)
 
+	// Entries are emitted in batches, along with the offset following each of them
+	batch := make([]*entry.Entry, 0, maxBatchSize)
+	positions := make([]int64, 0, maxBatchSize)
+
 	// Iterate over the tokenized file, emitting entries as we go
 	for {
 		select {
@@ -139,11 +165,34 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 			break
 		}
 
-		if err := r.emit(ctx, scanner.Bytes()); err != nil {
-			r.Error("Failed to emit entry", zap.Error(err))
+		e, err := r.newEntry(scanner.Bytes())
+		if err != nil || e == nil {
+			// Skipped messages advance the offset, so the batched entries must be emitted first
+			if !r.emitBatch(ctx, batch, positions) {
+				return
+			}
+			batch, positions = batch[:0], positions[:0]
+
+			if err != nil {
+				r.Errorw("Failed to emit entry", zap.Error(err))
+			}
+			if r.acks != nil {
+				r.acks.Advance(scanner.Pos())
+			}
+			r.Offset = scanner.Pos()
+			continue
+		}
+
+		batch = append(batch, e)
+		positions = append(positions, scanner.Pos())
+		if len(batch) == maxBatchSize {
+			if !r.emitBatch(ctx, batch, positions) {
+				return
+			}
+			batch, positions = batch[:0], positions[:0]
 		}
-		r.Offset = scanner.Pos()
 	}
+	r.emitBatch(ctx, batch, positions)
 }
 
 // Close will close the file
@@ -155,48 +204,113 @@ func (r *Reader) Close() {
 	}
 }
 
-// Emit creates an entry with the decoded message and sends it to the next
-// operator in the pipeline
-func (r *Reader) emit(ctx context.Context, msgBuf []byte) error {
+// checkpoint returns the state of the reader that is safe to persist. When
+// at-least-once delivery is enabled, the offset excludes unacknowledged entries.
+func (r *Reader) checkpoint() *Reader {
+	if r.acks == nil {
+		return r
+	}
+	return &Reader{
+		Fingerprint: r.Fingerprint,
+		Offset:      r.acks.Committed().(int64),
+	}
+}
+
+// emitBatch sends a batch of entries to the next operators in the pipeline, and advances
+// the offset past the accepted entries. The positions are the offsets following each entry.
+// It returns false if downstream operators applied backpressure, or if too many entries
+// are waiting to be acknowledged, in which case the entries that were not sent are released.
+func (r *Reader) emitBatch(ctx context.Context, entries []*entry.Entry, positions []int64) bool {
+	if len(entries) == 0 {
+		return true
+	}
+
+	if r.acks != nil && r.acks.Pending() >= maxPendingAcks {
+		if !r.acksFull {
+			r.Warnw("Too many entries are waiting to be acknowledged, pausing until they are. Ensure that every output acknowledges entries",
+				zap.Int("pending", r.acks.Pending()))
+			r.acksFull = true
+		}
+		for _, e := range entries {
+			e.Release()
+		}
+		return false
+	}
+	r.acksFull = false
+
+	var cancels []func()
+	if r.acks != nil {
+		cancels = make([]func(), 0, len(entries))
+		for i, e := range entries {
+			cancels = append(cancels, r.acks.Track(e, positions[i]))
+		}
+	}
+
+	// Processing errors are handled by the operators that encountered them,
+	// so only backpressure needs to be handled by the reader
+	err := r.fileInput.WriteBatch(ctx, entries)
+	accepted := errors.BatchAccepted(err, len(entries))
+	if accepted > 0 {
+		r.Offset = positions[accepted-1]
+	}
+	if accepted == len(entries) {
+		return true
+	}
+
+	// Leave the offset in place so that the rejected entries are read again during the next poll
+	if cancels != nil {
+		for _, cancel := range cancels[accepted:] {
+			cancel()
+		}
+	}
+	for _, e := range entries[accepted:] {
+		e.Abandon()
+		e.Release()
+	}
+	r.Debugw("Downstream operators applied backpressure, pausing until the next poll", zap.Error(err))
+	return false
+}
+
+// newEntry creates an entry from the decoded message. Empty messages are skipped.
+func (r *Reader) newEntry(msgBuf []byte) (*entry.Entry, error) {
 	// Skip the entry if it's empty
 	if len(msgBuf) == 0 {
-		return nil
+		return nil, nil
 	}
 	var e *entry.Entry
 	var err error
 	if r.fileInput.encoding.Encoding == encoding.Nop {
 		e, err = r.fileInput.NewEntry(msgBuf)
 		if err != nil {
-			return fmt.Errorf("create entry: %s", err)
+			return nil, fmt.Errorf("create entry: %s", err)
 		}
 	} else {
 		msg, err := r.decode(msgBuf)
 		if err != nil {
-			return fmt.Errorf("decode: %s", err)
+			return nil, fmt.Errorf("decode: %s", err)
 		}
 		e, err = r.fileInput.NewEntry(msg)
 		if err != nil {
-			return fmt.Errorf("create entry: %s", err)
+			return nil, fmt.Errorf("create entry: %s", err)
 		}
 	}
 
 	if err := e.Set(r.fileInput.FilePathField, r.fileAttributes.Path); err != nil {
-		return err
+		return nil, err
 	}
 	if err := e.Set(r.fileInput.FileNameField, r.fileAttributes.Name); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := e.Set(r.fileInput.FilePathResolvedField, r.fileAttributes.ResolvedPath); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := e.Set(r.fileInput.FileNameResolvedField, r.fileAttributes.ResolvedName); err != nil {
-		return err
+		return nil, err
 	}
 
-	r.fileInput.Write(ctx, e)
-	return nil
+	return e, nil
 }
 
 // decode converts the bytes in msgBuf to utf-8 from the configured encoding
@@ -230,8 +344,9 @@ func (r *Reader) Read(dst []byte) (int, error) {
 		return r.file.Read(dst)
 	}
 	n, err := r.file.Read(dst)
-	appendCount := min0(n, r.fileInput.fingerprintSize-int(r.Offset))
-	r.Fingerprint.FirstBytes = append(r.Fingerprint.FirstBytes[:r.Offset], dst[:appendCount]...)
+	appendCount := min0(n, r.fileInput.fingerprintSize-int(r.readOffset))
+	r.Fingerprint.FirstBytes = append(r.Fingerprint.FirstBytes[:r.readOffset], dst[:appendCount]...)
+	r.readOffset += int64(n)
 	return n, err
 }
 
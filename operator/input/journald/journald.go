@@ -28,10 +28,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jpillora/backoff"
 	jsoniter "github.com/json-iterator/go"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	agenterrors "github.com/open-telemetry/opentelemetry-log-collection/errors"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
 )
@@ -52,11 +54,12 @@ func NewJournaldInputConfig(operatorID string) *JournaldInputConfig {
 type JournaldInputConfig struct {
 	helper.InputConfig `mapstructure:",squash" yaml:",inline"`
 
-	Directory *string  `mapstructure:"directory,omitempty" json:"directory,omitempty" yaml:"directory,omitempty"`
-	Files     []string `mapstructure:"files,omitempty"     json:"files,omitempty"     yaml:"files,omitempty"`
-	StartAt   string   `mapstructure:"start_at,omitempty"  json:"start_at,omitempty"  yaml:"start_at,omitempty"`
-	Units     []string `mapstructure:"units,omitempty"     json:"units,omitempty"     yaml:"units,omitempty"`
-	Priority  string   `mapstructure:"priority,omitempty"  json:"priority,omitempty"  yaml:"priority,omitempty"`
+	Directory   *string  `mapstructure:"directory,omitempty"     json:"directory,omitempty"     yaml:"directory,omitempty"`
+	Files       []string `mapstructure:"files,omitempty"         json:"files,omitempty"         yaml:"files,omitempty"`
+	StartAt     string   `mapstructure:"start_at,omitempty"      json:"start_at,omitempty"      yaml:"start_at,omitempty"      jsonschema:"enum=beginning|end"`
+	Units       []string `mapstructure:"units,omitempty"         json:"units,omitempty"         yaml:"units,omitempty"`
+	Priority    string   `mapstructure:"priority,omitempty"      json:"priority,omitempty"      yaml:"priority,omitempty"`
+	AtLeastOnce bool     `mapstructure:"at_least_once,omitempty" json:"at_least_once,omitempty" yaml:"at_least_once,omitempty"`
 }
 
 // Build will build a journald input operator from the supplied configuration
@@ -109,7 +112,8 @@ func (c JournaldInputConfig) Build(logger *zap.SugaredLogger) (operator.Operator
 			return exec.CommandContext(ctx, "journalctl", args...) // #nosec - ...
 			// journalctl is an executable that is required for this operator to function
 		},
-		json: jsoniter.ConfigFastest,
+		json:        jsoniter.ConfigFastest,
+		atLeastOnce: c.AtLeastOnce,
 	}, nil
 }
 
@@ -123,6 +127,9 @@ type JournaldInput struct {
 	json      jsoniter.API
 	cancel    context.CancelFunc
 	wg        sync.WaitGroup
+
+	atLeastOnce bool
+	acks        *helper.AckTracker
 }
 
 type cmd interface {
@@ -145,6 +152,13 @@ func (operator *JournaldInput) Start(persister operator.Persister) error {
 
 	operator.persister = persister
 
+	// When at-least-once delivery is enabled, the cursor
+	// is persisted once entries have been acknowledged
+	operator.acks = nil
+	if operator.atLeastOnce {
+		operator.acks = helper.NewAckTracker(nil, operator.persistCursor)
+	}
+
 	// Start journalctl
 	cmd := operator.newCmd(ctx, cursor)
 	stdout, err := cmd.StdoutPipe()
@@ -172,21 +186,67 @@ func (operator *JournaldInput) Start(persister operator.Persister) error {
 				return
 			}
 
-			entry, cursor, err := operator.parseJournalEntry(line)
+			cursor, err := operator.emit(ctx, line)
 			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
 				operator.Warnw("Failed to parse journal entry", zap.Error(err))
 				continue
 			}
-			if err := operator.persister.Set(ctx, lastReadCursorKey, []byte(cursor)); err != nil {
-				operator.Warnw("Failed to set offset", zap.Error(err))
+			if operator.acks == nil {
+				operator.persistCursor(cursor)
 			}
-			operator.Write(ctx, entry)
 		}
 	}()
 
 	return nil
 }
 
+// persistCursor saves the cursor of the last handled entry
+func (operator *JournaldInput) persistCursor(cursor interface{}) {
+	if err := operator.persister.Set(context.Background(), lastReadCursorKey, []byte(cursor.(string))); err != nil {
+		operator.Warnw("Failed to set offset", zap.Error(err))
+	}
+}
+
+// emit parses a journal entry and writes it to the outputs of the operator.
+// While downstream operators apply backpressure, the entry is parsed again and
+// retried, so that journalctl is not read further until the entry is accepted.
+func (operator *JournaldInput) emit(ctx context.Context, line []byte) (string, error) {
+	retry := backoff.Backoff{
+		Min: 10 * time.Millisecond,
+		Max: time.Second,
+	}
+
+	for {
+		entry, cursor, err := operator.parseJournalEntry(line)
+		if err != nil {
+			return "", err
+		}
+
+		var cancel func()
+		if operator.acks != nil {
+			cancel = operator.acks.Track(entry, cursor)
+		}
+
+		// Processing errors are handled by the operators that encountered them
+		if err := operator.Write(ctx, entry); !agenterrors.IsBackpressure(err) {
+			return cursor, nil
+		}
+
+		if cancel != nil {
+			cancel()
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(retry.Duration()):
+		}
+	}
+}
+
 func (operator *JournaldInput) parseJournalEntry(line []byte) (*entry.Entry, string, error) {
 	var body map[string]interface{}
 	err := operator.json.Unmarshal(line, &body)
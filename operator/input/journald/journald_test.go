@@ -29,6 +29,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
 	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
@@ -116,6 +117,89 @@ func TestInputJournald(t *testing.T) {
 	}
 }
 
+func TestInputJournaldBackpressure(t *testing.T) {
+	cfg := NewJournaldInputConfig("my_journald_input")
+	cfg.OutputIDs = []string{"output"}
+
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	mockOutput := testutil.NewMockOperator("output")
+	received := make(chan *entry.Entry)
+	mockOutput.On("Process", mock.Anything, mock.Anything).Return(errors.NewBackpressureError("output is unavailable")).Once()
+	mockOutput.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		received <- args.Get(1).(*entry.Entry)
+	}).Return(nil)
+
+	err = op.SetOutputs([]operator.Operator{mockOutput})
+	require.NoError(t, err)
+
+	op.(*JournaldInput).newCmd = func(ctx context.Context, cursor []byte) cmd {
+		return &fakeJournaldCmd{}
+	}
+
+	persister := testutil.NewMockPersister("test")
+	err = op.Start(persister)
+	require.NoError(t, err)
+	defer op.Stop()
+
+	select {
+	case e := <-received:
+		require.Equal(t, "run-docker-netns-4f76d707d45f.mount: Succeeded.", e.Body.(map[string]interface{})["MESSAGE"])
+	case <-time.After(time.Second):
+		require.FailNow(t, "Timed out waiting for entry to be retried")
+	}
+
+	require.Eventually(t, func() bool {
+		cursor, err := persister.Get(context.Background(), lastReadCursorKey)
+		return err == nil && len(cursor) > 0
+	}, time.Second, 10*time.Millisecond)
+	mockOutput.AssertNumberOfCalls(t, "Process", 2)
+}
+
+func TestInputJournaldAtLeastOnce(t *testing.T) {
+	cfg := NewJournaldInputConfig("my_journald_input")
+	cfg.OutputIDs = []string{"output"}
+	cfg.AtLeastOnce = true
+
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	mockOutput := testutil.NewMockOperator("output")
+	received := make(chan *entry.Entry, 1)
+	mockOutput.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		received <- args.Get(1).(*entry.Entry)
+	}).Return(nil)
+
+	err = op.SetOutputs([]operator.Operator{mockOutput})
+	require.NoError(t, err)
+
+	op.(*JournaldInput).newCmd = func(ctx context.Context, cursor []byte) cmd {
+		return &fakeJournaldCmd{}
+	}
+
+	persister := testutil.NewMockPersister("test")
+	err = op.Start(persister)
+	require.NoError(t, err)
+	defer op.Stop()
+
+	var e *entry.Entry
+	select {
+	case e = <-received:
+	case <-time.After(time.Second):
+		require.FailNow(t, "Timed out waiting for entry to be read")
+	}
+
+	cursor, err := persister.Get(context.Background(), lastReadCursorKey)
+	require.NoError(t, err)
+	require.Nil(t, cursor, "cursor should not be persisted before the entry is acknowledged")
+
+	e.Ack()
+	cursor, err = persister.Get(context.Background(), lastReadCursorKey)
+	require.NoError(t, err)
+	require.Equal(t, "s=b1e713b587ae4001a9ca482c4b12c005;i=1eed30;b=c4fa36de06824d21835c05ff80c54468;m=9f9d630205;t=5a369604ee333;x=16c2d4fd4fdb7c36", string(cursor))
+}
+
 func TestJournaldInputConfig(t *testing.T) {
 	expect := NewJournaldInputConfig("my_journald_input")
 
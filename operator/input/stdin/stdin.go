@@ -23,7 +23,6 @@ import (
 
 	"go.uber.org/zap"
 
-	"github.com/open-telemetry/opentelemetry-log-collection/entry"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
 )
@@ -100,7 +99,7 @@ func (g *StdinInput) Start(_ operator.Persister) error {
 				return
 			}
 
-			e := entry.New()
+			e := g.WriterOperator.NewEntry()
 			e.Body = scanner.Text()
 			g.Write(ctx, e)
 		}
@@ -15,6 +15,7 @@
 package syslog
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"testing"
@@ -81,7 +82,7 @@ func SyslogInputTest(t *testing.T, cfg *SyslogInputConfig, tc syslog.Case) {
 	conn.Close()
 	require.NoError(t, err)
 
-	defer p.Stop()
+	defer p.Stop(context.Background())
 	select {
 	case e := <-fake.Received:
 		// close pipeline to avoid data race
@@ -38,7 +38,7 @@ type EventLogConfig struct {
 	helper.InputConfig `mapstructure:",squash" yaml:",inline"`
 	Channel            string          `mapstructure:"channel" json:"channel" yaml:"channel"`
 	MaxReads           int             `mapstructure:"max_reads,omitempty" json:"max_reads,omitempty" yaml:"max_reads,omitempty"`
-	StartAt            string          `mapstructure:"start_at,omitempty" json:"start_at,omitempty" yaml:"start_at,omitempty"`
+	StartAt            string          `mapstructure:"start_at,omitempty" json:"start_at,omitempty" yaml:"start_at,omitempty" jsonschema:"enum=beginning|end"`
 	PollInterval       helper.Duration `mapstructure:"poll_interval,omitempty" json:"poll_interval,omitempty" yaml:"poll_interval,omitempty"`
 }
 
@@ -54,3 +54,10 @@ type Operator interface {
 	// Logger returns the operator's logger
 	Logger() *zap.SugaredLogger
 }
+
+// Drainer is implemented by operators that hold entries in memory.
+type Drainer interface {
+	// Drain is called before Stop, after all upstream operators have stopped.
+	// It should write all held entries to the operator's outputs before the context is done.
+	Drain(context.Context) error
+}
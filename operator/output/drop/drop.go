@@ -59,5 +59,20 @@ type DropOutput struct {
 
 // Process will drop the incoming entry.
 func (p *DropOutput) Process(ctx context.Context, entry *entry.Entry) error {
+	p.Telemetry().Received(ctx)
+	p.Telemetry().Dropped(ctx)
+	entry.Ack()
+	entry.Release()
+	return nil
+}
+
+// ProcessBatch will drop a batch of incoming entries.
+func (p *DropOutput) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	p.Telemetry().ReceivedBatch(ctx, len(entries))
+	for _, e := range entries {
+		p.Telemetry().Dropped(ctx)
+		e.Ack()
+		e.Release()
+	}
 	return nil
 }
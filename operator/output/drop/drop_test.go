@@ -47,3 +47,15 @@ func TestProcess(t *testing.T) {
 	result := op.Process(context.Background(), entry)
 	require.Nil(t, result)
 }
+
+func TestProcessAcknowledges(t *testing.T) {
+	cfg := NewDropOutputConfig("test")
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	acked := false
+	entry := entry.New()
+	entry.OnAck(func() { acked = true })
+	require.NoError(t, op.Process(context.Background(), entry))
+	require.True(t, acked)
+}
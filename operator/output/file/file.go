@@ -21,7 +21,9 @@ import (
 	"html/template"
 	"os"
 	"sync"
+	"time"
 
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
@@ -111,9 +113,32 @@ func (fo *FileOutput) Stop() error {
 
 // Process will write an entry to the output file.
 func (fo *FileOutput) Process(ctx context.Context, entry *entry.Entry) error {
+	fo.Telemetry().Received(ctx)
+	defer fo.Telemetry().RecordDuration(ctx, time.Now())
+
+	fo.mux.Lock()
+	defer fo.mux.Unlock()
+
+	return fo.write(entry)
+}
+
+// ProcessBatch will write a batch of entries to the output file.
+func (fo *FileOutput) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	fo.Telemetry().ReceivedBatch(ctx, len(entries))
+	defer fo.Telemetry().RecordDuration(ctx, time.Now())
+
 	fo.mux.Lock()
 	defer fo.mux.Unlock()
 
+	var errs error
+	for _, e := range entries {
+		errs = multierr.Append(errs, fo.write(e))
+	}
+	return errs
+}
+
+// write writes an entry to the output file. It must be called while holding the lock.
+func (fo *FileOutput) write(entry *entry.Entry) error {
 	if fo.tmpl != nil {
 		err := fo.tmpl.Execute(fo.file, entry)
 		if err != nil {
@@ -126,5 +151,7 @@ func (fo *FileOutput) Process(ctx context.Context, entry *entry.Entry) error {
 		}
 	}
 
+	entry.Ack()
+	entry.Release()
 	return nil
 }
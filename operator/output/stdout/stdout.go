@@ -20,7 +20,9 @@ import (
 	"io"
 	"os"
 	"sync"
+	"time"
 
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
@@ -69,6 +71,9 @@ type StdoutOperator struct {
 
 // Process will log entries received.
 func (o *StdoutOperator) Process(ctx context.Context, entry *entry.Entry) error {
+	o.Telemetry().Received(ctx)
+	defer o.Telemetry().RecordDuration(ctx, time.Now())
+
 	o.mux.Lock()
 	err := o.encoder.Encode(entry)
 	if err != nil {
@@ -77,5 +82,28 @@ func (o *StdoutOperator) Process(ctx context.Context, entry *entry.Entry) error
 		return err
 	}
 	o.mux.Unlock()
+	entry.Ack()
+	entry.Release()
 	return nil
 }
+
+// ProcessBatch will log a batch of entries received.
+func (o *StdoutOperator) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	o.Telemetry().ReceivedBatch(ctx, len(entries))
+	defer o.Telemetry().RecordDuration(ctx, time.Now())
+
+	o.mux.Lock()
+	defer o.mux.Unlock()
+
+	var errs error
+	for _, e := range entries {
+		if err := o.encoder.Encode(e); err != nil {
+			o.Errorf("Failed to process entry: %s, %s", err, e.Body)
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		e.Ack()
+		e.Release()
+	}
+	return errs
+}
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
@@ -63,3 +64,25 @@ func TestStdoutOperator(t *testing.T) {
 	expected := `{"observed_timestamp":` + string(marshalledOTS) + `,"timestamp":` + string(marshalledTs) + `,"body":"test body","severity":0,"scope_name":""}` + "\n"
 	require.Equal(t, expected, buf.String())
 }
+
+func TestStdoutOperatorProcessBatch(t *testing.T) {
+	cfg := NewStdoutConfig("test_operator_id")
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	op.(*StdoutOperator).encoder = json.NewEncoder(&buf)
+
+	ts := time.Unix(1591042864, 0)
+	entries := []*entry.Entry{
+		{Timestamp: ts, ObservedTimestamp: ts, Body: "first"},
+		{Timestamp: ts, ObservedTimestamp: ts, Body: "second"},
+	}
+	err = op.(*StdoutOperator).ProcessBatch(context.Background(), entries)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], `"body":"first"`)
+	require.Contains(t, lines[1], `"body":"second"`)
+}
@@ -161,6 +161,10 @@ func generateParseFunc(headers []string, fieldDelimiter rune, lazyQuotes bool) p
 			lines = append(lines, line)
 		}
 
+		if len(lines) == 0 {
+			return nil, errors.New("no csv lines found")
+		}
+
 		/*
 			This parser is parsing a single value, which came from a single log entry.
 			Therefore, if there are multiple lines here, it should be assumed that each
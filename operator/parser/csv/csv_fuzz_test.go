@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package csv
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+)
+
+func FuzzCSVParser(f *testing.F) {
+	for _, tc := range parserCSVCases() {
+		config := NewCSVParserConfig("test")
+		tc.configure(config)
+		for _, e := range tc.inputEntries {
+			body, ok := e.Body.(string)
+			if !ok {
+				continue
+			}
+			header := config.Header
+			if config.HeaderAttribute != "" {
+				header, _ = e.Attributes[config.HeaderAttribute].(string)
+			}
+			f.Add(header, config.FieldDelimiter, config.LazyQuotes, body)
+		}
+	}
+	for _, tc := range parserCSVMultilineCases() {
+		f.Add(parserCSVMultilineHeader, ",", false, tc.input)
+	}
+
+	f.Fuzz(func(t *testing.T, header string, delimiter string, lazyQuotes bool, body string) {
+		staticConfig := NewCSVParserConfig("test")
+		staticConfig.Header = header
+		staticConfig.FieldDelimiter = delimiter
+		staticConfig.LazyQuotes = lazyQuotes
+
+		dynamicConfig := NewCSVParserConfig("test")
+		dynamicConfig.HeaderAttribute = "header"
+		dynamicConfig.FieldDelimiter = delimiter
+		dynamicConfig.LazyQuotes = lazyQuotes
+
+		for _, config := range []operator.Builder{staticConfig, dynamicConfig} {
+			op, err := config.Build(zap.NewNop().Sugar())
+			if err != nil {
+				continue
+			}
+
+			e := entry.New()
+			e.Body = body
+			e.AddAttribute("header", header)
+			_ = op.Process(context.Background(), e)
+		}
+	})
+}
@@ -4,7 +4,7 @@
 // you may not use this file except in compliance with the License.
 // You may obtain a copy of the License at
 //
-//     http://www.apache.org/licenses/LICENSE-2.0
+//	http://www.apache.org/licenses/LICENSE-2.0
 //
 // Unless required by applicable law or agreed to in writing, software
 // distributed under the License is distributed on an "AS IS" BASIS,
@@ -75,6 +75,13 @@ func TestCSVParserStringFailure(t *testing.T) {
 	require.Contains(t, err.Error(), "wrong number of fields")
 }
 
+func TestCSVParserEmptyString(t *testing.T) {
+	parser := newTestParser(t)
+	_, err := parser.parse("")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no csv lines found")
+}
+
 func TestCSVParserInvalidType(t *testing.T) {
 	parser := newTestParser(t)
 	_, err := parser.parse([]int{})
@@ -82,15 +89,18 @@ func TestCSVParserInvalidType(t *testing.T) {
 	require.Contains(t, err.Error(), "type '[]int' cannot be parsed as csv")
 }
 
-func TestParserCSV(t *testing.T) {
-	cases := []struct {
-		name             string
-		configure        func(*CSVParserConfig)
-		inputEntries     []entry.Entry
-		expectedEntries  []entry.Entry
-		expectBuildErr   bool
-		expectProcessErr bool
-	}{
+type parserCSVCase struct {
+	name             string
+	configure        func(*CSVParserConfig)
+	inputEntries     []entry.Entry
+	expectedEntries  []entry.Entry
+	expectBuildErr   bool
+	expectProcessErr bool
+}
+
+// parserCSVCases are also used to seed FuzzCSVParser
+func parserCSVCases() []parserCSVCase {
+	return []parserCSVCase{
 		{
 			"basic",
 			func(p *CSVParserConfig) {
@@ -293,10 +303,10 @@ func TestParserCSV(t *testing.T) {
 				{
 					Attributes: map[string]interface{}{
 						"columns": "name	age	height	number",
-						"name":   "stanza dev",
-						"age":    "1",
-						"height": "400",
-						"number": "555-555-5555",
+						"name":    "stanza dev",
+						"age":     "1",
+						"height":  "400",
+						"number":  "555-555-5555",
 					},
 					Body: "stanza dev	1	400	555-555-5555",
 				},
@@ -609,8 +619,10 @@ func TestParserCSV(t *testing.T) {
 			false,
 		},
 	}
+}
 
-	for _, tc := range cases {
+func TestParserCSV(t *testing.T) {
+	for _, tc := range parserCSVCases() {
 		t.Run(tc.name, func(t *testing.T) {
 			cfg := NewCSVParserConfig("test")
 			cfg.OutputIDs = []string{"fake"}
@@ -644,12 +656,18 @@ func TestParserCSV(t *testing.T) {
 	}
 }
 
-func TestParserCSVMultiline(t *testing.T) {
-	cases := []struct {
-		name     string
-		input    string
-		expected map[string]interface{}
-	}{
+type parserCSVMultilineCase struct {
+	name     string
+	input    string
+	expected map[string]interface{}
+}
+
+// parserCSVMultilineHeader is the header of parserCSVMultilineCases
+const parserCSVMultilineHeader = "A,B,C,D,E"
+
+// parserCSVMultilineCases are also used to seed FuzzCSVParser
+func parserCSVMultilineCases() []parserCSVMultilineCase {
+	return []parserCSVMultilineCase{
 		{
 			"no_newlines",
 			"aaaa,bbbb,cccc,dddd,eeee",
@@ -852,12 +870,15 @@ cc""",dddd,eeee`,
 			},
 		},
 	}
-	for _, tc := range cases {
+}
+
+func TestParserCSVMultiline(t *testing.T) {
+	for _, tc := range parserCSVMultilineCases() {
 		t.Run(tc.name, func(t *testing.T) {
 			cfg := NewCSVParserConfig("test")
 			cfg.ParseTo = entry.NewBodyField()
 			cfg.OutputIDs = []string{"fake"}
-			cfg.Header = "A,B,C,D,E"
+			cfg.Header = parserCSVMultilineHeader
 
 			op, err := cfg.Build(testutil.Logger(t))
 			require.NoError(t, err)
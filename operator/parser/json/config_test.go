@@ -51,6 +51,15 @@ func TestJSONParserConfig(t *testing.T) {
 				return cfg
 			}(),
 		},
+		{
+			Name: "on_error_dead_letter",
+			Expect: func() *JSONParserConfig {
+				cfg := defaultCfg()
+				cfg.OnError = "dead_letter"
+				cfg.DeadLetterOutput = []string{"dead_letter_file"}
+				return cfg
+			}(),
+		},
 		{
 			Name: "timestamp",
 			Expect: func() *JSONParserConfig {
@@ -66,6 +66,11 @@ func (j *JSONParser) Process(ctx context.Context, entry *entry.Entry) error {
 	return j.ParserOperator.ProcessWith(ctx, entry, j.parse)
 }
 
+// ProcessBatch will parse a batch of entries for JSON.
+func (j *JSONParser) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return j.ParserOperator.ProcessBatchWith(ctx, entries, j.parse)
+}
+
 // parse will parse a value as JSON.
 func (j *JSONParser) parse(value interface{}) (interface{}, error) {
 	var parsedValue map[string]interface{}
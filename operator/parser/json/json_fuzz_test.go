@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package json
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
+)
+
+func FuzzJSONParser(f *testing.F) {
+	for _, tc := range jsonParserCases() {
+		if body, ok := tc.input.Body.(string); ok {
+			f.Add(body)
+		}
+	}
+
+	config := NewJSONParserConfig("test")
+	timeFrom := entry.NewAttributeField("timestamp")
+	config.TimeParser = &helper.TimeParser{
+		ParseFrom:  &timeFrom,
+		LayoutType: helper.EpochKey,
+		Layout:     "s.ms",
+	}
+	severityFrom := entry.NewAttributeField("severity")
+	config.SeverityParserConfig = &helper.SeverityParserConfig{
+		ParseFrom: &severityFrom,
+		Preset:    "http",
+	}
+	op, err := config.Build(zap.NewNop().Sugar())
+	require.NoError(f, err)
+
+	f.Fuzz(func(t *testing.T, body string) {
+		e := entry.New()
+		e.Body = body
+		_ = op.Process(context.Background(), e)
+	})
+}
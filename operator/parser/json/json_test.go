@@ -75,13 +75,16 @@ func TestJSONImplementations(t *testing.T) {
 	require.Implements(t, (*operator.Operator)(nil), new(JSONParser))
 }
 
-func TestJSONParser(t *testing.T) {
-	cases := []struct {
-		name      string
-		configure func(*JSONParserConfig)
-		input     *entry.Entry
-		expect    *entry.Entry
-	}{
+type jsonParserCase struct {
+	name      string
+	configure func(*JSONParserConfig)
+	input     *entry.Entry
+	expect    *entry.Entry
+}
+
+// jsonParserCases are also used to seed FuzzJSONParser
+func jsonParserCases() []jsonParserCase {
+	return []jsonParserCase{
 		{
 			"simple",
 			func(p *JSONParserConfig) {},
@@ -148,8 +151,10 @@ func TestJSONParser(t *testing.T) {
 			},
 		},
 	}
+}
 
-	for _, tc := range cases {
+func TestJSONParser(t *testing.T) {
+	for _, tc := range jsonParserCases() {
 		t.Run(tc.name, func(t *testing.T) {
 			cfg := NewJSONParserConfig("test")
 			cfg.OutputIDs = []string{"fake"}
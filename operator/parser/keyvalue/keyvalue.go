@@ -91,6 +91,11 @@ func (kv *KVParser) Process(ctx context.Context, entry *entry.Entry) error {
 	return kv.ParserOperator.ProcessWith(ctx, entry, kv.parse)
 }
 
+// ProcessBatch will parse a batch of entries for key value pairs.
+func (kv *KVParser) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return kv.ParserOperator.ProcessBatchWith(ctx, entries, kv.parse)
+}
+
 // parse will parse a value as key values.
 func (kv *KVParser) parse(value interface{}) (interface{}, error) {
 	switch m := value.(type) {
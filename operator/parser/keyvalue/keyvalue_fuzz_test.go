@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package keyvalue
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+)
+
+func FuzzKVParser(f *testing.F) {
+	for _, tc := range kvParserCases() {
+		body, ok := tc.input.Body.(string)
+		if !ok {
+			continue
+		}
+		config := NewKVParserConfig("test")
+		tc.configure(config)
+		f.Add(config.Delimiter, config.PairDelimiter, body)
+	}
+
+	f.Fuzz(func(t *testing.T, delimiter string, pairDelimiter string, body string) {
+		config := NewKVParserConfig("test")
+		config.Delimiter = delimiter
+		config.PairDelimiter = pairDelimiter
+		op, err := config.Build(zap.NewNop().Sugar())
+		if err != nil {
+			return
+		}
+
+		e := entry.New()
+		e.Body = body
+		_ = op.Process(context.Background(), e)
+	})
+}
@@ -154,15 +154,18 @@ func TestKVImplementations(t *testing.T) {
 	require.Implements(t, (*operator.Operator)(nil), new(KVParser))
 }
 
-func TestKVParser(t *testing.T) {
-	cases := []struct {
-		name           string
-		configure      func(*KVParserConfig)
-		input          *entry.Entry
-		expect         *entry.Entry
-		expectError    bool
-		expectBuildErr bool
-	}{
+type kvParserCase struct {
+	name           string
+	configure      func(*KVParserConfig)
+	input          *entry.Entry
+	expect         *entry.Entry
+	expectError    bool
+	expectBuildErr bool
+}
+
+// kvParserCases are also used to seed FuzzKVParser
+func kvParserCases() []kvParserCase {
+	return []kvParserCase{
 		{
 			"simple",
 			func(kv *KVParserConfig) {},
@@ -517,8 +520,10 @@ func TestKVParser(t *testing.T) {
 			true,
 		},
 	}
+}
 
-	for _, tc := range cases {
+func TestKVParser(t *testing.T) {
+	for _, tc := range kvParserCases() {
 		t.Run(tc.name, func(t *testing.T) {
 			cfg := NewKVParserConfig("test")
 			cfg.OutputIDs = []string{"fake"}
@@ -19,6 +19,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/clock"
 )
 
 // cache allows operators to cache a value and look it up later
@@ -27,6 +29,8 @@ type cache interface {
 	add(key string, data interface{}) bool
 	copy() map[string]interface{}
 	maxSize() uint16
+	start(clock.Clock)
+	stop()
 }
 
 // newMemoryCache takes a cache size and a limiter interval and
@@ -38,7 +42,7 @@ func newMemoryCache(maxSize uint16, interval uint64) *memoryCache {
 	return &memoryCache{
 		cache:   make(map[string]interface{}),
 		keys:    make(chan string, maxSize),
-		limiter: newStartedAtomicLimiter(limit, interval),
+		limiter: newAtomicLimiter(limit, interval),
 	}
 }
 
@@ -122,10 +126,21 @@ func (m *memoryCache) maxSize() uint16 {
 	return uint16(cap(m.keys))
 }
 
+// start starts resetting the limiter of the cache
+func (m *memoryCache) start(clk clock.Clock) {
+	m.limiter.start(clk)
+}
+
+// stop stops resetting the limiter of the cache
+func (m *memoryCache) stop() {
+	m.limiter.stop()
+}
+
 // limiter provides rate limiting methods for
 // the cache
 type limiter interface {
-	init()
+	start(clock.Clock)
+	stop()
 	increment()
 	currentCount() uint64
 	limit() uint64
@@ -133,20 +148,18 @@ type limiter interface {
 	throttled() bool
 }
 
-// newStartedAtomicLimiter returns a started atomicLimiter
-func newStartedAtomicLimiter(max uint64, interval uint64) *atomicLimiter {
+// newAtomicLimiter returns an atomicLimiter, which is reset once it is started
+func newAtomicLimiter(max uint64, interval uint64) *atomicLimiter {
 	if interval == 0 {
 		interval = 5
 	}
 
-	a := &atomicLimiter{
+	return &atomicLimiter{
 		count:    0,
 		max:      max,
 		interval: time.Second * time.Duration(interval),
+		done:     make(chan struct{}),
 	}
-
-	a.init()
-	return a
 }
 
 // atomicLimiter enables rate limiting using an atomic
@@ -156,21 +169,27 @@ type atomicLimiter struct {
 	count    uint64
 	max      uint64
 	interval time.Duration
-	start    sync.Once
+	started  sync.Once
+	stopped  sync.Once
+	done     chan struct{}
 }
 
 var _ limiter = (&atomicLimiter{})
 
-// init initializes the limiter
-func (l *atomicLimiter) init() {
+// start starts the go routine that resets the limiter
+func (l *atomicLimiter) start(clk clock.Clock) {
 	// start the reset go routine once
-	l.start.Do(func() {
+	l.started.Do(func() {
 		go func() {
 			// During every interval period, reduce the counter
 			// by 10%
 			x := math.Round(-0.10 * float64(l.max))
 			for {
-				time.Sleep(l.interval)
+				select {
+				case <-l.done:
+					return
+				case <-clk.After(l.interval):
+				}
 				if l.currentCount() > 0 {
 					atomic.AddUint64(&l.count, ^uint64(x))
 				}
@@ -179,6 +198,13 @@ func (l *atomicLimiter) init() {
 	})
 }
 
+// stop stops the go routine that resets the limiter
+func (l *atomicLimiter) stop() {
+	l.stopped.Do(func() {
+		close(l.done)
+	})
+}
+
 // increment increments the atomic counter
 func (l *atomicLimiter) increment() {
 	if l.count == l.max {
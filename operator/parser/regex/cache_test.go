@@ -20,6 +20,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
 )
 
 func TestNewMemoryCache(t *testing.T) {
@@ -158,7 +160,7 @@ func TestCleanupLast(t *testing.T) {
 	require.Len(t, m.cache, maxSize)
 }
 
-func TestNewStartedAtomicLimiter(t *testing.T) {
+func TestNewAtomicLimiter(t *testing.T) {
 	cases := []struct {
 		name     string
 		max      uint64
@@ -183,7 +185,7 @@ func TestNewStartedAtomicLimiter(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			l := newStartedAtomicLimiter(tc.max, tc.interval)
+			l := newAtomicLimiter(tc.max, tc.interval)
 			require.Equal(t, tc.max, l.max)
 			if tc.interval == 0 {
 				// default
@@ -199,7 +201,7 @@ func TestNewStartedAtomicLimiter(t *testing.T) {
 func TestLimiter(t *testing.T) {
 	max := uint64(3)
 
-	l := newStartedAtomicLimiter(max, 120)
+	l := newAtomicLimiter(max, 120)
 	require.NotNil(t, l)
 	require.Equal(t, max, l.max)
 
@@ -221,24 +223,28 @@ func TestThrottledLimiter(t *testing.T) {
 	max := uint64(3)
 
 	// Limiter with a count higher than the max, which will force
-	// it to be throttled by default. Also note that the init method
-	// has not been called yet, so the reset go routine is not running
-	l := atomicLimiter{
-		max:      max,
-		count:    max + 1,
-		interval: 1,
-	}
+	// it to be throttled by default. Also note that the limiter
+	// has not been started yet, so the reset go routine is not running
+	l := newAtomicLimiter(max, 1)
+	l.count = max + 1
 
 	require.True(t, l.throttled())
 
-	// Test	the reset go routine by calling init() and waiting
-	// for it to reset the counter. The limiter will no longer
-	// be in a throttled state and the count will be reset.
-	l.init()
-	wait := 2 * l.interval
-	time.Sleep(time.Second * wait)
+	// Test the reset go routine by starting it and advancing the clock
+	// until it resets the counter. The limiter will no longer be in a
+	// throttled state and the count will be reset.
+	clock := testutil.NewFakeClock(time.Now())
+	l.start(clock)
+	defer l.stop()
+	for i := 0; i < int(max)+1; i++ {
+		clock.WaitForWaiters(1)
+		clock.Advance(l.interval)
+	}
+
+	require.Eventually(t, func() bool {
+		return l.currentCount() == 0
+	}, time.Second, time.Millisecond)
 	require.False(t, l.throttled())
-	require.Equal(t, uint64(0), l.currentCount())
 }
 
 func TestThrottledCache(t *testing.T) {
@@ -98,11 +98,32 @@ type RegexParser struct {
 	cache  cache
 }
 
+// Start will start resetting the limiter of the cache.
+func (r *RegexParser) Start(p operator.Persister) error {
+	if r.cache != nil {
+		r.cache.start(r.Clock())
+	}
+	return r.ParserOperator.Start(p)
+}
+
+// Stop will stop resetting the limiter of the cache.
+func (r *RegexParser) Stop() error {
+	if r.cache != nil {
+		r.cache.stop()
+	}
+	return r.ParserOperator.Stop()
+}
+
 // Process will parse an entry for regex.
 func (r *RegexParser) Process(ctx context.Context, entry *entry.Entry) error {
 	return r.ParserOperator.ProcessWith(ctx, entry, r.parse)
 }
 
+// ProcessBatch will parse a batch of entries for regex.
+func (r *RegexParser) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return r.ParserOperator.ProcessBatchWith(ctx, entries, r.parse)
+}
+
 // parse will parse a value using the supplied regex.
 func (r *RegexParser) parse(value interface{}) (interface{}, error) {
 	var raw string
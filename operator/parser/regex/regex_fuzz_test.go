@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package regex
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
+)
+
+func FuzzRegexParser(f *testing.F) {
+	bodies := make([]string, 0)
+	for _, tc := range parserRegexCases() {
+		config := NewRegexParserConfig("test")
+		tc.configure(config)
+		body, _ := tc.input.Body.(string)
+		f.Add(config.Regex, body)
+		bodies = append(bodies, body)
+	}
+
+	// The regexes of the config testdata are paired with the bodies of the test cases
+	files, err := filepath.Glob(filepath.Join("testdata", "*.yaml"))
+	require.NoError(f, err)
+	for _, file := range files {
+		raw, err := ioutil.ReadFile(file)
+		require.NoError(f, err)
+		var config struct {
+			Regex string `yaml:"regex"`
+		}
+		require.NoError(f, yaml.Unmarshal(raw, &config))
+		if config.Regex == "" {
+			continue
+		}
+		for _, body := range bodies {
+			f.Add(config.Regex, body)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, regex string, body string) {
+		for _, cacheSize := range []uint16{0, 2} {
+			config := NewRegexParserConfig("test")
+			config.Regex = regex
+			config.Cache.Size = cacheSize
+			op, err := config.Build(zap.NewNop().Sugar())
+			if err != nil {
+				return
+			}
+			if err := op.Start(testutil.NewMockPersister("test")); err != nil {
+				t.Fatal(err)
+			}
+
+			// Process the body repeatedly, so that it is read from the cache
+			for i := 0; i < 3; i++ {
+				e := entry.New()
+				e.Body = body
+				_ = op.Process(context.Background(), e)
+			}
+			_ = op.Stop()
+		}
+	})
+}
@@ -80,13 +80,16 @@ func TestRegexParserCache(t *testing.T) {
 	require.Equal(t, parser.cache.maxSize(), uint16(200))
 }
 
-func TestParserRegex(t *testing.T) {
-	cases := []struct {
-		name      string
-		configure func(*RegexParserConfig)
-		input     *entry.Entry
-		expected  *entry.Entry
-	}{
+type parserRegexCase struct {
+	name      string
+	configure func(*RegexParserConfig)
+	input     *entry.Entry
+	expected  *entry.Entry
+}
+
+// parserRegexCases are also used to seed FuzzRegexParser
+func parserRegexCases() []parserRegexCase {
+	return []parserRegexCase{
 		{
 			"RootString",
 			func(p *RegexParserConfig) {
@@ -138,8 +141,10 @@ func TestParserRegex(t *testing.T) {
 			},
 		},
 	}
+}
 
-	for _, tc := range cases {
+func TestParserRegex(t *testing.T) {
+	for _, tc := range parserRegexCases() {
 		t.Run(tc.name, func(t *testing.T) {
 			cfg := NewRegexParserConfig("test")
 			cfg.OutputIDs = []string{"fake"}
@@ -65,3 +65,8 @@ type ScopeNameParserOperator struct {
 func (p *ScopeNameParserOperator) Process(ctx context.Context, entry *entry.Entry) error {
 	return p.ProcessWith(ctx, entry, p.Parse)
 }
+
+// ProcessBatch will parse logger name from a batch of entries.
+func (p *ScopeNameParserOperator) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return p.ProcessBatchWith(ctx, entries, p.Parse)
+}
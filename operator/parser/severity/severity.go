@@ -70,3 +70,8 @@ type SeverityParserOperator struct {
 func (p *SeverityParserOperator) Process(ctx context.Context, entry *entry.Entry) error {
 	return p.ProcessWith(ctx, entry, p.Parse)
 }
+
+// ProcessBatch will parse severity from a batch of entries.
+func (p *SeverityParserOperator) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return p.ProcessBatchWith(ctx, entries, p.Parse)
+}
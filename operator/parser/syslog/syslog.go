@@ -51,7 +51,7 @@ type SyslogParserConfig struct {
 
 // SyslogBaseConfig is the detailed configuration of a syslog parser.
 type SyslogBaseConfig struct {
-	Protocol string `mapstructure:"protocol,omitempty" json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	Protocol string `mapstructure:"protocol,omitempty" json:"protocol,omitempty" yaml:"protocol,omitempty" jsonschema:"enum=rfc3164|rfc5424"`
 	Location string `mapstructure:"location,omitempty" json:"location,omitempty" yaml:"location,omitempty"`
 }
 
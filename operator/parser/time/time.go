@@ -19,6 +19,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-log-collection/clock"
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
@@ -65,7 +66,18 @@ type TimeParserOperator struct {
 	helper.TimeParser
 }
 
+// SetClock will set the clock used by the operator and its time parser.
+func (t *TimeParserOperator) SetClock(c clock.Clock) {
+	t.TransformerOperator.SetClock(c)
+	t.TimeParser.SetClock(c)
+}
+
 // Process will parse time from an entry.
 func (t *TimeParserOperator) Process(ctx context.Context, entry *entry.Entry) error {
 	return t.ProcessWith(ctx, entry, t.TimeParser.Parse)
 }
+
+// ProcessBatch will parse time from a batch of entries.
+func (t *TimeParserOperator) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return t.ProcessBatchWith(ctx, entries, t.TimeParser.Parse)
+}
@@ -69,3 +69,8 @@ type TraceParserOperator struct {
 func (p *TraceParserOperator) Process(ctx context.Context, entry *entry.Entry) error {
 	return p.ProcessWith(ctx, entry, p.Parse)
 }
+
+// ProcessBatch will parse traces from a batch of entries.
+func (p *TraceParserOperator) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return p.ProcessBatchWith(ctx, entries, p.Parse)
+}
@@ -65,6 +65,11 @@ func (u *URIParser) Process(ctx context.Context, entry *entry.Entry) error {
 	return u.ParserOperator.ProcessWith(ctx, entry, u.parse)
 }
 
+// ProcessBatch will parse a batch of entries.
+func (u *URIParser) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return u.ParserOperator.ProcessBatchWith(ctx, entries, u.parse)
+}
+
 // parse will parse a uri from a field and attach it to an entry.
 func (u *URIParser) parse(value interface{}) (interface{}, error) {
 	switch m := value.(type) {
@@ -213,13 +213,16 @@ func TestURIParserParse(t *testing.T) {
 }
 
 // Test all usecases: absolute uri, relative uri, query string
-func TestParseURI(t *testing.T) {
-	cases := []struct {
-		name       string
-		inputBody  string
-		outputBody map[string]interface{}
-		expectErr  bool
-	}{
+type parseURICase struct {
+	name       string
+	inputBody  string
+	outputBody map[string]interface{}
+	expectErr  bool
+}
+
+// parseURICases are also used to seed FuzzURIParser
+func parseURICases() []parseURICase {
+	return []parseURICase{
 		{
 			"scheme-http",
 			"http://",
@@ -480,8 +483,10 @@ func TestParseURI(t *testing.T) {
 			true,
 		},
 	}
+}
 
-	for _, tc := range cases {
+func TestParseURI(t *testing.T) {
+	for _, tc := range parseURICases() {
 		t.Run(tc.name, func(t *testing.T) {
 			x, err := parseURI(tc.inputBody)
 			if tc.expectErr {
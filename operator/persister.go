@@ -16,7 +16,7 @@ package operator
 
 import (
 	"context"
-	"fmt"
+	"strings"
 )
 
 // Persister is an interface used to persist data
@@ -39,11 +39,32 @@ func NewScopedPersister(s string, p Persister) Persister {
 }
 
 func (p scopedPersister) Get(ctx context.Context, key string) ([]byte, error) {
-	return p.Persister.Get(ctx, fmt.Sprintf("%s.%s", p.scope, key))
+	return p.Persister.Get(ctx, ScopedKey(p.scope, key))
 }
 func (p scopedPersister) Set(ctx context.Context, key string, value []byte) error {
-	return p.Persister.Set(ctx, fmt.Sprintf("%s.%s", p.scope, key), value)
+	return p.Persister.Set(ctx, ScopedKey(p.scope, key), value)
 }
 func (p scopedPersister) Delete(ctx context.Context, key string) error {
-	return p.Persister.Delete(ctx, fmt.Sprintf("%s.%s", p.scope, key))
+	return p.Persister.Delete(ctx, ScopedKey(p.scope, key))
+}
+
+var (
+	scopeEscaper   = strings.NewReplacer("%", "%25", ".", "%2E")
+	scopeUnescaper = strings.NewReplacer("%25", "%", "%2E", ".")
+)
+
+// ScopedKey returns the key under which a scoped persister stores a key of its scope.
+// Dots in the scope are escaped, so that the scope of a key ends at its first dot.
+func ScopedKey(scope, key string) string {
+	return scopeEscaper.Replace(scope) + "." + key
+}
+
+// SplitScopedKey returns the scope and key of a key stored by a scoped persister.
+// It returns false if the key does not have a scope.
+func SplitScopedKey(scopedKey string) (scope, key string, ok bool) {
+	i := strings.IndexByte(scopedKey, '.')
+	if i < 0 {
+		return "", scopedKey, false
+	}
+	return scopeUnescaper.Replace(scopedKey[:i]), scopedKey[i+1:], true
 }
@@ -14,35 +14,187 @@
 
 package operator
 
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+)
+
 // DefaultRegistry is a global registry of operator types to operator builders.
 var DefaultRegistry = NewRegistry()
 
 // Registry is used to track and retrieve known operator types
 type Registry struct {
-	operators map[string]func() Builder
+	mux        sync.RWMutex
+	operators  map[string]func() Builder
+	aliases    map[string]string
+	deprecated map[string]string
+	allowed    map[string]bool
+	denied     map[string]bool
 }
 
 // NewRegistry creates a new registry
 func NewRegistry() *Registry {
 	return &Registry{
-		operators: make(map[string]func() Builder),
+		operators:  make(map[string]func() Builder),
+		aliases:    make(map[string]string),
+		deprecated: make(map[string]string),
+		denied:     make(map[string]bool),
+	}
+}
+
+// Clone creates a copy of the registry, which can be configured independently.
+func (r *Registry) Clone() *Registry {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	clone := NewRegistry()
+	for operatorType, newBuilder := range r.operators {
+		clone.operators[operatorType] = newBuilder
 	}
+	for alias, operatorType := range r.aliases {
+		clone.aliases[alias] = operatorType
+	}
+	for name, message := range r.deprecated {
+		clone.deprecated[name] = message
+	}
+	if r.allowed != nil {
+		clone.allowed = make(map[string]bool, len(r.allowed))
+		for operatorType := range r.allowed {
+			clone.allowed[operatorType] = true
+		}
+	}
+	for operatorType := range r.denied {
+		clone.denied[operatorType] = true
+	}
+	return clone
 }
 
 // Register will register a function to an operator type.
 // This function will return a builder for the supplied type.
 func (r *Registry) Register(operatorType string, newBuilder func() Builder) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
 	r.operators[operatorType] = newBuilder
 }
 
-// Lookup looks up a given operator type. Its second return value will
-// be false if no builder is registered for that type.
+// RegisterAlias will register an alternative name for an operator type.
+// Configs that use the alias are unmarshalled as configs of the operator type.
+func (r *Registry) RegisterAlias(alias, operatorType string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.aliases[alias] = operatorType
+}
+
+// Deprecate marks an operator type or alias as deprecated. A warning
+// including the message is logged when an operator is built from a config that uses it.
+func (r *Registry) Deprecate(name, message string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.deprecated[name] = message
+}
+
+// Allow restricts the registry to the supplied operator types, in addition to
+// any types that were previously allowed. By default, all operator types are allowed.
+func (r *Registry) Allow(operatorTypes ...string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if r.allowed == nil {
+		r.allowed = make(map[string]bool, len(operatorTypes))
+	}
+	for _, operatorType := range operatorTypes {
+		r.allowed[operatorType] = true
+	}
+}
+
+// Deny prevents the supplied operator types from being used, even if they are allowed.
+func (r *Registry) Deny(operatorTypes ...string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for _, operatorType := range operatorTypes {
+		r.denied[operatorType] = true
+	}
+}
+
+// IsAllowed returns true if an operator type, or the type of an alias, may be used.
+func (r *Registry) IsAllowed(name string) bool {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return r.isAllowed(r.canonicalType(name))
+}
+
+// Lookup looks up a given operator type or alias. Its second return value will
+// be false if no builder is registered for that type, or if the type is not allowed.
 func (r *Registry) Lookup(configType string) (func() Builder, bool) {
-	b, ok := r.operators[configType]
-	if ok {
-		return b, ok
+	_, newBuilder, err := r.resolve(configType)
+	if err != nil {
+		return nil, false
+	}
+	return newBuilder, true
+}
+
+// Types returns the allowed operator types in sorted order. Aliases are not included.
+func (r *Registry) Types() []string {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	types := make([]string, 0, len(r.operators))
+	for operatorType := range r.operators {
+		if r.isAllowed(operatorType) {
+			types = append(types, operatorType)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// resolve returns the operator type and builder function of an operator type or alias
+func (r *Registry) resolve(name string) (string, func() Builder, error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	operatorType := r.canonicalType(name)
+	newBuilder, ok := r.operators[operatorType]
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported type '%s'", name)
 	}
-	return nil, false
+
+	if !r.isAllowed(operatorType) {
+		return "", nil, errors.NewError(
+			fmt.Sprintf("operator type '%s' is not allowed", operatorType),
+			"use one of the operator types that are allowed by the registry",
+			"operator_type", operatorType,
+		)
+	}
+	return operatorType, newBuilder, nil
+}
+
+// deprecation returns the deprecation message of an operator type or alias
+func (r *Registry) deprecation(name string) (string, bool) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+
+	if message, ok := r.deprecated[name]; ok {
+		return message, true
+	}
+	message, ok := r.deprecated[r.canonicalType(name)]
+	return message, ok
+}
+
+func (r *Registry) canonicalType(name string) string {
+	if operatorType, ok := r.aliases[name]; ok {
+		return operatorType
+	}
+	return name
+}
+
+func (r *Registry) isAllowed(operatorType string) bool {
+	if r.denied[operatorType] {
+		return false
+	}
+	return r.allowed == nil || r.allowed[operatorType]
 }
 
 // Register will register an operator in the default registry
@@ -50,6 +202,11 @@ func Register(operatorType string, newBuilder func() Builder) {
 	DefaultRegistry.Register(operatorType, newBuilder)
 }
 
+// RegisterAlias will register an alternative name for an operator type in the default registry
+func RegisterAlias(alias, operatorType string) {
+	DefaultRegistry.RegisterAlias(alias, operatorType)
+}
+
 // Lookup looks up a given operator type.Its second return value will
 // be false if no builder is registered for that type.
 func Lookup(configType string) (func() Builder, bool) {
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func newTestRegistry() *Registry {
+	registry := NewRegistry()
+	registry.Register("fake_operator", func() Builder { return &FakeBuilder{} })
+	registry.Register("other_operator", func() Builder { return &FakeBuilder{} })
+	registry.RegisterAlias("fake", "fake_operator")
+	return registry
+}
+
+func TestRegistryLookup(t *testing.T) {
+	registry := newTestRegistry()
+
+	_, ok := registry.Lookup("fake_operator")
+	require.True(t, ok)
+
+	_, ok = registry.Lookup("fake")
+	require.True(t, ok)
+
+	_, ok = registry.Lookup("missing")
+	require.False(t, ok)
+
+	require.Equal(t, []string{"fake_operator", "other_operator"}, registry.Types())
+}
+
+func TestRegistryAllowDeny(t *testing.T) {
+	t.Run("Allow", func(t *testing.T) {
+		registry := newTestRegistry()
+		registry.Allow("fake_operator")
+
+		require.True(t, registry.IsAllowed("fake_operator"))
+		require.True(t, registry.IsAllowed("fake"))
+		require.False(t, registry.IsAllowed("other_operator"))
+		require.Equal(t, []string{"fake_operator"}, registry.Types())
+
+		_, ok := registry.Lookup("other_operator")
+		require.False(t, ok)
+	})
+
+	t.Run("Deny", func(t *testing.T) {
+		registry := newTestRegistry()
+		registry.Allow("fake_operator", "other_operator")
+		registry.Deny("fake_operator")
+
+		require.False(t, registry.IsAllowed("fake_operator"))
+		require.False(t, registry.IsAllowed("fake"))
+		require.True(t, registry.IsAllowed("other_operator"))
+		require.Equal(t, []string{"other_operator"}, registry.Types())
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		registry := newTestRegistry()
+		registry.Deny("fake_operator")
+
+		_, err := registry.UnmarshalConfigs([]byte("- type: fake\n"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "operator type 'fake_operator' is not allowed")
+	})
+}
+
+func TestRegistryClone(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Deny("other_operator")
+
+	clone := registry.Clone()
+	clone.Register("new_operator", func() Builder { return &FakeBuilder{} })
+	clone.Allow("new_operator")
+
+	require.Equal(t, []string{"fake_operator"}, registry.Types())
+	require.Equal(t, []string{"new_operator"}, clone.Types())
+	require.False(t, clone.IsAllowed("other_operator"))
+
+	_, ok := clone.Lookup("fake")
+	require.False(t, ok, "alias of a type that is not allowed")
+	require.True(t, registry.IsAllowed("fake"))
+}
+
+func TestRegistryUnmarshalConfigs(t *testing.T) {
+	registry := newTestRegistry()
+
+	configs, err := registry.UnmarshalConfigs([]byte(`
+- type: fake
+  id: aliased
+- {"type": "other_operator", "array": ["a"]}
+`))
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+	require.Equal(t, &FakeBuilder{OperatorID: "aliased", OperatorType: "fake_operator"}, configs[0].Builder)
+	require.Equal(t, &FakeBuilder{OperatorType: "other_operator", Array: []string{"a"}}, configs[1].Builder)
+
+	// Types that are only registered in the default registry are not supported
+	_, err = NewRegistry().UnmarshalConfigs([]byte("- type: fake_operator\n"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported type 'fake_operator'")
+}
+
+func TestRegistryDeprecation(t *testing.T) {
+	registry := newTestRegistry()
+	registry.Deprecate("fake", "use fake_operator instead")
+
+	core, logs := observer.New(zap.WarnLevel)
+	logger := zap.New(core).Sugar()
+
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal([]byte("type: fake\n"), &registryConfig{config: &cfg, registry: registry}))
+	_, err := cfg.Build(logger)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	require.Equal(t, "Operator type is deprecated", entry.Message)
+	require.Equal(t, "fake", entry.ContextMap()["deprecated_type"])
+	require.Equal(t, "use fake_operator instead", entry.ContextMap()["message"])
+
+	// The operator type itself is not deprecated
+	require.NoError(t, yaml.Unmarshal([]byte("type: fake_operator\n"), &registryConfig{config: &cfg, registry: registry}))
+	_, err = cfg.Build(logger)
+	require.NoError(t, err)
+	require.Equal(t, 1, logs.Len())
+}
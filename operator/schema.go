@@ -0,0 +1,352 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+)
+
+// SchemaVersion is the JSON Schema draft used by generated schemas.
+const SchemaVersion = "http://json-schema.org/draft-07/schema#"
+
+// Schema is a JSON Schema describing the configuration of an operator.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Const                interface{}        `json:"const,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+// SchemaProvider is implemented by config types that define their own schema,
+// typically because they can be unmarshalled from more than one representation.
+type SchemaProvider interface {
+	JSONSchema() *Schema
+}
+
+var (
+	schemaProviderType = reflect.TypeOf((*SchemaProvider)(nil)).Elem()
+	durationType       = reflect.TypeOf(time.Duration(0))
+
+	// fieldTypes are unmarshalled from a field's string representation
+	fieldTypes = map[reflect.Type]bool{
+		reflect.TypeOf(entry.Field{}):          true,
+		reflect.TypeOf(entry.BodyField{}):      true,
+		reflect.TypeOf(entry.AttributeField{}): true,
+		reflect.TypeOf(entry.ResourceField{}):  true,
+	}
+)
+
+// ConfigSchema returns the JSON Schema of an operator type's config.
+// Its second return value will be false if the type is not registered.
+func (r *Registry) ConfigSchema(operatorType string) (*Schema, bool) {
+	newBuilder, ok := r.Lookup(operatorType)
+	if !ok {
+		return nil, false
+	}
+
+	schema := NewSchema(newBuilder())
+	schema.Title = operatorType
+	schema.Required = []string{"type"}
+	if typeSchema, ok := schema.Properties["type"]; ok {
+		typeSchema.Const = operatorType
+		typeSchema.Default = nil
+	}
+	return schema, true
+}
+
+// JSONSchema returns a JSON Schema that matches the config of any registered operator type.
+func (r *Registry) JSONSchema() *Schema {
+	schema := &Schema{
+		Schema: SchemaVersion,
+		Title:  "operator",
+		Type:   "object",
+	}
+	for _, operatorType := range r.Types() {
+		operatorSchema, _ := r.ConfigSchema(operatorType)
+		schema.OneOf = append(schema.OneOf, operatorSchema)
+	}
+	return schema
+}
+
+// ConfigSchema returns the JSON Schema of an operator type's config in the default registry.
+func ConfigSchema(operatorType string) (*Schema, bool) {
+	return DefaultRegistry.ConfigSchema(operatorType)
+}
+
+// JSONSchema returns a JSON Schema that matches the config of any operator type in the default registry.
+func JSONSchema() *Schema {
+	return DefaultRegistry.JSONSchema()
+}
+
+// NewSchema derives a JSON Schema from a config struct. Field names are taken from the
+// `yaml`, `json` or `mapstructure` tags, and defaults from the values of the config.
+// Allowed values may be declared with a tag such as `jsonschema:"enum=beginning|end"`.
+func NewSchema(config interface{}) *Schema {
+	value := reflect.ValueOf(config)
+	return schemaForValue(value.Type(), value, make(map[reflect.Type]bool))
+}
+
+func schemaForValue(t reflect.Type, value reflect.Value, visiting map[reflect.Type]bool) *Schema {
+	if t.Implements(schemaProviderType) {
+		return reflect.Zero(t).Interface().(SchemaProvider).JSONSchema()
+	}
+	if reflect.PtrTo(t).Implements(schemaProviderType) {
+		return reflect.New(t).Interface().(SchemaProvider).JSONSchema()
+	}
+
+	if fieldTypes[t] {
+		return &Schema{Type: "string"}
+	}
+	if t == durationType {
+		return &Schema{AnyOf: []*Schema{{Type: "string"}, {Type: "integer"}}}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaForValue(t.Elem(), elem(value), visiting)
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaForValue(t.Elem(), reflect.Value{}, visiting)}
+	case reflect.Map:
+		items := schemaForValue(t.Elem(), reflect.Value{}, visiting)
+		if isEmptySchema(items) {
+			return &Schema{Type: "object"}
+		}
+		return &Schema{Type: "object", AdditionalProperties: items}
+	case reflect.Struct:
+		if visiting[t] {
+			return &Schema{Type: "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		schema := &Schema{
+			Type:                 "object",
+			Properties:           make(map[string]*Schema),
+			AdditionalProperties: false,
+		}
+		addProperties(schema, t, value, visiting)
+		return schema
+	}
+
+	// Interfaces and other kinds may hold any value
+	return &Schema{}
+}
+
+// addProperties adds the fields of a struct to the properties of a schema
+func addProperties(schema *Schema, t reflect.Type, value reflect.Value, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		var fieldValue reflect.Value
+		if value.IsValid() {
+			fieldValue = value.Field(i)
+		}
+
+		name, inline, skip := fieldName(field)
+		if skip {
+			continue
+		}
+
+		if inline || field.Anonymous && name == "" {
+			embedded, embeddedValue := field.Type, fieldValue
+			if embedded.Kind() == reflect.Ptr {
+				embedded, embeddedValue = embedded.Elem(), elem(embeddedValue)
+			}
+			if isInlineStruct(embedded) {
+				addProperties(schema, embedded, embeddedValue, visiting)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		propertySchema := schemaForValue(field.Type, fieldValue, visiting)
+		if enum, ok := enumValues(field); ok {
+			propertySchema.Enum = enum
+		}
+		if fieldValue.IsValid() && propertySchema.Properties == nil {
+			propertySchema.Default = defaultValue(fieldValue)
+		}
+		schema.Properties[name] = propertySchema
+	}
+}
+
+// isInlineStruct returns true if the fields of a struct are unmarshalled as fields of its parent
+func isInlineStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !fieldTypes[t] &&
+		!t.Implements(schemaProviderType) && !reflect.PtrTo(t).Implements(schemaProviderType)
+}
+
+// fieldName returns the name of a field, and whether its fields are inlined into its parent
+func fieldName(field reflect.StructField) (name string, inline bool, skip bool) {
+	for _, key := range []string{"yaml", "json", "mapstructure"} {
+		tag, ok := field.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		if parts[0] == "-" {
+			return "", false, true
+		}
+		for _, option := range parts[1:] {
+			if option == "inline" || option == "squash" {
+				inline = true
+			}
+		}
+		if parts[0] != "" || inline {
+			return parts[0], inline, false
+		}
+	}
+	return "", false, false
+}
+
+// enumValues returns the allowed values declared by the jsonschema tag of a field
+func enumValues(field reflect.StructField) ([]interface{}, bool) {
+	tag, ok := field.Tag.Lookup("jsonschema")
+	if !ok || !strings.HasPrefix(tag, "enum=") {
+		return nil, false
+	}
+
+	values := strings.Split(strings.TrimPrefix(tag, "enum="), "|")
+	enum := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		enum = append(enum, value)
+	}
+	return enum, true
+}
+
+// defaultValue returns the JSON representation of a config value, or nil if it is not set
+func defaultValue(value reflect.Value) interface{} {
+	if value.IsZero() {
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Map, reflect.Slice:
+		if value.Len() == 0 {
+			return nil
+		}
+	case reflect.Int64:
+		if value.Type() == durationType {
+			return value.Interface().(time.Duration).String()
+		}
+	}
+
+	bytes, err := json.Marshal(value.Interface())
+	if err != nil {
+		return nil
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return nil
+	}
+	return result
+}
+
+func elem(value reflect.Value) reflect.Value {
+	if !value.IsValid() || value.IsNil() {
+		return reflect.Value{}
+	}
+	return value.Elem()
+}
+
+func isEmptySchema(schema *Schema) bool {
+	return reflect.DeepEqual(schema, &Schema{})
+}
+
+// UnknownFields returns the dotted paths of the fields in a decoded config
+// that are not allowed by the schema, in sorted order.
+func (s *Schema) UnknownFields(value interface{}) []string {
+	unknown := s.unknownFields(value, "")
+	sort.Strings(unknown)
+	return unknown
+}
+
+func (s *Schema) unknownFields(value interface{}, path string) []string {
+	var unknown []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, fieldValue := range v {
+			unknown = append(unknown, s.unknownField(key, fieldValue, path)...)
+		}
+	case map[interface{}]interface{}:
+		for key, fieldValue := range v {
+			keyString, ok := key.(string)
+			if !ok {
+				continue
+			}
+			unknown = append(unknown, s.unknownField(keyString, fieldValue, path)...)
+		}
+	case []interface{}:
+		if s.Items == nil {
+			return nil
+		}
+		for i, item := range v {
+			unknown = append(unknown, s.Items.unknownFields(item, path+"["+strconv.Itoa(i)+"]")...)
+		}
+	}
+	return unknown
+}
+
+func (s *Schema) unknownField(name string, value interface{}, path string) []string {
+	if path != "" {
+		path += "."
+	}
+	path += name
+
+	// Only objects without additional properties have a fixed set of fields
+	if s.Properties == nil || s.AdditionalProperties != false {
+		return nil
+	}
+
+	property, ok := s.Properties[name]
+	if !ok {
+		return []string{path}
+	}
+	return property.unknownFields(value, path)
+}
@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+)
+
+type schemaTestConfig struct {
+	FakeBuilder `yaml:",inline"`
+	Inline      schemaTestInline `mapstructure:",squash" yaml:",inline"`
+	Mode        string           `json:"mode"     yaml:"mode" jsonschema:"enum=fast|slow"`
+	Field       entry.Field      `json:"field"    yaml:"field"`
+	Timeout     time.Duration    `json:"timeout"  yaml:"timeout"`
+	Labels      map[string]int   `json:"labels"   yaml:"labels"`
+	Nested      *schemaTestNode  `json:"nested"   yaml:"nested"`
+	Ignored     string           `json:"-"        yaml:"-"`
+	unexported  string
+}
+
+type schemaTestInline struct {
+	Size int `mapstructure:"size"`
+}
+
+type schemaTestNode struct {
+	Child *schemaTestNode `json:"child" yaml:"child"`
+}
+
+func newSchemaTestConfig() *schemaTestConfig {
+	cfg := &schemaTestConfig{
+		Mode:    "fast",
+		Timeout: 5 * time.Second,
+		Field:   entry.NewBodyField(),
+	}
+	cfg.Inline.Size = 10
+	cfg.unexported = "unexported"
+	return cfg
+}
+
+func TestNewSchema(t *testing.T) {
+	schema := NewSchema(newSchemaTestConfig())
+	require.Equal(t, "object", schema.Type)
+	require.Equal(t, false, schema.AdditionalProperties)
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	require.ElementsMatch(t, []string{"id", "type", "array", "size", "mode", "field", "timeout", "labels", "nested"}, names)
+
+	require.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, schema.Properties["array"])
+	require.Equal(t, &Schema{Type: "integer", Default: float64(10)}, schema.Properties["size"])
+	require.Equal(t, &Schema{Type: "string", Enum: []interface{}{"fast", "slow"}, Default: "fast"}, schema.Properties["mode"])
+	require.Equal(t, &Schema{Type: "string", Default: "body"}, schema.Properties["field"])
+	require.Equal(t, "5s", schema.Properties["timeout"].Default)
+	require.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Type: "integer"}}, schema.Properties["labels"])
+
+	// Recursive types are not expanded more than once
+	nested := schema.Properties["nested"]
+	require.Equal(t, "object", nested.Type)
+	require.Equal(t, &Schema{Type: "object"}, nested.Properties["child"])
+}
+
+func TestRegistrySchema(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("b_operator", func() Builder { return &FakeBuilder{} })
+	registry.Register("a_operator", func() Builder { return newSchemaTestConfig() })
+
+	_, ok := registry.ConfigSchema("missing")
+	require.False(t, ok)
+
+	schema, ok := registry.ConfigSchema("a_operator")
+	require.True(t, ok)
+	require.Equal(t, "a_operator", schema.Title)
+	require.Equal(t, []string{"type"}, schema.Required)
+	require.Equal(t, "a_operator", schema.Properties["type"].Const)
+
+	full := registry.JSONSchema()
+	require.Equal(t, SchemaVersion, full.Schema)
+	require.Len(t, full.OneOf, 2)
+	require.Equal(t, "a_operator", full.OneOf[0].Title)
+	require.Equal(t, "b_operator", full.OneOf[1].Title)
+
+	_, err := json.Marshal(full)
+	require.NoError(t, err)
+}
+
+func TestSchemaUnknownFields(t *testing.T) {
+	schema := NewSchema(newSchemaTestConfig())
+
+	raw := map[string]interface{}{
+		"id":      "op",
+		"size":    10,
+		"labels":  map[string]interface{}{"any": 1},
+		"unknown": true,
+		"nested": map[interface{}]interface{}{
+			"child": map[string]interface{}{"child": "x", "other": 1},
+			"extra": 1,
+		},
+	}
+	// Fields of recursive types are not checked past the first level
+	require.Equal(t, []string{"nested.extra", "unknown"}, schema.UnknownFields(raw))
+}
@@ -0,0 +1,299 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+)
+
+// stateMagic marks state that is wrapped in a versioned envelope. It begins with a zero
+// byte, which can not begin the state of operators that predate envelopes.
+const stateMagic = "\x00otel-state"
+
+// State is the persisted value of a key, along with the type of the operator
+// that persisted it and the version of its encoding. State that was persisted
+// without an envelope has an empty type and version 0.
+type State struct {
+	Type    string
+	Version int
+	Data    []byte
+}
+
+// EncodeState wraps the data of an operator's state in a versioned envelope
+func EncodeState(state State) []byte {
+	var buf bytes.Buffer
+	var varint [binary.MaxVarintLen64]byte
+
+	buf.WriteString(stateMagic)
+	buf.Write(varint[:binary.PutUvarint(varint[:], uint64(state.Version))])
+	buf.Write(varint[:binary.PutUvarint(varint[:], uint64(len(state.Type)))])
+	buf.WriteString(state.Type)
+	buf.Write(state.Data)
+	return buf.Bytes()
+}
+
+// DecodeState unwraps the envelope of an operator's state
+func DecodeState(raw []byte) (State, error) {
+	if !bytes.HasPrefix(raw, []byte(stateMagic)) {
+		return State{Data: raw}, nil
+	}
+
+	rest := raw[len(stateMagic):]
+	version, n := binary.Uvarint(rest)
+	if n <= 0 {
+		return State{}, errors.NewError("persisted state has an invalid version", "reset the state of the operator")
+	}
+	rest = rest[n:]
+
+	typeLength, n := binary.Uvarint(rest)
+	if n <= 0 || typeLength > uint64(len(rest)-n) {
+		return State{}, errors.NewError("persisted state has an invalid type", "reset the state of the operator")
+	}
+	rest = rest[n:]
+
+	return State{
+		Type:    string(rest[:typeLength]),
+		Version: int(version),
+		Data:    rest[typeLength:],
+	}, nil
+}
+
+// StateMigration converts the state of an operator from one version to the next
+type StateMigration func(data []byte) ([]byte, error)
+
+// StateMigrations holds the migrations of the persisted state of operator types
+type StateMigrations struct {
+	mux        sync.RWMutex
+	migrations map[string]map[int]StateMigration
+}
+
+// NewStateMigrations creates a registry of state migrations
+func NewStateMigrations() *StateMigrations {
+	return &StateMigrations{
+		migrations: make(map[string]map[int]StateMigration),
+	}
+}
+
+// DefaultStateMigrations is the registry of state migrations used by pipelines
+var DefaultStateMigrations = NewStateMigrations()
+
+// RegisterStateMigration will register a migration of an operator type's state in the default registry
+func RegisterStateMigration(operatorType string, from int, migrate StateMigration) {
+	DefaultStateMigrations.Register(operatorType, from, migrate)
+}
+
+// Register will register a migration of an operator type's state from a version to the next.
+// The state of an operator type starts at version 0, and each migration increases its version.
+func (m *StateMigrations) Register(operatorType string, from int, migrate StateMigration) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if _, ok := m.migrations[operatorType]; !ok {
+		m.migrations[operatorType] = make(map[int]StateMigration)
+	}
+	m.migrations[operatorType][from] = migrate
+}
+
+// Version returns the current version of an operator type's state, which
+// is the number of migrations registered from version 0.
+func (m *StateMigrations) Version(operatorType string) int {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	version := 0
+	for m.migrations[operatorType][version] != nil {
+		version++
+	}
+	return version
+}
+
+// Migrate converts the state of an operator type to its current version
+func (m *StateMigrations) Migrate(operatorType string, state State) ([]byte, error) {
+	current := m.Version(operatorType)
+	if state.Version > current {
+		return nil, errors.NewError(
+			"persisted state was written by a newer version",
+			"upgrade the collector, or reset the state of the operator",
+			"operator_type", operatorType,
+			"version", strconv.Itoa(state.Version),
+			"supported_version", strconv.Itoa(current),
+		)
+	}
+
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	data := state.Data
+	for version := state.Version; version < current; version++ {
+		migrated, err := m.migrations[operatorType][version](data)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("migrate %s state from version %d", operatorType, version))
+		}
+		data = migrated
+	}
+	return data, nil
+}
+
+// NewVersionedPersister wraps the state set by an operator in a versioned envelope, and
+// migrates the state it gets to the current version of the operator type's state. State
+// written by a different operator type is logged and read as if it was never set.
+func NewVersionedPersister(operatorType string, p Persister, migrations *StateMigrations, logger *zap.SugaredLogger) Persister {
+	return &versionedPersister{
+		Persister:    p,
+		operatorType: operatorType,
+		migrations:   migrations,
+		logger:       logger,
+	}
+}
+
+type versionedPersister struct {
+	Persister
+	operatorType string
+	migrations   *StateMigrations
+	logger       *zap.SugaredLogger
+}
+
+func (p *versionedPersister) Get(ctx context.Context, key string) ([]byte, error) {
+	raw, err := p.Persister.Get(ctx, key)
+	if err != nil || raw == nil {
+		return raw, err
+	}
+
+	state, err := DecodeState(raw)
+	if err != nil {
+		return nil, errors.WithDetails(err, "key", key)
+	}
+	if state.Type != "" && state.Type != p.operatorType {
+		// The id of the operator was reused by an operator of another type, so its state does not apply
+		p.logger.Warnw("Ignoring state persisted by a different operator type",
+			"key", key,
+			"operator_type", p.operatorType,
+			"state_type", state.Type,
+		)
+		return nil, nil
+	}
+	return p.migrations.Migrate(p.operatorType, state)
+}
+
+func (p *versionedPersister) Set(ctx context.Context, key string, value []byte) error {
+	return p.Persister.Set(ctx, key, EncodeState(State{
+		Type:    p.operatorType,
+		Version: p.migrations.Version(p.operatorType),
+		Data:    value,
+	}))
+}
+
+// KeyLister is implemented by persisters that can list the keys they store
+type KeyLister interface {
+	// Keys returns the keys that begin with a prefix, in sorted order
+	Keys(ctx context.Context, prefix string) ([]string, error)
+}
+
+// StateRecord is the state of a key in a scope
+type StateRecord struct {
+	Key string
+	State
+}
+
+// InspectState returns the state persisted in the scope of an operator, such as by
+// NewScopedPersister. The persister must implement KeyLister.
+func InspectState(ctx context.Context, p Persister, scope string) ([]StateRecord, error) {
+	keys, err := scopeKeys(ctx, p, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]StateRecord, 0, len(keys))
+	for _, key := range keys {
+		raw, err := p.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		state, err := DecodeState(raw)
+		if err != nil {
+			return nil, errors.WithDetails(err, "key", key)
+		}
+		records = append(records, StateRecord{
+			Key:   strings.TrimPrefix(key, ScopedKey(scope, "")),
+			State: state,
+		})
+	}
+	return records, nil
+}
+
+// ResetState deletes the state persisted in the scope of an operator, and returns the
+// number of keys that were deleted. The persister must implement KeyLister.
+func ResetState(ctx context.Context, p Persister, scope string) (int, error) {
+	keys, err := scopeKeys(ctx, p, scope)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, key := range keys {
+		if err := p.Delete(ctx, key); err != nil {
+			return i, err
+		}
+	}
+	return len(keys), nil
+}
+
+// StateScopes returns the scopes that hold persisted state, in sorted order.
+// The persister must implement KeyLister.
+func StateScopes(ctx context.Context, p Persister) ([]string, error) {
+	keys, err := scopeKeys(ctx, p, "")
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make([]string, 0)
+	for _, key := range keys {
+		scope, _, ok := SplitScopedKey(key)
+		if !ok {
+			continue
+		}
+		if len(scopes) == 0 || scopes[len(scopes)-1] != scope {
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes, nil
+}
+
+func scopeKeys(ctx context.Context, p Persister, scope string) ([]string, error) {
+	lister, ok := p.(KeyLister)
+	if !ok {
+		return nil, errors.NewError(
+			"persister can not list its keys",
+			"use a persister that implements operator.KeyLister",
+		)
+	}
+
+	prefix := ""
+	if scope != "" {
+		prefix = ScopedKey(scope, "")
+	}
+	return lister.Keys(ctx, prefix)
+}
@@ -0,0 +1,233 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// memoryPersister is a persister that can list its keys
+type memoryPersister map[string][]byte
+
+func (p memoryPersister) Get(_ context.Context, key string) ([]byte, error) {
+	return p[key], nil
+}
+
+func (p memoryPersister) Set(_ context.Context, key string, value []byte) error {
+	p[key] = value
+	return nil
+}
+
+func (p memoryPersister) Delete(_ context.Context, key string) error {
+	delete(p, key)
+	return nil
+}
+
+func (p memoryPersister) Keys(_ context.Context, prefix string) ([]string, error) {
+	keys := make([]string, 0, len(p))
+	for key := range p {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func TestStateEncoding(t *testing.T) {
+	cases := []struct {
+		name  string
+		state State
+	}{
+		{"Empty", State{}},
+		{"Data", State{Type: "file_input", Version: 3, Data: []byte(`{"Offset":10}`)}},
+		{"LargeVersion", State{Type: "t", Version: 1 << 20, Data: []byte{0, 1, 2}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			decoded, err := DecodeState(EncodeState(tc.state))
+			require.NoError(t, err)
+			require.Equal(t, tc.state.Type, decoded.Type)
+			require.Equal(t, tc.state.Version, decoded.Version)
+			require.Equal(t, string(tc.state.Data), string(decoded.Data))
+		})
+	}
+}
+
+func TestDecodeUnversionedState(t *testing.T) {
+	state, err := DecodeState([]byte(`{"Offset":10}`))
+	require.NoError(t, err)
+	require.Equal(t, State{Data: []byte(`{"Offset":10}`)}, state)
+}
+
+func TestDecodeInvalidState(t *testing.T) {
+	_, err := DecodeState([]byte(stateMagic + "\x01\x05ab"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid type")
+}
+
+func TestStateMigrations(t *testing.T) {
+	migrations := NewStateMigrations()
+	require.Equal(t, 0, migrations.Version("test"))
+
+	migrations.Register("test", 0, func(data []byte) ([]byte, error) {
+		return append(data, '1'), nil
+	})
+	migrations.Register("test", 1, func(data []byte) ([]byte, error) {
+		return append(data, '2'), nil
+	})
+	// Migrations that do not follow from version 0 are not applied
+	migrations.Register("test", 5, func(data []byte) ([]byte, error) {
+		return nil, fmt.Errorf("unexpected migration")
+	})
+	require.Equal(t, 2, migrations.Version("test"))
+	require.Equal(t, 0, migrations.Version("other"))
+
+	data, err := migrations.Migrate("test", State{Data: []byte("v")})
+	require.NoError(t, err)
+	require.Equal(t, "v12", string(data))
+
+	data, err = migrations.Migrate("test", State{Version: 1, Data: []byte("v")})
+	require.NoError(t, err)
+	require.Equal(t, "v2", string(data))
+
+	_, err = migrations.Migrate("test", State{Version: 3, Data: []byte("v")})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "written by a newer version")
+}
+
+func TestStateMigrationError(t *testing.T) {
+	migrations := NewStateMigrations()
+	migrations.Register("test", 0, func([]byte) ([]byte, error) {
+		return nil, fmt.Errorf("bad state")
+	})
+
+	_, err := migrations.Migrate("test", State{Data: []byte("v")})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "bad state")
+}
+
+func TestVersionedPersister(t *testing.T) {
+	ctx := context.Background()
+	migrations := NewStateMigrations()
+	migrations.Register("test", 0, func(data []byte) ([]byte, error) {
+		return append([]byte("migrated "), data...), nil
+	})
+
+	store := memoryPersister{}
+	core, logs := observer.New(zapcore.WarnLevel)
+	p := NewVersionedPersister("test", NewScopedPersister("op", store), migrations, zap.New(core).Sugar())
+
+	value, err := p.Get(ctx, "missing")
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	require.NoError(t, p.Set(ctx, "key", []byte("value")))
+	state, err := DecodeState(store["op.key"])
+	require.NoError(t, err)
+	require.Equal(t, State{Type: "test", Version: 1, Data: []byte("value")}, state)
+
+	value, err = p.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, "value", string(value))
+
+	// State persisted before envelopes is migrated from version 0
+	store["op.legacy"] = []byte("value")
+	value, err = p.Get(ctx, "legacy")
+	require.NoError(t, err)
+	require.Equal(t, "migrated value", string(value))
+
+	store["op.other"] = EncodeState(State{Type: "other", Data: []byte("value")})
+	value, err = p.Get(ctx, "other")
+	require.NoError(t, err)
+	require.Nil(t, value)
+	require.Equal(t, 1, logs.FilterMessage("Ignoring state persisted by a different operator type").Len())
+
+	require.NoError(t, p.Delete(ctx, "key"))
+	require.NotContains(t, store, "op.key")
+}
+
+func TestInspectAndResetState(t *testing.T) {
+	ctx := context.Background()
+	store := memoryPersister{
+		"first.a":   EncodeState(State{Type: "test", Version: 1, Data: []byte("1")}),
+		"first.b":   []byte("2"),
+		"second.a":  EncodeState(State{Type: "test", Data: []byte("3")}),
+		"firstly.a": []byte("4"),
+		"unscoped":  []byte("5"),
+	}
+
+	scopes, err := StateScopes(ctx, store)
+	require.NoError(t, err)
+	require.Equal(t, []string{"first", "firstly", "second"}, scopes)
+
+	records, err := InspectState(ctx, store, "first")
+	require.NoError(t, err)
+	require.Equal(t, []StateRecord{
+		{Key: "a", State: State{Type: "test", Version: 1, Data: []byte("1")}},
+		{Key: "b", State: State{Data: []byte("2")}},
+	}, records)
+
+	deleted, err := ResetState(ctx, store, "first")
+	require.NoError(t, err)
+	require.Equal(t, 2, deleted)
+	require.NotContains(t, store, "first.a")
+	require.NotContains(t, store, "first.b")
+	require.Contains(t, store, "firstly.a")
+	require.Contains(t, store, "second.a")
+}
+
+func TestStateDottedScopes(t *testing.T) {
+	ctx := context.Background()
+	store := memoryPersister{}
+	for _, scope := range []string{"a", "a.b", "tpl.regex_parser"} {
+		p := NewScopedPersister(scope, store)
+		require.NoError(t, p.Set(ctx, "key.with.dots", []byte(scope)))
+	}
+
+	scopes, err := StateScopes(ctx, store)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "a.b", "tpl.regex_parser"}, scopes)
+
+	records, err := InspectState(ctx, store, "tpl.regex_parser")
+	require.NoError(t, err)
+	require.Equal(t, []StateRecord{{Key: "key.with.dots", State: State{Data: []byte("tpl.regex_parser")}}}, records)
+
+	// Resetting a scope does not reset the scopes whose ID begins with it
+	deleted, err := ResetState(ctx, store, "a")
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+
+	scopes, err = StateScopes(ctx, store)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a.b", "tpl.regex_parser"}, scopes)
+}
+
+func TestInspectStateUnsupported(t *testing.T) {
+	p := NewScopedPersister("scope", memoryPersister{})
+	_, err := InspectState(context.Background(), p, "scope")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can not list its keys")
+}
@@ -0,0 +1,241 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+)
+
+const filePrefix = "file:"
+
+// verbatimFields hold expressions, in which `${` has its own meaning,
+// so their values are never substituted
+var verbatimFields = map[string]bool{
+	"expr":           true,
+	"if":             true,
+	"is_first_entry": true,
+	"is_last_entry":  true,
+}
+
+// substitute replaces references to environment variables and files in the string values of
+// a decoded config. `${NAME}` is replaced by the value of an environment variable, `${NAME:-default}`
+// by its value or a default if it is not set or empty, and `${file:/path}` by the contents of a file.
+// `$${` is replaced by a literal `${`. When a value consists of a single reference, and the schema
+// describes the value as a number or boolean, the substituted value is converted to that type.
+// Fields that hold expressions, and values of the form `EXPR(...)`, are not substituted. The second return value reports whether any value was changed.
+func substitute(value interface{}, schema *Schema, path string) (interface{}, bool, error) {
+	switch v := value.(type) {
+	case string:
+		return substituteString(v, schema, path)
+	case map[string]interface{}:
+		changed := false
+		for key, item := range v {
+			if schema.isVerbatim(key) {
+				continue
+			}
+			substituted, itemChanged, err := substitute(item, schema.property(key), joinPath(path, key))
+			if err != nil {
+				return nil, false, err
+			}
+			v[key] = substituted
+			changed = changed || itemChanged
+		}
+		return v, changed, nil
+	case map[interface{}]interface{}:
+		changed := false
+		for key, item := range v {
+			if schema.isVerbatim(fmt.Sprint(key)) {
+				continue
+			}
+			substituted, itemChanged, err := substitute(item, schema.property(fmt.Sprint(key)), joinPath(path, fmt.Sprint(key)))
+			if err != nil {
+				return nil, false, err
+			}
+			v[key] = substituted
+			changed = changed || itemChanged
+		}
+		return v, changed, nil
+	case []interface{}:
+		var items *Schema
+		if schema != nil {
+			items = schema.Items
+		}
+		changed := false
+		for i, item := range v {
+			substituted, itemChanged, err := substitute(item, items, path+"["+strconv.Itoa(i)+"]")
+			if err != nil {
+				return nil, false, err
+			}
+			v[i] = substituted
+			changed = changed || itemChanged
+		}
+		return v, changed, nil
+	default:
+		return value, false, nil
+	}
+}
+
+func substituteString(value string, schema *Schema, path string) (interface{}, bool, error) {
+	if !strings.Contains(value, "${") || strings.HasPrefix(value, "EXPR(") {
+		return value, false, nil
+	}
+
+	var result strings.Builder
+	for rest := value; rest != ""; {
+		start := strings.Index(rest, "${")
+		if start == -1 {
+			result.WriteString(rest)
+			break
+		}
+
+		// $${ escapes a reference
+		if start > 0 && rest[start-1] == '$' {
+			result.WriteString(rest[:start-1])
+			result.WriteString("${")
+			rest = rest[start+2:]
+			continue
+		}
+
+		end := strings.Index(rest[start:], "}")
+		if end == -1 {
+			return nil, false, errors.NewError(
+				"config value contains an unterminated reference",
+				"ensure that each `${` is closed by a `}`, or escape it as `$${`",
+				"field", path,
+			)
+		}
+
+		replacement, err := resolveReference(rest[start+2:start+end], path)
+		if err != nil {
+			return nil, false, err
+		}
+		result.WriteString(rest[:start])
+		result.WriteString(replacement)
+		rest = rest[start+end+1:]
+	}
+
+	// Only a value that consists of a single reference is converted
+	substituted := result.String()
+	if strings.HasPrefix(value, "${") && strings.Index(value, "}") == len(value)-1 {
+		return convertScalar(substituted, schema), true, nil
+	}
+	return substituted, true, nil
+}
+
+// resolveReference returns the value of a reference, without its delimiters
+func resolveReference(reference, path string) (string, error) {
+	if strings.HasPrefix(reference, filePrefix) {
+		filePath := strings.TrimPrefix(reference, filePrefix)
+		contents, err := ioutil.ReadFile(filePath) // #nosec - file references are specified by the config author
+		if err != nil {
+			return "", errors.NewError(
+				fmt.Sprintf("failed to read referenced file: %s", err),
+				"ensure that the referenced file exists and is readable",
+				"field", path,
+				"file", filePath,
+			)
+		}
+		return strings.TrimRight(string(contents), "\r\n"), nil
+	}
+
+	name, defaultValue, hasDefault := reference, "", false
+	if i := strings.Index(reference, ":-"); i != -1 {
+		name, defaultValue, hasDefault = reference[:i], reference[i+2:], true
+	}
+	if name == "" {
+		return "", errors.NewError(
+			"config value contains a reference without a name",
+			"ensure that references have the form `${NAME}`, `${NAME:-default}` or `${file:/path}`",
+			"field", path,
+		)
+	}
+
+	value, ok := os.LookupEnv(name)
+	if hasDefault && value == "" {
+		return defaultValue, nil
+	}
+	if !ok {
+		return "", errors.NewError(
+			fmt.Sprintf("environment variable '%s' is not set", name),
+			fmt.Sprintf("set the environment variable, or provide a default with `${%s:-default}`", name),
+			"field", path,
+			"variable", name,
+		)
+	}
+	return value, nil
+}
+
+// convertScalar converts a substituted value to the type described by its schema
+func convertScalar(value string, schema *Schema) interface{} {
+	if schema == nil {
+		return value
+	}
+
+	switch schema.Type {
+	case "integer":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+// property returns the schema of a property of an object, or nil if it is not known
+func (s *Schema) property(name string) *Schema {
+	if s == nil {
+		return nil
+	}
+	if property, ok := s.Properties[name]; ok {
+		return property
+	}
+	if additional, ok := s.AdditionalProperties.(*Schema); ok {
+		return additional
+	}
+	return nil
+}
+
+// isVerbatim returns true if a property of an object is one of the verbatimFields. The
+// keys of maps that accept arbitrary properties, such as labels, are substituted.
+func (s *Schema) isVerbatim(name string) bool {
+	if !verbatimFields[name] {
+		return false
+	}
+	if s == nil {
+		return true
+	}
+	_, ok := s.Properties[name]
+	return ok
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestSubstituteString(t *testing.T) {
+	t.Setenv("SUBSTITUTE_HOST", "localhost")
+	t.Setenv("SUBSTITUTE_PORT", "8080")
+	t.Setenv("SUBSTITUTE_EMPTY", "")
+
+	secret := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, ioutil.WriteFile(secret, []byte("s3cr3t\n"), 0600))
+
+	cases := []struct {
+		name     string
+		value    string
+		schema   *Schema
+		expected interface{}
+	}{
+		{"NoReference", "plain", nil, "plain"},
+		{"Variable", "${SUBSTITUTE_HOST}", nil, "localhost"},
+		{"Embedded", "${SUBSTITUTE_HOST}:${SUBSTITUTE_PORT}", nil, "localhost:8080"},
+		{"Default", "${SUBSTITUTE_MISSING:-fallback}", nil, "fallback"},
+		{"DefaultEmpty", "${SUBSTITUTE_EMPTY:-fallback}", nil, "fallback"},
+		{"DefaultUnused", "${SUBSTITUTE_HOST:-fallback}", nil, "localhost"},
+		{"Empty", "${SUBSTITUTE_EMPTY}", nil, ""},
+		{"File", "${file:" + secret + "}", nil, "s3cr3t"},
+		{"Escaped", "$${SUBSTITUTE_HOST}", nil, "${SUBSTITUTE_HOST}"},
+		{"Integer", "${SUBSTITUTE_PORT}", &Schema{Type: "integer"}, int64(8080)},
+		{"EmbeddedInteger", "${SUBSTITUTE_PORT}0", &Schema{Type: "integer"}, "80800"},
+		{"String", "${SUBSTITUTE_PORT}", &Schema{Type: "string"}, "8080"},
+		{"Boolean", "${SUBSTITUTE_BOOL:-true}", &Schema{Type: "boolean"}, true},
+		{"InvalidNumber", "${SUBSTITUTE_HOST}", &Schema{Type: "number"}, "localhost"},
+		{"Expression", `EXPR("${SUBSTITUTE_HOST}")`, nil, `EXPR("${SUBSTITUTE_HOST}")`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			substituted, _, err := substituteString(tc.value, tc.schema, "field")
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, substituted)
+		})
+	}
+}
+
+func TestSubstituteStringErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"Missing", "${SUBSTITUTE_MISSING}", "environment variable 'SUBSTITUTE_MISSING' is not set"},
+		{"Unterminated", "${SUBSTITUTE_MISSING", "unterminated reference"},
+		{"NoName", "${:-default}", "reference without a name"},
+		{"MissingFile", "${file:/nonexistent/secret}", "failed to read referenced file"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := substituteString(tc.value, nil, "field")
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expected)
+			require.Contains(t, err.Error(), `"field":"field"`)
+		})
+	}
+}
+
+type substituteBuilder struct {
+	FakeBuilder `yaml:",inline"`
+	Port        int               `json:"port"    yaml:"port"`
+	Enabled     bool              `json:"enabled" yaml:"enabled"`
+	Labels      map[string]string `json:"labels"  yaml:"labels"`
+	Regex       string            `json:"regex"   yaml:"regex"`
+	IfExpr      string            `json:"if"      yaml:"if"`
+	Value       string            `json:"value"   yaml:"value"`
+}
+
+func TestUnmarshalSubstitution(t *testing.T) {
+	t.Cleanup(func() {
+		DefaultRegistry = NewRegistry()
+	})
+	Register("substitute_operator", func() Builder { return &substituteBuilder{} })
+
+	t.Setenv("SUBSTITUTE_PORT", "8080")
+	t.Setenv("SUBSTITUTE_ENV", "prod")
+	expected := &substituteBuilder{
+		FakeBuilder: FakeBuilder{OperatorType: "substitute_operator", Array: []string{"prod"}},
+		Port:        8080,
+		Enabled:     true,
+		Labels:      map[string]string{"env": "prod-1"},
+		Regex:       `^prod-(?P<port>\d+)${end}$`,
+		IfExpr:      `body.env == "${SUBSTITUTE_ENV}"`,
+		Value:       `EXPR(attributes["${SUBSTITUTE_ENV}"])`,
+	}
+
+	t.Run("YAML", func(t *testing.T) {
+		raw := `
+type: substitute_operator
+port: ${SUBSTITUTE_PORT}
+enabled: ${SUBSTITUTE_ENABLED:-true}
+array: ['${SUBSTITUTE_ENV}']
+labels:
+  env: ${SUBSTITUTE_ENV}-1
+regex: '^${SUBSTITUTE_ENV}-(?P<port>\d+)$${end}$'
+if: 'body.env == "${SUBSTITUTE_ENV}"'
+value: 'EXPR(attributes["${SUBSTITUTE_ENV}"])'
+`
+		var cfg Config
+		require.NoError(t, yaml.Unmarshal([]byte(raw), &cfg))
+		require.Equal(t, expected, cfg.Builder)
+		require.Empty(t, cfg.unknownFields)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		raw := `{"type":"substitute_operator","port":"${SUBSTITUTE_PORT}","enabled":"${SUBSTITUTE_ENABLED:-true}","array":["${SUBSTITUTE_ENV}"],"labels":{"env":"${SUBSTITUTE_ENV}-1"},"regex":"^${SUBSTITUTE_ENV}-(?P<port>\\d+)$${end}$","if":"body.env == \"${SUBSTITUTE_ENV}\"","value":"EXPR(attributes[\"${SUBSTITUTE_ENV}\"])"}`
+		var cfg Config
+		require.NoError(t, json.Unmarshal([]byte(raw), &cfg))
+		require.Equal(t, expected, cfg.Builder)
+	})
+
+	t.Run("VerbatimLabel", func(t *testing.T) {
+		// Only declared expression fields are left as they are
+		raw := "type: substitute_operator\nlabels:\n  if: ${SUBSTITUTE_ENV}\n"
+		var cfg Config
+		require.NoError(t, yaml.Unmarshal([]byte(raw), &cfg))
+		require.Equal(t, map[string]string{"if": "prod"}, cfg.Builder.(*substituteBuilder).Labels)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		raw := "type: substitute_operator\nlabels:\n  env: ${SUBSTITUTE_MISSING}\n"
+		var cfg Config
+		err := yaml.Unmarshal([]byte(raw), &cfg)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "environment variable 'SUBSTITUTE_MISSING' is not set")
+		require.Contains(t, err.Error(), `"field":"labels.env"`)
+	})
+}
@@ -19,7 +19,6 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/antonmedv/expr"
 	"github.com/antonmedv/expr/vm"
 	"go.uber.org/zap"
 
@@ -65,7 +64,7 @@ func (c AddOperatorConfig) Build(logger *zap.SugaredLogger) (operator.Operator,
 	exprStr := strings.TrimPrefix(strVal, "EXPR(")
 	exprStr = strings.TrimSuffix(exprStr, ")")
 
-	compiled, err := expr.Compile(exprStr, expr.AllowUndefinedVariables())
+	compiled, err := helper.ExprCompile(exprStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile expression '%s': %w", c.IfExpr, err)
 	}
@@ -88,6 +87,11 @@ func (p *AddOperator) Process(ctx context.Context, entry *entry.Entry) error {
 	return p.ProcessWith(ctx, entry, p.Transform)
 }
 
+// ProcessBatch will process a batch of entries with a add transformation.
+func (p *AddOperator) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return p.ProcessBatchWith(ctx, entries, p.Transform)
+}
+
 // Transform will apply the add operations to an entry
 func (p *AddOperator) Transform(e *entry.Entry) error {
 	if p.Value != nil {
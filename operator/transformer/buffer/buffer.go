@@ -0,0 +1,241 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jpillora/backoff"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
+)
+
+const (
+	defaultMaxSize     = 1 << 30
+	defaultSegmentSize = 16 << 20
+)
+
+func init() {
+	operator.Register("buffer", func() operator.Builder { return NewBufferOperatorConfig("") })
+}
+
+// NewBufferOperatorConfig creates a new buffer operator config with default values
+func NewBufferOperatorConfig(operatorID string) *BufferOperatorConfig {
+	return &BufferOperatorConfig{
+		WriterConfig: helper.NewWriterConfig(operatorID, "buffer"),
+		MaxSize:      defaultMaxSize,
+		SegmentSize:  defaultSegmentSize,
+	}
+}
+
+// BufferOperatorConfig is the configuration of a buffer operator
+type BufferOperatorConfig struct {
+	helper.WriterConfig `mapstructure:",squash" yaml:",inline"`
+
+	Path        string          `mapstructure:"path"                   json:"path"                   yaml:"path"`
+	MaxSize     helper.ByteSize `mapstructure:"max_size,omitempty"     json:"max_size,omitempty"     yaml:"max_size,omitempty"`
+	SegmentSize helper.ByteSize `mapstructure:"segment_size,omitempty" json:"segment_size,omitempty" yaml:"segment_size,omitempty"`
+}
+
+// Build will build a buffer operator from the supplied configuration
+func (c BufferOperatorConfig) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
+	writerOperator, err := c.WriterConfig.Build(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Path == "" {
+		return nil, fmt.Errorf("missing required parameter 'path'")
+	}
+
+	if c.SegmentSize <= 0 {
+		return nil, fmt.Errorf("invalid value '%d' for parameter 'segment_size'", c.SegmentSize)
+	}
+
+	if c.MaxSize < c.SegmentSize {
+		return nil, fmt.Errorf("'max_size' must be greater than or equal to 'segment_size'")
+	}
+
+	return &BufferOperator{
+		WriterOperator: writerOperator,
+		queue:          newDiskQueue(c.Path, int64(c.MaxSize), int64(c.SegmentSize)),
+		cancel:         func() {},
+	}, nil
+}
+
+// BufferOperator is an operator that spools entries to disk and
+// replays them to its outputs from a separate goroutine.
+type BufferOperator struct {
+	helper.WriterOperator
+
+	queue  *diskQueue
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Start opens the on-disk queue and starts replaying buffered entries
+func (b *BufferOperator) Start(persister operator.Persister) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := b.queue.open(ctx, persister); err != nil {
+		cancel()
+		return err
+	}
+
+	b.cancel = cancel
+	b.wg.Add(1)
+	go b.replay(ctx)
+	return nil
+}
+
+// Stop stops replaying entries. Entries that have not been
+// replayed remain on disk and are replayed after a restart.
+func (b *BufferOperator) Stop() error {
+	b.cancel()
+	b.wg.Wait()
+	return b.queue.close()
+}
+
+// CanProcess will always return true for a buffer operator
+func (b *BufferOperator) CanProcess() bool {
+	return true
+}
+
+// Process writes an entry to the on-disk queue. The entry is acknowledged once it
+// has been synced to disk, and backpressure is applied while the queue is full.
+// Entries that can not be encoded are acknowledged and dropped.
+func (b *BufferOperator) Process(ctx context.Context, e *entry.Entry) (err error) {
+	defer func() { b.Telemetry().ReceivedUnlessRejected(ctx, err) }()
+
+	data, err := entry.Marshal(e)
+	if err != nil {
+		e.Ack()
+		e.Release()
+		return errors.Wrap(err, "encode entry")
+	}
+
+	if _, err := b.queue.push(data); err != nil {
+		return err
+	}
+
+	e.Ack()
+	e.Release()
+	return nil
+}
+
+// ProcessBatch writes a batch of entries to the on-disk queue, syncing them to
+// disk once for the whole batch. Entries are acknowledged once they are synced,
+// and entries that can not be encoded are acknowledged and dropped.
+func (b *BufferOperator) ProcessBatch(ctx context.Context, entries []*entry.Entry) (err error) {
+	defer func() { b.Telemetry().ReceivedBatchUnlessRejected(ctx, len(entries), err) }()
+
+	// indices holds the position in entries of each encoded record
+	records := make([][]byte, 0, len(entries))
+	indices := make([]int, 0, len(entries))
+
+	var errs error
+	for i, e := range entries {
+		data, err := entry.Marshal(e)
+		if err != nil {
+			e.Ack()
+			e.Release()
+			errs = multierr.Append(errs, errors.Wrap(err, "encode entry"))
+			continue
+		}
+		records = append(records, data)
+		indices = append(indices, i)
+	}
+
+	pushed, err := b.queue.push(records...)
+	for _, i := range indices[:pushed] {
+		entries[i].Ack()
+		entries[i].Release()
+	}
+	errs = multierr.Append(errs, err)
+
+	if errors.IsBackpressure(err) {
+		return errors.NewBatchError(indices[pushed], errs)
+	}
+	return errs
+}
+
+// replay reads entries from the queue and writes them to the outputs of the
+// operator, retrying entries for as long as the outputs apply backpressure.
+func (b *BufferOperator) replay(ctx context.Context) {
+	defer b.wg.Done()
+
+	retry := backoff.Backoff{
+		Min: 10 * time.Millisecond,
+		Max: 10 * time.Second,
+	}
+
+	for {
+		data, next, ok, err := b.queue.peek(ctx)
+		if err != nil {
+			b.Errorw("Failed to read buffered entries, skipping the remainder of the segment", zap.Error(err))
+			if err := b.queue.skipSegment(ctx); err != nil {
+				b.Errorw("Failed to skip segment", zap.Error(err))
+				if !b.wait(ctx, retry.Duration()) {
+					return
+				}
+			}
+			continue
+		}
+
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-b.queue.notify:
+			}
+			continue
+		}
+
+		e, err := entry.Unmarshal(data)
+		if err != nil {
+			b.Errorw("Failed to decode buffered entry", zap.Error(err))
+		} else if err := b.Write(ctx, e); errors.IsBackpressure(err) {
+			// The record is decoded again when it is retried, since
+			// downstream operators may have modified the entry
+			e.Release()
+			if !b.wait(ctx, retry.Duration()) {
+				return
+			}
+			continue
+		}
+		retry.Reset()
+
+		if err := b.queue.commit(ctx, next); err != nil {
+			b.Errorw("Failed to commit buffered entry", zap.Error(err))
+		}
+	}
+}
+
+// wait returns false if the context is done before the duration elapses
+func (b *BufferOperator) wait(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-b.Clock().After(d):
+		return true
+	}
+}
@@ -0,0 +1,415 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
+)
+
+// gatedOutput applies backpressure until it is opened
+type gatedOutput struct {
+	*testutil.FakeOutput
+	open int32
+}
+
+func (o *gatedOutput) Process(ctx context.Context, e *entry.Entry) error {
+	if atomic.LoadInt32(&o.open) == 0 {
+		return errors.NewBackpressureError("output is closed")
+	}
+	return o.FakeOutput.Process(ctx, e)
+}
+
+// flakyOutput applies backpressure to the first entry it receives
+type flakyOutput struct {
+	*testutil.FakeOutput
+	id       string
+	rejected int32
+}
+
+func (o *flakyOutput) ID() string {
+	return o.id
+}
+
+func (o *flakyOutput) Process(ctx context.Context, e *entry.Entry) error {
+	if atomic.CompareAndSwapInt32(&o.rejected, 0, 1) {
+		return errors.NewBackpressureError("output is busy")
+	}
+	return o.FakeOutput.Process(ctx, e)
+}
+
+func newTestBuffer(t *testing.T, dir string, cfgMod func(*BufferOperatorConfig), output operator.Operator) *BufferOperator {
+	cfg := NewBufferOperatorConfig("test")
+	cfg.Path = dir
+	cfg.OutputIDs = []string{output.ID()}
+	if cfgMod != nil {
+		cfgMod(cfg)
+	}
+
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.NoError(t, op.SetOutputs([]operator.Operator{output}))
+	return op.(*BufferOperator)
+}
+
+func newTestEntry(body interface{}) *entry.Entry {
+	e := entry.New()
+	e.ObservedTimestamp = time.Date(2022, time.May, 1, 12, 0, 1, 0, time.UTC)
+	e.Timestamp = time.Date(2022, time.May, 1, 12, 0, 0, 0, time.UTC)
+	e.Body = body
+	e.Attributes = map[string]interface{}{"key": "value"}
+	e.Severity = entry.Error
+	return e
+}
+
+func TestBuildInvalid(t *testing.T) {
+	cases := []struct {
+		name     string
+		modify   func(*BufferOperatorConfig)
+		expected string
+	}{
+		{
+			"MissingPath",
+			func(cfg *BufferOperatorConfig) { cfg.Path = "" },
+			"missing required parameter 'path'",
+		},
+		{
+			"ZeroSegmentSize",
+			func(cfg *BufferOperatorConfig) { cfg.SegmentSize = 0 },
+			"invalid value '0' for parameter 'segment_size'",
+		},
+		{
+			"SegmentLargerThanMax",
+			func(cfg *BufferOperatorConfig) { cfg.MaxSize = 1024 },
+			"'max_size' must be greater than or equal to 'segment_size'",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewBufferOperatorConfig("test")
+			cfg.Path = testutil.NewTempDir(t)
+			tc.modify(cfg)
+			_, err := cfg.Build(testutil.Logger(t))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestBufferReplay(t *testing.T) {
+	output := testutil.NewFakeOutput(t)
+	buffer := newTestBuffer(t, testutil.NewTempDir(t), nil, output)
+	require.NoError(t, buffer.Start(testutil.NewMockPersister("test")))
+	defer buffer.Stop()
+
+	bodies := []interface{}{
+		"a string",
+		map[string]interface{}{"message": "a map", "count": 3},
+		[]byte("some bytes"),
+	}
+	for _, body := range bodies {
+		require.NoError(t, buffer.Process(context.Background(), newTestEntry(body)))
+	}
+
+	for _, body := range bodies {
+		select {
+		case e := <-output.Received:
+			require.Equal(t, newTestEntry(body), e)
+		case <-time.After(time.Second):
+			require.FailNow(t, "Timed out waiting for buffered entry")
+		}
+	}
+}
+
+func TestBufferReplayToSeveralOutputs(t *testing.T) {
+	flaky := &flakyOutput{FakeOutput: testutil.NewFakeOutput(t), id: "flaky"}
+	steady := &flakyOutput{FakeOutput: testutil.NewFakeOutput(t), id: "steady", rejected: 1}
+
+	cfg := NewBufferOperatorConfig("test")
+	cfg.Path = testutil.NewTempDir(t)
+	cfg.OutputIDs = []string{"flaky", "steady"}
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.NoError(t, op.SetOutputs([]operator.Operator{flaky, steady}))
+
+	buffer := op.(*BufferOperator)
+	require.NoError(t, buffer.Start(testutil.NewMockPersister("test")))
+	defer buffer.Stop()
+
+	require.NoError(t, buffer.Process(context.Background(), newTestEntry("test")))
+
+	// Backpressure from one output does not replay the entry to the other
+	flaky.ExpectBody(t, "test")
+	steady.ExpectBody(t, "test")
+	steady.ExpectNoEntry(t, 100*time.Millisecond)
+	flaky.ExpectNoEntry(t, 0)
+}
+
+func TestBufferAcknowledges(t *testing.T) {
+	output := &gatedOutput{FakeOutput: testutil.NewFakeOutput(t)}
+	buffer := newTestBuffer(t, testutil.NewTempDir(t), nil, output)
+	require.NoError(t, buffer.Start(testutil.NewMockPersister("test")))
+	defer buffer.Stop()
+
+	acked := false
+	e := newTestEntry("test")
+	e.OnAck(func() { acked = true })
+	require.NoError(t, buffer.Process(context.Background(), e))
+	require.True(t, acked, "entry should be acknowledged once it is written to disk")
+}
+
+func TestBufferDropsUnencodableEntries(t *testing.T) {
+	entry.SetPoolDebug(true)
+	defer entry.SetPoolDebug(false)
+
+	output := &gatedOutput{FakeOutput: testutil.NewFakeOutput(t)}
+	buffer := newTestBuffer(t, testutil.NewTempDir(t), nil, output)
+	require.NoError(t, buffer.Start(testutil.NewMockPersister("test")))
+	defer buffer.Stop()
+
+	acked := 0
+	entries := make([]*entry.Entry, 0, 3)
+	for _, body := range []interface{}{"first", make(chan int), "last"} {
+		e := newTestEntry(body)
+		e.OnAck(func() { acked++ })
+		entries = append(entries, e)
+	}
+
+	// Entries that can not be encoded are acknowledged and released along with the buffered entries
+	err := operator.ProcessBatch(context.Background(), buffer, entries)
+	require.Error(t, err)
+	require.False(t, errors.IsBackpressure(err))
+	require.Equal(t, 3, acked)
+	for _, e := range entries {
+		require.PanicsWithValue(t, "entry was used after it was released", e.Release)
+	}
+
+	e := newTestEntry(make(chan int))
+	e.OnAck(func() { acked++ })
+	require.Error(t, buffer.Process(context.Background(), e))
+	require.Equal(t, 4, acked)
+	require.PanicsWithValue(t, "entry was used after it was released", e.Release)
+}
+
+func TestBufferFull(t *testing.T) {
+	output := &gatedOutput{FakeOutput: testutil.NewFakeOutput(t)}
+	buffer := newTestBuffer(t, testutil.NewTempDir(t), func(cfg *BufferOperatorConfig) {
+		cfg.MaxSize = 4096
+		cfg.SegmentSize = 1024
+	}, output)
+	require.NoError(t, buffer.Start(testutil.NewMockPersister("test")))
+	defer buffer.Stop()
+
+	var err error
+	for i := 0; i < 1000 && err == nil; i++ {
+		err = buffer.Process(context.Background(), newTestEntry("test"))
+	}
+	require.Error(t, err)
+	require.True(t, errors.IsBackpressure(err))
+
+	// Once the output recovers, the buffer accepts entries again
+	atomic.StoreInt32(&output.open, 1)
+	require.Eventually(t, func() bool {
+		return buffer.Process(context.Background(), newTestEntry("test")) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestBufferRestart(t *testing.T) {
+	dir := testutil.NewTempDir(t)
+	persister := testutil.NewMockPersister("test")
+
+	closed := &gatedOutput{FakeOutput: testutil.NewFakeOutput(t)}
+	buffer := newTestBuffer(t, dir, func(cfg *BufferOperatorConfig) {
+		cfg.SegmentSize = 256
+	}, closed)
+	require.NoError(t, buffer.Start(persister))
+	for i := 0; i < 10; i++ {
+		require.NoError(t, buffer.Process(context.Background(), newTestEntry(i)))
+	}
+	require.NoError(t, buffer.Stop())
+
+	output := testutil.NewFakeOutput(t)
+	restarted := newTestBuffer(t, dir, func(cfg *BufferOperatorConfig) {
+		cfg.SegmentSize = 256
+	}, output)
+	require.NoError(t, restarted.Start(persister))
+	defer restarted.Stop()
+
+	for i := 0; i < 10; i++ {
+		output.ExpectBody(t, i)
+	}
+	output.ExpectNoEntry(t, 100*time.Millisecond)
+}
+
+func TestBufferRemovesSegments(t *testing.T) {
+	dir := testutil.NewTempDir(t)
+	output := testutil.NewFakeOutput(t)
+	buffer := newTestBuffer(t, dir, func(cfg *BufferOperatorConfig) {
+		cfg.SegmentSize = 256
+	}, output)
+	require.NoError(t, buffer.Start(testutil.NewMockPersister("test")))
+	defer buffer.Stop()
+
+	for i := 0; i < 20; i++ {
+		require.NoError(t, buffer.Process(context.Background(), newTestEntry(i)))
+	}
+	for i := 0; i < 20; i++ {
+		output.ExpectBody(t, i)
+	}
+
+	require.Eventually(t, func() bool {
+		files, err := os.ReadDir(dir)
+		return err == nil && len(files) == 1
+	}, time.Second, 10*time.Millisecond, "only the segment being written should remain")
+}
+
+func TestBufferDiscardsPartialRecord(t *testing.T) {
+	dir := testutil.NewTempDir(t)
+	persister := testutil.NewMockPersister("test")
+
+	closed := &gatedOutput{FakeOutput: testutil.NewFakeOutput(t)}
+	buffer := newTestBuffer(t, dir, nil, closed)
+	require.NoError(t, buffer.Start(persister))
+	require.NoError(t, buffer.Process(context.Background(), newTestEntry("complete")))
+	require.NoError(t, buffer.Stop())
+
+	// Simulate a crash while a record was being written
+	segment, err := os.OpenFile(buffer.queue.segmentPath(0), os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = segment.Write([]byte{0, 0, 1})
+	require.NoError(t, err)
+	require.NoError(t, segment.Close())
+
+	output := testutil.NewFakeOutput(t)
+	restarted := newTestBuffer(t, dir, nil, output)
+	require.NoError(t, restarted.Start(persister))
+	defer restarted.Stop()
+
+	output.ExpectBody(t, "complete")
+	output.ExpectNoEntry(t, 100*time.Millisecond)
+}
+
+// countingPersister counts the values that are set
+type countingPersister struct {
+	operator.Persister
+	sets int32
+}
+
+func (p *countingPersister) Set(ctx context.Context, key string, value []byte) error {
+	atomic.AddInt32(&p.sets, 1)
+	return p.Persister.Set(ctx, key, value)
+}
+
+func TestBufferDoesNotPersistWrites(t *testing.T) {
+	output := &gatedOutput{FakeOutput: testutil.NewFakeOutput(t)}
+	buffer := newTestBuffer(t, testutil.NewTempDir(t), nil, output)
+	persister := &countingPersister{Persister: testutil.NewMockPersister("test")}
+	require.NoError(t, buffer.Start(persister))
+	defer buffer.Stop()
+
+	sets := atomic.LoadInt32(&persister.sets)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, buffer.Process(context.Background(), newTestEntry(i)))
+	}
+	require.Equal(t, sets, atomic.LoadInt32(&persister.sets), "the write cursor should be recovered from the segments")
+}
+
+func TestBufferProcessBatch(t *testing.T) {
+	output := &gatedOutput{FakeOutput: testutil.NewFakeOutput(t)}
+	buffer := newTestBuffer(t, testutil.NewTempDir(t), func(cfg *BufferOperatorConfig) {
+		cfg.MaxSize = 1024
+		cfg.SegmentSize = 1024
+	}, output)
+	require.NoError(t, buffer.Start(testutil.NewMockPersister("test")))
+	defer buffer.Stop()
+
+	acked := 0
+	entries := make([]*entry.Entry, 0, 20)
+	for i := 0; i < 20; i++ {
+		e := newTestEntry(i)
+		e.OnAck(func() { acked++ })
+		entries = append(entries, e)
+	}
+
+	// The entries that fit in the buffer are acknowledged, and the rest are rejected
+	err := operator.ProcessBatch(context.Background(), buffer, entries)
+	require.True(t, errors.IsBackpressure(err))
+	accepted := errors.BatchAccepted(err, len(entries))
+	require.Greater(t, accepted, 0)
+	require.Less(t, accepted, len(entries))
+	require.Equal(t, accepted, acked)
+
+	atomic.StoreInt32(&output.open, 1)
+	for i := 0; i < accepted; i++ {
+		output.ExpectBody(t, i)
+	}
+}
+
+func TestBufferNotRunning(t *testing.T) {
+	dir := testutil.NewTempDir(t)
+	buffer := newTestBuffer(t, dir, nil, testutil.NewFakeOutput(t))
+
+	err := buffer.Process(context.Background(), newTestEntry("early"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "while it was not running")
+
+	require.NoError(t, buffer.Start(testutil.NewMockPersister("test")))
+	require.NoError(t, buffer.Stop())
+
+	err = buffer.Process(context.Background(), newTestEntry("late"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "while it was not running")
+}
+
+func TestBufferDiscardsTornRecord(t *testing.T) {
+	dir := testutil.NewTempDir(t)
+	persister := testutil.NewMockPersister("test")
+
+	closed := &gatedOutput{FakeOutput: testutil.NewFakeOutput(t)}
+	buffer := newTestBuffer(t, dir, nil, closed)
+	require.NoError(t, buffer.Start(persister))
+	require.NoError(t, buffer.Process(context.Background(), newTestEntry("complete")))
+	require.NoError(t, buffer.Stop())
+
+	// Simulate a crash after the header of a record was written, but not all of its data
+	segment, err := os.OpenFile(buffer.queue.segmentPath(0), os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = segment.Write([]byte{0, 0, 0, 4, 1, 2, 3, 4, 'a'})
+	require.NoError(t, err)
+	require.NoError(t, segment.Close())
+
+	output := testutil.NewFakeOutput(t)
+	restarted := newTestBuffer(t, dir, nil, output)
+	require.NoError(t, restarted.Start(persister))
+	defer restarted.Stop()
+
+	output.ExpectBody(t, "complete")
+	output.ExpectNoEntry(t, 100*time.Millisecond)
+
+	// Records written after the restart follow the last complete record
+	require.NoError(t, restarted.Process(context.Background(), newTestEntry("next")))
+	output.ExpectBody(t, "next")
+}
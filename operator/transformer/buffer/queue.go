@@ -0,0 +1,425 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buffer
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+)
+
+const (
+	// Each record is prefixed with the length and checksum of its data
+	recordHeaderSize = 8
+
+	segmentSuffix = ".segment"
+	cursorsKey    = "cursors"
+)
+
+var (
+	errCorruptRecord = fmt.Errorf("buffered record is corrupt")
+	errClosed        = errors.NewError(
+		"buffer received an entry while it was not running",
+		"ensure that the buffer is started before entries are sent to it",
+	)
+)
+
+// cursor is a position in the segments of a disk queue
+type cursor struct {
+	Segment int   `json:"segment"`
+	Offset  int64 `json:"offset"`
+}
+
+// diskQueue is a queue of records stored in segment files within a directory.
+// Records are appended to the newest segment, and segments are removed once
+// all of their records have been read and committed. The read cursor is saved
+// with a persister, and the write cursor is recovered from the segments on
+// disk, so that the queue survives restarts.
+type diskQueue struct {
+	dir         string
+	maxSize     int64
+	segmentSize int64
+
+	mux       sync.Mutex
+	persister operator.Persister
+	read      cursor
+	write     cursor
+	size      int64
+	writer    *os.File
+	reader    *os.File
+	notify    chan struct{}
+}
+
+func newDiskQueue(dir string, maxSize, segmentSize int64) *diskQueue {
+	return &diskQueue{
+		dir:         dir,
+		maxSize:     maxSize,
+		segmentSize: segmentSize,
+		notify:      make(chan struct{}, 1),
+	}
+}
+
+// open loads the saved read cursor, recovers the write cursor from the
+// newest segment, and prepares the segments for reading and writing
+func (q *diskQueue) open(ctx context.Context, persister operator.Persister) error {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	q.persister = persister
+	q.read = cursor{}
+
+	if err := os.MkdirAll(q.dir, 0750); err != nil {
+		return errors.Wrap(err, "create buffer directory")
+	}
+
+	saved, err := persister.Get(ctx, cursorsKey)
+	if err != nil {
+		return errors.Wrap(err, "load buffer cursors")
+	}
+	if saved != nil {
+		var cursors struct {
+			Read cursor `json:"read"`
+		}
+		if err := json.Unmarshal(saved, &cursors); err != nil {
+			return errors.Wrap(err, "decode buffer cursors")
+		}
+		q.read = cursors.Read
+	}
+
+	segments, err := q.segments()
+	if err != nil {
+		return err
+	}
+
+	// Remove segments that were fully read. Without a saved cursor, all
+	// segments are removed because their records can not be located.
+	q.size = 0
+	q.write = cursor{Segment: q.read.Segment}
+	for _, segment := range segments {
+		if saved == nil || segment < q.read.Segment {
+			if err := os.Remove(q.segmentPath(segment)); err != nil {
+				return errors.Wrap(err, "remove stale segment")
+			}
+			continue
+		}
+
+		info, err := os.Stat(q.segmentPath(segment))
+		if err != nil {
+			return errors.Wrap(err, "stat segment")
+		}
+		q.size += info.Size()
+		q.write.Segment = segment
+	}
+
+	q.writer, err = os.OpenFile(q.segmentPath(q.write.Segment), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Wrap(err, "open segment")
+	}
+	if err := q.recoverWriteOffset(); err != nil {
+		return err
+	}
+	if q.read.Segment == q.write.Segment && q.read.Offset > q.write.Offset {
+		q.read = q.write
+	}
+
+	return q.save(ctx)
+}
+
+// recoverWriteOffset sets the write cursor to the end of the last complete record
+// in the newest segment. Any record that was partially written is discarded.
+func (q *diskQueue) recoverWriteOffset() error {
+	info, err := q.writer.Stat()
+	if err != nil {
+		return errors.Wrap(err, "stat segment")
+	}
+
+	var offset int64
+	header := make([]byte, recordHeaderSize)
+	for {
+		if _, err := q.writer.ReadAt(header, offset); err != nil {
+			break
+		}
+		length := int64(binary.BigEndian.Uint32(header[0:4]))
+		if offset+recordHeaderSize+length > info.Size() {
+			break
+		}
+		data := make([]byte, length)
+		if _, err := q.writer.ReadAt(data, offset+recordHeaderSize); err != nil {
+			break
+		}
+		if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[4:8]) {
+			break
+		}
+		offset += recordHeaderSize + length
+	}
+
+	if info.Size() > offset {
+		if err := q.writer.Truncate(offset); err != nil {
+			return errors.Wrap(err, "truncate segment")
+		}
+		q.size -= info.Size() - offset
+	}
+	q.write.Offset = offset
+	return nil
+}
+
+// close closes the open segments. The records remain on disk.
+func (q *diskQueue) close() error {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	var err error
+	if q.writer != nil {
+		err = q.writer.Close()
+		q.writer = nil
+	}
+	if q.reader != nil {
+		_ = q.reader.Close()
+		q.reader = nil
+	}
+	return err
+}
+
+// push appends records to the queue, and syncs them to disk once they have all
+// been written. It returns the number of records that were written, along with a
+// backpressure error if the next record would cause the queue to exceed its size.
+func (q *diskQueue) push(records ...[]byte) (int, error) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	if q.writer == nil {
+		return 0, errClosed
+	}
+
+	var pushed int
+	var err error
+	for _, data := range records {
+		if err = q.append(data); err != nil {
+			break
+		}
+		pushed++
+	}
+	if pushed == 0 {
+		return 0, err
+	}
+
+	if syncErr := q.writer.Sync(); syncErr != nil {
+		return 0, errors.Wrap(syncErr, "sync segment")
+	}
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return pushed, err
+}
+
+// append writes a record to the newest segment
+func (q *diskQueue) append(data []byte) error {
+	recordSize := int64(recordHeaderSize + len(data))
+	if q.size+recordSize > q.maxSize {
+		return errors.NewBackpressureError("buffer is full")
+	}
+
+	if q.write.Offset > 0 && q.write.Offset+recordSize > q.segmentSize {
+		if err := q.rollSegment(); err != nil {
+			return err
+		}
+	}
+
+	record := make([]byte, recordSize)
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(data)))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(data))
+	copy(record[recordHeaderSize:], data)
+	if _, err := q.writer.Write(record); err != nil {
+		return errors.Wrap(err, "write record")
+	}
+
+	q.write.Offset += recordSize
+	q.size += recordSize
+	return nil
+}
+
+// peek reads the oldest record in the queue without removing it. It returns the
+// cursor following the record, which must be committed once it has been handled.
+// If the queue is empty, ok is false.
+func (q *diskQueue) peek(ctx context.Context) (data []byte, next cursor, ok bool, err error) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	for {
+		if q.read == q.write {
+			return nil, q.read, false, nil
+		}
+
+		if q.reader == nil {
+			q.reader, err = os.Open(q.segmentPath(q.read.Segment))
+			if err != nil {
+				return nil, q.read, false, errors.Wrap(err, "open segment")
+			}
+		}
+
+		header := make([]byte, recordHeaderSize)
+		_, err = q.reader.ReadAt(header, q.read.Offset)
+		if err == io.EOF && q.read.Segment < q.write.Segment {
+			// The segment has been read entirely
+			if err := q.removeReadSegment(ctx); err != nil {
+				return nil, q.read, false, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, q.read, false, errors.Wrap(err, "read record")
+		}
+
+		length := int64(binary.BigEndian.Uint32(header[0:4]))
+		if length > q.maxSize {
+			return nil, q.read, false, errCorruptRecord
+		}
+
+		data = make([]byte, length)
+		if _, err := q.reader.ReadAt(data, q.read.Offset+recordHeaderSize); err != nil {
+			return nil, q.read, false, errCorruptRecord
+		}
+		if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[4:8]) {
+			return nil, q.read, false, errCorruptRecord
+		}
+
+		next = cursor{Segment: q.read.Segment, Offset: q.read.Offset + int64(len(header)+len(data))}
+		return data, next, true, nil
+	}
+}
+
+// commit advances the read cursor past a record returned by peek
+func (q *diskQueue) commit(ctx context.Context, next cursor) error {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	q.read = next
+	return q.save(ctx)
+}
+
+// skipSegment discards the remaining records in the segment being read.
+// It is used to recover from corrupt records.
+func (q *diskQueue) skipSegment(ctx context.Context) error {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	if q.read.Segment == q.write.Segment {
+		q.read = q.write
+		return q.save(ctx)
+	}
+	return q.removeReadSegment(ctx)
+}
+
+// rollSegment starts writing to a new segment
+func (q *diskQueue) rollSegment() error {
+	if err := q.writer.Sync(); err != nil {
+		return errors.Wrap(err, "sync segment")
+	}
+	if err := q.writer.Close(); err != nil {
+		return errors.Wrap(err, "close segment")
+	}
+
+	writer, err := os.OpenFile(q.segmentPath(q.write.Segment+1), os.O_CREATE|os.O_RDWR|os.O_TRUNC|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Wrap(err, "open segment")
+	}
+
+	q.writer = writer
+	q.write = cursor{Segment: q.write.Segment + 1}
+	return nil
+}
+
+// removeReadSegment deletes the segment being read and moves to the next one
+func (q *diskQueue) removeReadSegment(ctx context.Context) error {
+	if q.reader != nil {
+		_ = q.reader.Close()
+		q.reader = nil
+	}
+
+	q.read = cursor{Segment: q.read.Segment + 1}
+	if err := q.save(ctx); err != nil {
+		return err
+	}
+
+	path := q.segmentPath(q.read.Segment - 1)
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "stat segment")
+	}
+
+	if err := os.Remove(path); err != nil {
+		return errors.Wrap(err, "remove segment")
+	}
+	q.size -= info.Size()
+	return nil
+}
+
+// save persists the read cursor
+func (q *diskQueue) save(ctx context.Context) error {
+	cursors, err := json.Marshal(map[string]cursor{
+		"read": q.read,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := q.persister.Set(ctx, cursorsKey, cursors); err != nil {
+		return errors.Wrap(err, "save buffer cursors")
+	}
+	return nil
+}
+
+// segments returns the sorted sequence numbers of the segments on disk
+func (q *diskQueue) segments() ([]int, error) {
+	files, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "read buffer directory")
+	}
+
+	segments := make([]int, 0, len(files))
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		segment, err := strconv.Atoi(strings.TrimSuffix(name, segmentSuffix))
+		if err != nil {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	sort.Ints(segments)
+	return segments, nil
+}
+
+func (q *diskQueue) segmentPath(segment int) string {
+	return filepath.Join(q.dir, fmt.Sprintf("%020d%s", segment, segmentSuffix))
+}
@@ -77,6 +77,11 @@ func (p *CopyOperator) Process(ctx context.Context, entry *entry.Entry) error {
 	return p.ProcessWith(ctx, entry, p.Transform)
 }
 
+// ProcessBatch will process a batch of entries with a copy transformation.
+func (p *CopyOperator) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return p.ProcessBatchWith(ctx, entries, p.Transform)
+}
+
 // Transform will apply the copy operation to an entry
 func (p *CopyOperator) Transform(e *entry.Entry) error {
 	val, exist := p.From.Get(e)
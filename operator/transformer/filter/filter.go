@@ -20,7 +20,6 @@ import (
 	"fmt"
 	"math/big"
 
-	"github.com/antonmedv/expr"
 	"github.com/antonmedv/expr/vm"
 	"go.uber.org/zap"
 
@@ -59,7 +58,7 @@ func (c FilterOperatorConfig) Build(logger *zap.SugaredLogger) (operator.Operato
 		return nil, err
 	}
 
-	compiledExpression, err := expr.Compile(c.Expression, expr.AsBool(), expr.AllowUndefinedVariables())
+	compiledExpression, err := helper.ExprCompileBool(c.Expression)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile expression '%s': %w", c.Expression, err)
 	}
@@ -83,35 +82,48 @@ type FilterOperator struct {
 }
 
 // Process will drop incoming entries that match the filter expression
-func (f *FilterOperator) Process(ctx context.Context, entry *entry.Entry) error {
+func (f *FilterOperator) Process(ctx context.Context, entry *entry.Entry) (err error) {
+	defer func() { f.Telemetry().ReceivedUnlessRejected(ctx, err) }()
+
 	env := helper.GetExprEnv(entry)
 	defer helper.PutExprEnv(env)
 
 	matches, err := vm.Run(f.expression, env)
 	if err != nil {
 		f.Errorf("Running expressing returned an error", zap.Error(err))
+		f.Telemetry().Dropped(ctx)
+		entry.Ack()
+		entry.Release()
 		return nil
 	}
 
 	filtered, ok := matches.(bool)
 	if !ok {
 		f.Errorf("Expression did not compile as a boolean")
+		f.Telemetry().Dropped(ctx)
+		entry.Ack()
+		entry.Release()
 		return nil
 	}
 
 	if !filtered {
-		f.Write(ctx, entry)
-		return nil
+		return f.Write(ctx, entry)
 	}
 
 	i, err := randInt(rand.Reader, upperBound)
 	if err != nil {
+		f.Telemetry().Dropped(ctx)
+		entry.Ack()
+		entry.Release()
 		return err
 	}
 
 	if i.Cmp(f.dropCutoff) >= 0 {
-		f.Write(ctx, entry)
+		return f.Write(ctx, entry)
 	}
 
+	f.Telemetry().Dropped(ctx)
+	entry.Ack()
+	entry.Release()
 	return nil
 }
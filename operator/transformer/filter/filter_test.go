@@ -165,6 +165,33 @@ func TestFilterOperator(t *testing.T) {
 			`env("TEST_FILTER_OPERATOR_ENV") == "bar"`,
 			false,
 		},
+		{
+			"MatchSeverityName",
+			&entry.Entry{
+				Body:     "message",
+				Severity: entry.Debug,
+			},
+			`severity < "info"`,
+			true,
+		},
+		{
+			"NoMatchSeverityName",
+			&entry.Entry{
+				Body:     "message",
+				Severity: entry.Warn,
+			},
+			`severity < "info"`,
+			false,
+		},
+		{
+			"MatchScopeName",
+			&entry.Entry{
+				Body:      "message",
+				ScopeName: "noisy",
+			},
+			`scope_name == "noisy"`,
+			true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -210,10 +237,13 @@ func TestFilterDropRatio(t *testing.T) {
 	filterOperator.OutputOperators = []operator.Operator{mockOutput}
 	require.True(t, ok)
 
-	testEntry := &entry.Entry{
-		Body: map[string]interface{}{
-			"message": "test_message",
-		},
+	// Dropped entries are released, so each entry is only processed once
+	newTestEntry := func() *entry.Entry {
+		return &entry.Entry{
+			Body: map[string]interface{}{
+				"message": "test_message",
+			},
+		}
 	}
 
 	nextIndex := 0
@@ -226,14 +256,44 @@ func TestFilterDropRatio(t *testing.T) {
 	}
 
 	for i := 1; i < 11; i++ {
-		err = filterOperator.Process(context.Background(), testEntry)
+		err = filterOperator.Process(context.Background(), newTestEntry())
 		require.NoError(t, err)
 	}
 
 	for i := 1; i < 11; i++ {
-		err = filterOperator.Process(context.Background(), testEntry)
+		err = filterOperator.Process(context.Background(), newTestEntry())
 		require.NoError(t, err)
 	}
 
 	require.Equal(t, 10, processedEntries)
 }
+
+func TestFilterAcknowledgesDropped(t *testing.T) {
+	cfg := NewFilterOperatorConfig("test")
+	cfg.Expression = `body.message == "test_message"`
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	mockOutput := testutil.NewMockOperator("output")
+	mockOutput.On("Process", mock.Anything, mock.Anything).Return(nil)
+
+	filterOperator, ok := op.(*FilterOperator)
+	require.True(t, ok)
+	filterOperator.OutputOperators = []operator.Operator{mockOutput}
+
+	acked := false
+	testEntry := entry.New()
+	testEntry.Body = map[string]interface{}{
+		"message": "test_message",
+	}
+	testEntry.OnAck(func() { acked = true })
+
+	entry.SetPoolDebug(true)
+	defer entry.SetPoolDebug(false)
+
+	err = filterOperator.Process(context.Background(), testEntry)
+	require.NoError(t, err)
+	require.True(t, acked)
+	mockOutput.AssertNotCalled(t, "Process", mock.Anything, mock.Anything)
+	require.PanicsWithValue(t, "entry was used after it was released", testEntry.Ack, "dropped entry should be released")
+}
@@ -72,6 +72,11 @@ func (p *FlattenOperator) Process(ctx context.Context, entry *entry.Entry) error
 	return p.ProcessWith(ctx, entry, p.Transform)
 }
 
+// ProcessBatch will process a batch of entries with a flatten transformation.
+func (p *FlattenOperator) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return p.ProcessBatchWith(ctx, entries, p.Transform)
+}
+
 // Transform will apply the flatten operation to an entry
 func (p *FlattenOperator) Transform(entry *entry.Entry) error {
 	parent := p.Field.Parent()
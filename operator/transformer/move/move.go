@@ -73,6 +73,11 @@ func (p *MoveOperator) Process(ctx context.Context, entry *entry.Entry) error {
 	return p.ProcessWith(ctx, entry, p.Transform)
 }
 
+// ProcessBatch will process a batch of entries with a move transformation.
+func (p *MoveOperator) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return p.ProcessBatchWith(ctx, entries, p.Transform)
+}
+
 // Transform will apply the move operation to an entry
 func (p *MoveOperator) Transform(e *entry.Entry) error {
 	val, exist := p.From.Delete(e)
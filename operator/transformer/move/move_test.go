@@ -507,6 +507,33 @@ func TestProcessAndBuild(t *testing.T) {
 				return e
 			},
 		},
+		{
+			"MoveNegativeIndexToAttribute",
+			false,
+			func() *MoveOperatorConfig {
+				from, err := entry.NewField("body.items[-1]")
+				require.NoError(t, err)
+				cfg := defaultCfg()
+				cfg.From = from
+				cfg.To = entry.NewAttributeField("last")
+				return cfg
+			}(),
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"items": []interface{}{"a", "b", "c"},
+				}
+				return e
+			},
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"items": []interface{}{"a", "b"},
+				}
+				e.Attributes = map[string]interface{}{"last": "c"}
+				return e
+			},
+		},
 	}
 	for _, tc := range cases {
 		t.Run("BuildandProcess/"+tc.name, func(t *testing.T) {
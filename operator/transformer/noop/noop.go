@@ -59,6 +59,14 @@ type NoopOperator struct {
 
 // Process will forward the entry to the next output without any alterations.
 func (p *NoopOperator) Process(ctx context.Context, entry *entry.Entry) error {
-	p.Write(ctx, entry)
-	return nil
+	err := p.Write(ctx, entry)
+	p.Telemetry().ReceivedUnlessRejected(ctx, err)
+	return err
+}
+
+// ProcessBatch will forward a batch of entries to the next output without any alterations.
+func (p *NoopOperator) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	err := p.WriteBatch(ctx, entries)
+	p.Telemetry().ReceivedBatchUnlessRejected(ctx, len(entries), err)
+	return err
 }
@@ -23,9 +23,13 @@ import (
 
 	"github.com/antonmedv/expr"
 	"github.com/antonmedv/expr/vm"
+	"github.com/jpillora/backoff"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-log-collection/clock"
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
 )
@@ -56,7 +60,7 @@ type RecombineOperatorConfig struct {
 	CombineField             entry.Field   `json:"combine_field"      yaml:"combine_field"`
 	CombineWith              string        `json:"combine_with"       yaml:"combine_with"`
 	SourceIdentifier         entry.Field   `json:"source_identifier"  yaml:"source_identifier"`
-	OverwriteWith            string        `json:"overwrite_with"     yaml:"overwrite_with"`
+	OverwriteWith            string        `json:"overwrite_with"     yaml:"overwrite_with"     jsonschema:"enum=oldest|newest"`
 	ForceFlushTimeout        time.Duration `json:"force_flush_period" yaml:"force_flush_period"`
 	MaxSources               int           `json:"max_sources"        yaml:"max_sources"`
 }
@@ -80,13 +84,13 @@ func (c *RecombineOperatorConfig) Build(logger *zap.SugaredLogger) (operator.Ope
 	var prog *vm.Program
 	if c.IsFirstEntry != "" {
 		matchesFirst = true
-		prog, err = expr.Compile(c.IsFirstEntry, expr.AsBool(), expr.AllowUndefinedVariables())
+		prog, err = helper.ExprCompileBool(c.IsFirstEntry)
 		if err != nil {
 			return nil, fmt.Errorf("failed to compile is_first_entry: %s", err)
 		}
 	} else {
 		matchesFirst = false
-		prog, err = expr.Compile(c.IsLastEntry, expr.AsBool(), expr.AllowUndefinedVariables())
+		prog, err = helper.ExprCompileBool(c.IsLastEntry)
 		if err != nil {
 			return nil, fmt.Errorf("failed to compile is_last_entry: %s", err)
 		}
@@ -114,10 +118,10 @@ func (c *RecombineOperatorConfig) Build(logger *zap.SugaredLogger) (operator.Ope
 		maxSources:          c.MaxSources,
 		overwriteWithOldest: overwriteWithOldest,
 		batchMap:            make(map[string][]*entry.Entry),
+		pending:             make(map[string][]*entry.Entry),
 		combineField:        c.CombineField,
 		combineWith:         c.CombineWith,
 		forceFlushTimeout:   c.ForceFlushTimeout,
-		ticker:              time.NewTicker(c.ForceFlushTimeout),
 		chClose:             make(chan struct{}),
 		sourceIdentifier:    c.SourceIdentifier,
 	}, nil
@@ -134,54 +138,82 @@ type RecombineOperator struct {
 	overwriteWithOldest bool
 	combineField        entry.Field
 	combineWith         string
-	ticker              *time.Ticker
 	forceFlushTimeout   time.Duration
 	chClose             chan struct{}
 	sourceIdentifier    entry.Field
 
 	sync.Mutex
 	batchMap map[string][]*entry.Entry
+
+	// pending holds the flushed entries of each source that were rejected with
+	// backpressure, which are written before any other entry of the source
+	pending map[string][]*entry.Entry
 }
 
 func (r *RecombineOperator) Start(_ operator.Persister) error {
-	go r.flushLoop()
+	go r.flushLoop(r.Clock().NewTicker(r.forceFlushTimeout))
 
 	return nil
 }
 
-func (r *RecombineOperator) flushLoop() {
+func (r *RecombineOperator) flushLoop(ticker clock.Ticker) {
+	defer ticker.Stop()
 	for {
 		select {
-		case <-r.ticker.C:
+		case <-ticker.C():
 			r.Lock()
-			timeNow := time.Now()
+			timeNow := r.Clock().Now()
 			for source, entries := range r.batchMap {
 				lastEntryTs := entries[len(entries)-1].Timestamp
 				timeSinceLastEntry := timeNow.Sub(lastEntryTs)
 				if timeSinceLastEntry < r.forceFlushTimeout {
 					continue
 				}
-				if err := r.flushSource(source); err != nil {
+				if err := r.flushSource(context.Background(), source); err != nil && !errors.IsBackpressure(err) {
+					r.Errorf("there was error flushing combined logs %s", err)
+				}
+			}
+			for source := range r.pending {
+				if err := r.writePending(context.Background(), source); err != nil && !errors.IsBackpressure(err) {
 					r.Errorf("there was error flushing combined logs %s", err)
 				}
 			}
-
-			r.ticker.Reset(r.forceFlushTimeout)
 			r.Unlock()
 		case <-r.chClose:
-			r.ticker.Stop()
 			return
 		}
 	}
 }
 
+// Drain combines the entries of each batch, including partial batches,
+// and writes the combined entries to the outputs of the operator.
+func (r *RecombineOperator) Drain(ctx context.Context) error {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.flushCombined(ctx)
+}
+
 func (r *RecombineOperator) Stop() error {
 	r.Lock()
 	defer r.Unlock()
 
+	// Entries that were not drained are flushed with a short timeout
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
-	r.flushUncombined(ctx)
+	if err := r.flushCombined(ctx); err != nil {
+		r.Errorw("Failed to flush batched entries", zap.Error(err))
+	}
+
+	// Entries that could not be written are not acknowledged, so that they are read again
+	for source, entries := range r.pending {
+		r.Warnw("Discarding entries rejected by downstream operators", "source", source, "count", len(entries))
+		for _, e := range entries {
+			e.Abandon()
+			e.Release()
+		}
+		delete(r.pending, source)
+	}
 
 	close(r.chClose)
 
@@ -190,7 +222,9 @@ func (r *RecombineOperator) Stop() error {
 
 const DefaultSourceIdentifier = "DefaultSourceIdentifier"
 
-func (r *RecombineOperator) Process(ctx context.Context, e *entry.Entry) error {
+func (r *RecombineOperator) Process(ctx context.Context, e *entry.Entry) (err error) {
+	defer func() { r.Telemetry().ReceivedUnlessRejected(ctx, err) }()
+
 	// Lock the recombine operator because process can't run concurrently
 	r.Lock()
 	defer r.Unlock()
@@ -202,6 +236,31 @@ func (r *RecombineOperator) Process(ctx context.Context, e *entry.Entry) error {
 	env := helper.GetExprEnv(e)
 	defer helper.PutExprEnv(env)
 
+	return r.process(ctx, env, e)
+}
+
+// ProcessBatch will process a batch of entries, holding the lock for the whole batch.
+func (r *RecombineOperator) ProcessBatch(ctx context.Context, entries []*entry.Entry) (err error) {
+	defer func() { r.Telemetry().ReceivedBatchUnlessRejected(ctx, len(entries), err) }()
+
+	r.Lock()
+	defer r.Unlock()
+
+	var errs error
+	for i, e := range entries {
+		env := helper.GetExprEnv(e)
+		err := r.process(ctx, env, e)
+		helper.PutExprEnv(env)
+		if errors.IsBackpressure(err) {
+			return errors.NewBatchError(i, multierr.Append(errs, err))
+		}
+		errs = multierr.Append(errs, err)
+	}
+	return errs
+}
+
+// process adds an entry to its batch, flushing the batch if it is complete. It must be called while holding the lock.
+func (r *RecombineOperator) process(ctx context.Context, env map[string]interface{}, e *entry.Entry) error {
 	m, err := expr.Run(r.prog, env)
 	if err != nil {
 		return r.HandleEntryError(ctx, e, err)
@@ -220,25 +279,37 @@ func (r *RecombineOperator) Process(ctx context.Context, e *entry.Entry) error {
 		s = DefaultSourceIdentifier
 	}
 
+	// Entries of a source are rejected until its earlier entries have been written
+	if err := r.writePending(ctx, s); errors.IsBackpressure(err) {
+		return err
+	} else if err != nil {
+		r.Errorf("there was error flushing combined logs %s", err)
+	}
+
 	switch {
 	// This is the first entry in the next batch
 	case matches && r.matchIndicatesFirst():
 		// Flush the existing batch
-		err := r.flushSource(s)
-		if err != nil {
+		err := r.flushSource(ctx, s)
+		if errors.IsBackpressure(err) {
+			// The existing batch is pending, and this entry is retried by the caller
 			return err
 		}
 
 		// Add the current log to the new batch
 		r.addToBatch(ctx, e, s)
-		return nil
+		return err
 	// This is the last entry in a complete batch
 	case matches && r.matchIndicatesLast():
 		fallthrough
 	// When matching on first entry, never batch partial first. Just emit immediately
 	case !matches && r.matchIndicatesFirst() && len(r.batchMap[s]) == 0:
 		r.addToBatch(ctx, e, s)
-		return r.flushSource(s)
+		if err := r.flushSource(ctx, s); !errors.IsBackpressure(err) {
+			return err
+		}
+		// The combined entry, which includes this entry, is pending
+		return nil
 	}
 
 	// This is neither the first entry of a new log,
@@ -256,19 +327,21 @@ func (r *RecombineOperator) matchIndicatesLast() bool {
 }
 
 // addToBatch adds the current entry to the current batch of entries that will be combined
-func (r *RecombineOperator) addToBatch(_ context.Context, e *entry.Entry, source string) {
+func (r *RecombineOperator) addToBatch(ctx context.Context, e *entry.Entry, source string) {
 	if _, ok := r.batchMap[source]; !ok {
 		r.batchMap[source] = []*entry.Entry{e}
 		if len(r.batchMap) >= r.maxSources {
 			r.Error("Batched source exceeds max source size. Flushing all batched logs. Consider increasing max_sources parameter")
-			r.flushUncombined(context.Background())
+			if err := r.flushUncombined(ctx); err != nil && !errors.IsBackpressure(err) {
+				r.Errorf("there was error flushing uncombined logs %s", err)
+			}
 		}
 		return
 	}
 
 	r.batchMap[source] = append(r.batchMap[source], e)
 	if len(r.batchMap[source]) >= r.maxBatchSize {
-		if err := r.flushSource(source); err != nil {
+		if err := r.flushSource(ctx, source); err != nil && !errors.IsBackpressure(err) {
 			r.Errorf("there was error flushing combined logs %s", err)
 		}
 	}
@@ -276,23 +349,97 @@ func (r *RecombineOperator) addToBatch(_ context.Context, e *entry.Entry, source
 
 // flushUncombined flushes all the logs in the batch individually to the
 // next output in the pipeline. This is only used when there is an error
-// or at shutdown to avoid dropping the logs.
-func (r *RecombineOperator) flushUncombined(ctx context.Context) {
+// or at shutdown to avoid dropping the logs. Entries that are rejected
+// with backpressure are kept as pending entries.
+func (r *RecombineOperator) flushUncombined(ctx context.Context) error {
+	var errs error
+	for source, entries := range r.batchMap {
+		r.pending[source] = append(r.pending[source], entries...)
+		errs = multierr.Append(errs, r.writePending(ctx, source))
+	}
+	r.batchMap = make(map[string][]*entry.Entry)
+	return errs
+}
+
+// flushCombined combines the entries of every batch, and writes the combined entries
+// to the next operator in the pipeline, retrying pending entries until the context is done.
+func (r *RecombineOperator) flushCombined(ctx context.Context) error {
+	var errs error
 	for source := range r.batchMap {
-		for _, entry := range r.batchMap[source] {
-			r.Write(ctx, entry)
+		base, err := r.combine(source)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		if base != nil {
+			r.pending[source] = append(r.pending[source], base)
+		}
+	}
+
+	retry := backoff.Backoff{
+		Min: 10 * time.Millisecond,
+		Max: time.Second,
+	}
+	for {
+		var pressure error
+		for source := range r.pending {
+			err := r.writePending(ctx, source)
+			if errors.IsBackpressure(err) {
+				pressure = err
+			} else {
+				errs = multierr.Append(errs, err)
+			}
+		}
+		if pressure == nil {
+			return errs
+		}
+
+		select {
+		case <-ctx.Done():
+			return multierr.Append(errs, pressure)
+		case <-r.Clock().After(retry.Duration()):
 		}
 	}
-	r.batchMap = make(map[string][]*entry.Entry)
-	r.ticker.Reset(r.forceFlushTimeout)
 }
 
 // flushSource combines the entries currently in the batch into a single entry,
-// then forwards them to the next operator in the pipeline
-func (r *RecombineOperator) flushSource(source string) error {
+// then forwards them to the next operator in the pipeline. If the combined entry
+// is rejected with backpressure, it is kept as a pending entry of the source.
+func (r *RecombineOperator) flushSource(ctx context.Context, source string) error {
+	base, err := r.combine(source)
+	if err != nil {
+		return err
+	}
+	if base != nil {
+		r.pending[source] = append(r.pending[source], base)
+	}
+	return r.writePending(ctx, source)
+}
+
+// writePending writes the pending entries of a source in order, stopping at
+// the first entry that is rejected with backpressure.
+func (r *RecombineOperator) writePending(ctx context.Context, source string) error {
+	var errs error
+	entries := r.pending[source]
+	for len(entries) > 0 {
+		err := r.Write(ctx, entries[0])
+		if errors.IsBackpressure(err) {
+			r.pending[source] = entries
+			return multierr.Append(errs, err)
+		}
+		errs = multierr.Append(errs, err)
+		entries = entries[1:]
+	}
+	delete(r.pending, source)
+	return errs
+}
+
+// combine combines the entries in the batch of a source into a single entry,
+// and removes the batch. It returns nil if the batch is empty.
+func (r *RecombineOperator) combine(source string) (*entry.Entry, error) {
 	// Skip flushing a combined log if the batch is empty
 	if len(r.batchMap[source]) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// Choose which entry we want to keep the rest of the fields from
@@ -309,6 +456,11 @@ func (r *RecombineOperator) flushSource(source string) error {
 	// separated by newlines
 	var recombined strings.Builder
 	for i, e := range entries {
+		// The combined entries are acknowledged along with the base entry
+		if e != base {
+			base.MergeAcks(e)
+		}
+
 		var s string
 		err := e.Read(r.combineField, &s)
 		if err != nil {
@@ -325,11 +477,14 @@ func (r *RecombineOperator) flushSource(source string) error {
 	// Set the recombined field on the entry
 	err := base.Set(r.combineField, recombined.String())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	r.Write(context.Background(), base)
-
+	for _, e := range entries {
+		if e != base {
+			e.Release()
+		}
+	}
 	delete(r.batchMap, source)
-	return nil
+	return base, nil
 }
@@ -19,9 +19,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
 )
@@ -395,24 +397,26 @@ func TestTimeout(t *testing.T) {
 	require.NoError(t, err)
 	recombine := op.(*RecombineOperator)
 
+	clock := testutil.NewFakeClock(time.Now())
+	recombine.SetClock(clock)
+
 	fake := testutil.NewFakeOutput(t)
 	require.NoError(t, recombine.SetOutputs([]operator.Operator{fake}))
 
 	e := entry.New()
-	e.Timestamp = time.Now()
+	e.Timestamp = clock.Now()
 	e.Body = "body"
 
 	ctx := context.Background()
 
-	recombine.Start(nil)
+	require.NoError(t, recombine.Start(nil))
 	require.NoError(t, recombine.Process(ctx, e))
-	select {
-	case <-fake.Received:
-		t.Logf("We shouldn't receive an entry before timeout")
-		t.FailNow()
-	case <-time.After(50 * time.Millisecond):
-	}
 
+	// The flush loop does not run until the timeout has elapsed
+	clock.Advance(50 * time.Millisecond)
+	require.Len(t, fake.Received, 0, "entry was flushed before the timeout")
+
+	clock.Advance(50 * time.Millisecond)
 	select {
 	case <-fake.Received:
 	case <-time.After(5 * time.Second):
@@ -422,3 +426,149 @@ func TestTimeout(t *testing.T) {
 
 	require.NoError(t, recombine.Stop())
 }
+
+func TestRecombineAcknowledges(t *testing.T) {
+	cfg := NewRecombineOperatorConfig("")
+	cfg.CombineField = entry.NewBodyField()
+	cfg.IsLastEntry = `body == "end"`
+	cfg.OutputIDs = []string{"fake"}
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	recombine := op.(*RecombineOperator)
+
+	fake := testutil.NewFakeOutput(t)
+	require.NoError(t, recombine.SetOutputs([]operator.Operator{fake}))
+
+	acked := 0
+	ctx := context.Background()
+	for _, body := range []string{"start", "middle", "end"} {
+		e := entry.New()
+		e.Body = body
+		e.OnAck(func() { acked++ })
+		require.NoError(t, recombine.Process(ctx, e))
+	}
+
+	fake.ExpectBody(t, "start\nmiddle\nend")
+	require.Equal(t, 3, acked, "combined entries should be acknowledged with the recombined entry")
+}
+
+func TestRecombineBackpressure(t *testing.T) {
+	cfg := NewRecombineOperatorConfig("")
+	cfg.CombineField = entry.NewBodyField()
+	cfg.IsLastEntry = `body == "end"`
+	cfg.OutputIDs = []string{"output"}
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	recombine := op.(*RecombineOperator)
+
+	var received []*entry.Entry
+	output := &testutil.Operator{}
+	output.On("ID").Return("output")
+	output.On("CanProcess").Return(true)
+	output.On("Process", mock.Anything, mock.Anything).Return(errors.NewBackpressureError("queue is full")).Once()
+	output.On("Process", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		received = append(received, args.Get(1).(*entry.Entry))
+	}).Return(nil)
+	require.NoError(t, recombine.SetOutputs([]operator.Operator{output}))
+
+	acked := 0
+	ctx := context.Background()
+	for _, body := range []string{"start", "middle", "end"} {
+		e := entry.New()
+		e.Body = body
+		e.OnAck(func() { acked++ })
+		require.NoError(t, recombine.Process(ctx, e))
+	}
+
+	// The rejected combined entry is kept until it is written
+	require.Empty(t, received)
+	require.Equal(t, 0, acked)
+
+	// The pending entry is written before the next entry of the source
+	e := entry.New()
+	e.Body = "next"
+	require.NoError(t, recombine.Process(ctx, e))
+	require.Len(t, received, 1)
+	require.Equal(t, "start\nmiddle\nend", received[0].Body)
+
+	require.NoError(t, recombine.Drain(ctx))
+	require.Len(t, received, 2)
+	require.Equal(t, "next", received[1].Body)
+
+	received[0].Ack()
+	require.Equal(t, 3, acked)
+}
+
+func TestRecombineDrain(t *testing.T) {
+	cfg := NewRecombineOperatorConfig("")
+	cfg.CombineField = entry.NewBodyField()
+	cfg.IsLastEntry = `body == "end"`
+	cfg.OutputIDs = []string{"fake"}
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	recombine := op.(*RecombineOperator)
+
+	fake := testutil.NewFakeOutput(t)
+	require.NoError(t, recombine.SetOutputs([]operator.Operator{fake}))
+	require.NoError(t, recombine.Start(testutil.NewUnscopedMockPersister()))
+
+	ctx := context.Background()
+	for _, body := range []string{"start", "middle"} {
+		e := entry.New()
+		e.Body = body
+		require.NoError(t, recombine.Process(ctx, e))
+	}
+	fake.ExpectNoEntry(t, 10*time.Millisecond)
+
+	require.NoError(t, recombine.Drain(ctx))
+	fake.ExpectBody(t, "start\nmiddle")
+	fake.ExpectNoEntry(t, 10*time.Millisecond)
+
+	require.NoError(t, recombine.Stop())
+	fake.ExpectNoEntry(t, 10*time.Millisecond)
+}
+
+func TestRecombineDrainSources(t *testing.T) {
+	cfg := NewRecombineOperatorConfig("")
+	cfg.CombineField = entry.NewBodyField()
+	cfg.IsFirstEntry = `body matches "^[^\\s]"`
+	cfg.SourceIdentifier = entry.NewAttributeField("file.path")
+	cfg.OutputIDs = []string{"fake"}
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	recombine := op.(*RecombineOperator)
+
+	fake := testutil.NewFakeOutput(t)
+	require.NoError(t, recombine.SetOutputs([]operator.Operator{fake}))
+	require.NoError(t, recombine.Start(testutil.NewUnscopedMockPersister()))
+
+	acked := 0
+	ctx := context.Background()
+	for _, source := range []string{"a.log", "b.log"} {
+		for _, body := range []string{"Exception in thread main", "  at com.example.Main"} {
+			e := entry.New()
+			e.Body = body
+			e.AddAttribute("file.path", source)
+			e.OnAck(func() { acked++ })
+			require.NoError(t, recombine.Process(ctx, e))
+		}
+	}
+	fake.ExpectNoEntry(t, 10*time.Millisecond)
+
+	// Each partial stack trace is emitted as a single combined entry
+	require.NoError(t, recombine.Drain(ctx))
+	for i := 0; i < 2; i++ {
+		select {
+		case e := <-fake.Received:
+			require.Equal(t, "Exception in thread main\n  at com.example.Main", e.Body)
+			e.Ack()
+		case <-time.After(time.Second):
+			require.FailNow(t, "Timed out waiting for entry")
+		}
+	}
+	fake.ExpectNoEntry(t, 10*time.Millisecond)
+	require.Equal(t, 4, acked)
+
+	require.NoError(t, recombine.Stop())
+	fake.ExpectNoEntry(t, 10*time.Millisecond)
+}
@@ -71,6 +71,11 @@ func (p *RemoveOperator) Process(ctx context.Context, entry *entry.Entry) error
 	return p.ProcessWith(ctx, entry, p.Transform)
 }
 
+// ProcessBatch will process a batch of entries with a remove transformation.
+func (p *RemoveOperator) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return p.ProcessBatchWith(ctx, entries, p.Transform)
+}
+
 // Transform will apply the remove operation to an entry
 func (p *RemoveOperator) Transform(entry *entry.Entry) error {
 	if p.Field.allAttributes {
@@ -257,6 +257,61 @@ func TestProcessAndBuild(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"remove_index",
+			func() *RemoveOperatorConfig {
+				field, err := entry.NewField("body.items[0]")
+				require.NoError(t, err)
+				cfg := defaultCfg()
+				cfg.Field = rootableField{Field: field}
+				return cfg
+			}(),
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"items": []interface{}{"a", "b", "c"},
+				}
+				return e
+			},
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"items": []interface{}{"b", "c"},
+				}
+				return e
+			},
+			false,
+		},
+		{
+			"remove_glob",
+			func() *RemoveOperatorConfig {
+				field, err := entry.NewField("attributes.http.*")
+				require.NoError(t, err)
+				cfg := defaultCfg()
+				cfg.Field = rootableField{Field: field}
+				return cfg
+			}(),
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Attributes = map[string]interface{}{
+					"host": "localhost",
+					"http": map[string]interface{}{
+						"method": "GET",
+						"url":    "/",
+					},
+				}
+				return e
+			},
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Attributes = map[string]interface{}{
+					"host": "localhost",
+					"http": map[string]interface{}{},
+				}
+				return e
+			},
+			false,
+		},
 	}
 	for _, tc := range cases {
 		t.Run("BuildandProcess/"+tc.name, func(t *testing.T) {
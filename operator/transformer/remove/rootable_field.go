@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 )
 
 // RootableField represents a potential field on an entry.
@@ -32,6 +33,11 @@ type rootableField struct {
 	allAttributes bool
 }
 
+// JSONSchema describes a rootable field as a string
+func (f rootableField) JSONSchema() *operator.Schema {
+	return &operator.Schema{Type: "string"}
+}
+
 // UnmarshalJSON will unmarshal a field from JSON
 func (f *rootableField) UnmarshalJSON(raw []byte) error {
 	var s string
@@ -87,33 +87,48 @@ func (p *RetainOperator) Process(ctx context.Context, entry *entry.Entry) error
 	return p.ProcessWith(ctx, entry, p.Transform)
 }
 
+// ProcessBatch will process a batch of entries with a retain transformation.
+func (p *RetainOperator) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return p.ProcessBatchWith(ctx, entries, p.Transform)
+}
+
 // Transform will apply the retain operation to an entry
 func (p *RetainOperator) Transform(e *entry.Entry) error {
-	newEntry := entry.New()
-	newEntry.ObservedTimestamp = e.ObservedTimestamp
-	newEntry.Timestamp = e.Timestamp
+	// The retained values are set on the entry again, so they must not be shared with other entries
+	e.Unshare()
 
-	if !p.AllResourceFields {
-		newEntry.Resource = e.Resource
-	}
-	if !p.AllAttributeFields {
-		newEntry.Attributes = e.Attributes
+	// Wildcards are expanded so that only the matched values are set again
+	fields := make([]entry.Field, 0, len(p.Fields))
+	for _, field := range p.Fields {
+		fields = append(fields, field.Expand(e)...)
 	}
-	if !p.AllBodyFields {
-		newEntry.Body = e.Body
+
+	values := make([]interface{}, len(fields))
+	for i, field := range fields {
+		values[i], _ = e.Get(field)
 	}
 
-	for _, field := range p.Fields {
-		val, ok := e.Get(field)
-		if !ok {
-			continue
-		}
-		err := newEntry.Set(field, val)
+	if p.AllResourceFields {
+		e.Resource = nil
+	}
+	if p.AllAttributeFields {
+		e.Attributes = nil
+	}
+	if p.AllBodyFields {
+		e.Body = nil
+	}
+	e.SeverityText = ""
+	e.Severity = entry.Default
+	e.SpanId = nil
+	e.TraceId = nil
+	e.TraceFlags = nil
+	e.ScopeName = ""
+
+	for i, field := range fields {
+		err := e.Set(field, values[i])
 		if err != nil {
 			return err
 		}
 	}
-
-	*e = *newEntry
 	return nil
 }
@@ -389,6 +389,38 @@ func TestBuildAndProcess(t *testing.T) {
 				return e
 			},
 		},
+		{
+			"retain_wildcard_index",
+			false,
+			func() *RetainOperatorConfig {
+				field, err := entry.NewField("body.items[*].id")
+				require.NoError(t, err)
+				cfg := defaultCfg()
+				cfg.Fields = append(cfg.Fields, field)
+				return cfg
+			}(),
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"key": "val",
+					"items": []interface{}{
+						map[string]interface{}{"id": "a", "count": 1},
+						map[string]interface{}{"id": "b", "count": 2},
+					},
+				}
+				return e
+			},
+			func() *entry.Entry {
+				e := newTestEntry()
+				e.Body = map[string]interface{}{
+					"items": []interface{}{
+						map[string]interface{}{"id": "a"},
+						map[string]interface{}{"id": "b"},
+					},
+				}
+				return e
+			},
+		},
 	}
 	for _, tc := range cases {
 		t.Run("BuildandProcess/"+tc.name, func(t *testing.T) {
@@ -412,3 +444,29 @@ func TestBuildAndProcess(t *testing.T) {
 		})
 	}
 }
+
+func TestRetainSharedEntry(t *testing.T) {
+	cfg := NewRetainOperatorConfig("test")
+	cfg.OutputIDs = []string{"fake"}
+	cfg.Fields = []entry.Field{entry.NewAttributeField("key")}
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	fake := testutil.NewFakeOutput(t)
+	require.NoError(t, op.SetOutputs([]operator.Operator{fake}))
+
+	acked := false
+	original := entry.New()
+	original.AddAttribute("key", "value")
+	original.AddAttribute("other", "value")
+	original.OnAck(func() { acked = true })
+	shared := original.Share()
+
+	require.NoError(t, op.Process(context.Background(), shared))
+	received := <-fake.Received
+	require.Equal(t, map[string]interface{}{"key": "value"}, received.Attributes)
+	require.Equal(t, map[string]interface{}{"key": "value", "other": "value"}, original.Attributes)
+
+	original.Ack()
+	require.True(t, acked, "the retained entry should be acknowledged")
+}
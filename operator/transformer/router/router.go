@@ -18,11 +18,11 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/antonmedv/expr"
 	"github.com/antonmedv/expr/vm"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
 )
@@ -69,7 +69,7 @@ func (c RouterOperatorConfig) Build(logger *zap.SugaredLogger) (operator.Operato
 
 	routes := make([]*RouterOperatorRoute, 0, len(c.Routes))
 	for _, routeConfig := range c.Routes {
-		compiled, err := expr.Compile(routeConfig.Expression, expr.AsBool(), expr.AllowUndefinedVariables())
+		compiled, err := helper.ExprCompileBool(routeConfig.Expression)
 		if err != nil {
 			return nil, fmt.Errorf("failed to compile expression '%s': %w", routeConfig.Expression, err)
 		}
@@ -113,7 +113,9 @@ func (p *RouterOperator) CanProcess() bool {
 }
 
 // Process will route incoming entries based on matching expressions
-func (p *RouterOperator) Process(ctx context.Context, entry *entry.Entry) error {
+func (p *RouterOperator) Process(ctx context.Context, entry *entry.Entry) (err error) {
+	defer func() { p.Telemetry().ReceivedUnlessRejected(ctx, err) }()
+
 	env := helper.GetExprEnv(entry)
 	defer helper.PutExprEnv(env)
 
@@ -131,13 +133,24 @@ func (p *RouterOperator) Process(ctx context.Context, entry *entry.Entry) error
 				return err
 			}
 
-			for _, output := range route.OutputOperators {
-				_ = output.Process(ctx, entry)
+			if len(route.OutputOperators) == 0 {
+				entry.Ack()
+				entry.Release()
+				return nil
+			}
+
+			err := helper.WriteTo(ctx, route.OutputOperators, entry)
+			if !errors.IsBackpressure(err) {
+				p.Telemetry().Emitted(ctx)
 			}
-			break
+			return err
 		}
 	}
 
+	// Entries that do not match any route are dropped
+	p.Telemetry().Dropped(ctx)
+	entry.Ack()
+	entry.Release()
 	return nil
 }
 
@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
 	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
@@ -230,3 +231,96 @@ func TestRouterOperator(t *testing.T) {
 		})
 	}
 }
+
+func TestRouterOperatorAcknowledges(t *testing.T) {
+	cfg := NewRouterOperatorConfig("test_operator_id")
+	cfg.Routes = []*RouterOperatorRouteConfig{
+		{
+			Expression: `body.message == "test_message"`,
+			OutputIDs:  []string{"output1", "output2"},
+		},
+	}
+
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	received := []*entry.Entry{}
+	mock1 := testutil.NewMockOperator("output1")
+	mock1.On("Process", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		received = append(received, args[1].(*entry.Entry))
+	})
+	mock2 := testutil.NewMockOperator("output2")
+	mock2.On("Process", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		received = append(received, args[1].(*entry.Entry))
+	})
+
+	routerOperator := op.(*RouterOperator)
+	err = routerOperator.SetOutputs([]operator.Operator{mock1, mock2})
+	require.NoError(t, err)
+
+	t.Run("Routed", func(t *testing.T) {
+		acked := false
+		routed := entry.New()
+		routed.Body = map[string]interface{}{"message": "test_message"}
+		routed.OnAck(func() { acked = true })
+
+		require.NoError(t, routerOperator.Process(context.Background(), routed))
+		require.Len(t, received, 2)
+		require.NotSame(t, received[0], received[1], "each output should receive its own copy")
+
+		received[0].Ack()
+		require.False(t, acked, "entry should wait for every output")
+		received[1].Ack()
+		require.True(t, acked)
+	})
+
+	t.Run("Unrouted", func(t *testing.T) {
+		acked := false
+		unrouted := entry.New()
+		unrouted.Body = map[string]interface{}{"message": "other_message"}
+		unrouted.OnAck(func() { acked = true })
+
+		entry.SetPoolDebug(true)
+		defer entry.SetPoolDebug(false)
+
+		require.NoError(t, routerOperator.Process(context.Background(), unrouted))
+		require.True(t, acked, "dropped entry should be acknowledged")
+		require.PanicsWithValue(t, "entry was used after it was released", unrouted.Ack, "dropped entry should be released")
+	})
+}
+
+func TestRouterOperatorBackpressure(t *testing.T) {
+	cfg := NewRouterOperatorConfig("test_operator_id")
+	cfg.Routes = []*RouterOperatorRouteConfig{
+		{
+			Expression: "true",
+			OutputIDs:  []string{"output1", "output2"},
+		},
+	}
+
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	mock1 := testutil.NewMockOperator("output1")
+	mock1.On("Process", mock.Anything, mock.Anything).Return(errors.NewBackpressureError("queue is full")).Once()
+	mock1.On("Process", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		args[1].(*entry.Entry).Ack()
+	})
+	mock2 := testutil.NewMockOperator("output2")
+	mock2.On("Process", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		args[1].(*entry.Entry).Ack()
+	})
+
+	routerOperator := op.(*RouterOperator)
+	require.NoError(t, routerOperator.SetOutputs([]operator.Operator{mock1, mock2}))
+
+	acked := false
+	e := entry.New()
+	e.OnAck(func() { acked = true })
+
+	// The output that applied backpressure is retried without sending the entry to the other output again
+	require.NoError(t, routerOperator.Process(context.Background(), e))
+	mock1.AssertNumberOfCalls(t, "Process", 2)
+	mock2.AssertNumberOfCalls(t, "Process", 1)
+	require.True(t, acked)
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sizelimit
+
+import (
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper/operatortest"
+)
+
+func TestConfig(t *testing.T) {
+	cases := []operatortest.ConfigUnmarshalTest{
+		{
+			Name:      "default",
+			ExpectErr: false,
+			Expect:    defaultCfg(),
+		},
+		{
+			Name:      "max_size",
+			ExpectErr: false,
+			Expect: func() *SizeLimitOperatorConfig {
+				cfg := defaultCfg()
+				cfg.MaxSize = 1 << 20
+				return cfg
+			}(),
+		},
+		{
+			Name:      "action_drop",
+			ExpectErr: false,
+			Expect: func() *SizeLimitOperatorConfig {
+				cfg := defaultCfg()
+				cfg.MaxSize = 64 << 10
+				cfg.Action = dropAction
+				return cfg
+			}(),
+		},
+		{
+			Name:      "on_error",
+			ExpectErr: false,
+			Expect: func() *SizeLimitOperatorConfig {
+				cfg := defaultCfg()
+				cfg.MaxSize = 1024
+				cfg.OnError = helper.DropOnError
+				return cfg
+			}(),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tc.Run(t, defaultCfg())
+		})
+	}
+}
+
+func defaultCfg() *SizeLimitOperatorConfig {
+	return NewSizeLimitOperatorConfig("size_limit")
+}
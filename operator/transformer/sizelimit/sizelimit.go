@@ -0,0 +1,250 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sizelimit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
+)
+
+const (
+	truncateAction = "truncate"
+	dropAction     = "drop"
+)
+
+func init() {
+	operator.Register("size_limit", func() operator.Builder { return NewSizeLimitOperatorConfig("") })
+}
+
+// NewSizeLimitOperatorConfig creates a new size limit operator config with default values
+func NewSizeLimitOperatorConfig(operatorID string) *SizeLimitOperatorConfig {
+	return &SizeLimitOperatorConfig{
+		TransformerConfig: helper.NewTransformerConfig(operatorID, "size_limit"),
+		Action:            truncateAction,
+	}
+}
+
+// SizeLimitOperatorConfig is the configuration of a size limit operator
+type SizeLimitOperatorConfig struct {
+	helper.TransformerConfig `mapstructure:",squash" yaml:",inline"`
+	MaxSize                  helper.ByteSize `mapstructure:"max_size" json:"max_size" yaml:"max_size"`
+	Action                   string          `mapstructure:"action"   json:"action"   yaml:"action"   jsonschema:"enum=truncate|drop"`
+}
+
+// Build will build a size limit operator from the supplied configuration
+func (c SizeLimitOperatorConfig) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
+	transformerOperator, err := c.TransformerConfig.Build(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.MaxSize <= 0 {
+		return nil, fmt.Errorf("invalid value '%d' for parameter 'max_size'", c.MaxSize)
+	}
+
+	switch c.Action {
+	case truncateAction, dropAction:
+	default:
+		return nil, errors.NewError(
+			fmt.Sprintf("invalid action '%s'", c.Action),
+			"ensure that the `action` field is set to either `truncate` or `drop`",
+		)
+	}
+
+	return &SizeLimitOperator{
+		TransformerOperator: transformerOperator,
+		maxSize:             int(c.MaxSize),
+		action:              c.Action,
+	}, nil
+}
+
+// SizeLimitOperator truncates or drops entries that are larger than a maximum size, as estimated by entry.Size.
+type SizeLimitOperator struct {
+	helper.TransformerOperator
+	maxSize int
+	action  string
+}
+
+// Process will truncate or drop an entry that is larger than the maximum size.
+func (p *SizeLimitOperator) Process(ctx context.Context, e *entry.Entry) (err error) {
+	if p.action == truncateAction {
+		return p.ProcessWith(ctx, e, p.Transform)
+	}
+
+	defer func() { p.Telemetry().ReceivedUnlessRejected(ctx, err) }()
+	skip, err := p.Skip(ctx, e)
+	if err != nil {
+		return p.HandleEntryError(ctx, e, err)
+	}
+	if skip {
+		return p.Write(ctx, e)
+	}
+
+	if size := e.Size(); size > p.maxSize {
+		p.Debugw("Dropping entry that exceeds the maximum size", "size", size, "max_size", p.maxSize)
+		p.Telemetry().Dropped(ctx)
+		e.Ack()
+		e.Release()
+		return nil
+	}
+	return p.Write(ctx, e)
+}
+
+// Transform will truncate an entry that is larger than the maximum size, so that the entry fits within
+// the maximum size. Its body is truncated first, then its attributes and then its resource. Strings and
+// byte slices are shortened, and the keys of maps and elements of slices that do not fit are removed, in
+// order. It returns an error if the entry is larger than the maximum size without these fields.
+func (p *SizeLimitOperator) Transform(e *entry.Entry) error {
+	excess := e.Size() - p.maxSize
+	if excess <= 0 {
+		return nil
+	}
+
+	e.Unshare()
+	e.Body, excess = shrink(e.Body, excess)
+	if excess > 0 && e.Attributes != nil {
+		var attributes interface{}
+		attributes, excess = shrink(e.Attributes, excess)
+		e.Attributes = attributes.(map[string]interface{})
+	}
+	if excess > 0 && e.Resource != nil {
+		var resource interface{}
+		resource, excess = shrink(e.Resource, excess)
+		e.Resource = resource.(map[string]interface{})
+	}
+
+	if excess > 0 {
+		return errors.NewError(
+			"entry exceeds the maximum size without its body, attributes and resource",
+			"ensure that the severity text, scope name and trace fields of the entry are smaller than `max_size`",
+			"size", fmt.Sprint(p.maxSize+excess),
+			"max_size", fmt.Sprint(p.maxSize),
+		)
+	}
+	return nil
+}
+
+// shrink truncates a value by up to excess bytes, and returns the number of bytes still in excess
+func shrink(value interface{}, excess int) (interface{}, int) {
+	size := entry.SizeOf(value)
+	limit := size - excess
+	if limit < 0 {
+		limit = 0
+	}
+	truncated, truncatedSize := truncate(value, limit)
+	return truncated, excess - (size - truncatedSize)
+}
+
+// truncate returns a value that is no larger than the limit, and its size.
+// Values that can not be shortened, such as numbers, are nil if they do not fit.
+func truncate(value interface{}, limit int) (interface{}, int) {
+	size := entry.SizeOf(value)
+	if size <= limit {
+		return value, size
+	}
+
+	switch v := value.(type) {
+	case string:
+		s := truncateString(v, limit)
+		return s, len(s)
+	case []byte:
+		return v[:limit:limit], limit
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		truncated := make(map[string]interface{})
+		size := 0
+		for _, k := range keys {
+			if size+len(k) > limit {
+				break
+			}
+			child, childSize := truncate(v[k], limit-size-len(k))
+			if child == nil && v[k] != nil {
+				break
+			}
+			truncated[k] = child
+			size += len(k) + childSize
+		}
+		return truncated, size
+	case map[string]string:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		truncated := make(map[string]string)
+		size := 0
+		for _, k := range keys {
+			if size+len(k) > limit {
+				break
+			}
+			child := truncateString(v[k], limit-size-len(k))
+			truncated[k] = child
+			size += len(k) + len(child)
+		}
+		return truncated, size
+	case []interface{}:
+		truncated := make([]interface{}, 0, len(v))
+		size := 0
+		for _, element := range v {
+			child, childSize := truncate(element, limit-size)
+			if child == nil && element != nil {
+				break
+			}
+			truncated = append(truncated, child)
+			size += childSize
+		}
+		return truncated, size
+	case []string:
+		truncated := make([]string, 0, len(v))
+		size := 0
+		for _, element := range v {
+			child := truncateString(element, limit-size)
+			truncated = append(truncated, child)
+			size += len(child)
+			if len(child) < len(element) {
+				break
+			}
+		}
+		return truncated, size
+	default:
+		return nil, 0
+	}
+}
+
+// truncateString shortens a string to at most limit bytes, without splitting a multi-byte character
+func truncateString(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit]
+}
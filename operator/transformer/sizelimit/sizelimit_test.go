@@ -0,0 +1,269 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sizelimit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
+)
+
+func newTestOperator(t *testing.T, cfgMod func(*SizeLimitOperatorConfig)) (operator.Operator, *testutil.FakeOutput) {
+	cfg := NewSizeLimitOperatorConfig("test")
+	cfg.MaxSize = 10
+	cfg.OutputIDs = []string{"fake"}
+	if cfgMod != nil {
+		cfgMod(cfg)
+	}
+
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	fake := testutil.NewFakeOutput(t)
+	require.NoError(t, op.SetOutputs([]operator.Operator{fake}))
+	return op, fake
+}
+
+func TestBuildInvalid(t *testing.T) {
+	cases := []struct {
+		name     string
+		modify   func(*SizeLimitOperatorConfig)
+		expected string
+	}{
+		{
+			"MissingMaxSize",
+			func(cfg *SizeLimitOperatorConfig) {},
+			"invalid value '0' for parameter 'max_size'",
+		},
+		{
+			"InvalidAction",
+			func(cfg *SizeLimitOperatorConfig) {
+				cfg.MaxSize = 10
+				cfg.Action = "split"
+			},
+			"invalid action 'split'",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewSizeLimitOperatorConfig("test")
+			tc.modify(cfg)
+			_, err := cfg.Build(testutil.Logger(t))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		name       string
+		body       interface{}
+		attributes map[string]interface{}
+		expected   interface{}
+	}{
+		{
+			"Small",
+			"0123456789",
+			nil,
+			"0123456789",
+		},
+		{
+			"String",
+			"0123456789abc",
+			nil,
+			"0123456789",
+		},
+		{
+			"StringWithAttributes",
+			"0123456789",
+			map[string]interface{}{"key": "val"},
+			"0123",
+		},
+		{
+			"MultiByteCharacter",
+			"012345678é",
+			nil,
+			"012345678",
+		},
+		{
+			"Bytes",
+			[]byte("0123456789abc"),
+			nil,
+			[]byte("0123456789"),
+		},
+		{
+			"Map",
+			map[string]interface{}{"a": "1234", "b": "12345", "c": "1234"},
+			nil,
+			map[string]interface{}{"a": "1234", "b": "1234"},
+		},
+		{
+			"MapWithNumber",
+			map[string]interface{}{"a": "12345", "b": 1},
+			nil,
+			map[string]interface{}{"a": "12345"},
+		},
+		{
+			"NestedMap",
+			map[string]interface{}{"a": map[string]interface{}{"b": "12345678910"}},
+			nil,
+			map[string]interface{}{"a": map[string]interface{}{"b": "12345678"}},
+		},
+		{
+			"StringMap",
+			map[string]string{"a": "1234", "b": "12345", "c": "1234"},
+			nil,
+			map[string]string{"a": "1234", "b": "1234"},
+		},
+		{
+			"Slice",
+			[]interface{}{"1234", "1234", "1234"},
+			nil,
+			[]interface{}{"1234", "1234", "12"},
+		},
+		{
+			"SliceWithNumber",
+			[]interface{}{"1", 2, 3},
+			nil,
+			[]interface{}{"1", 2},
+		},
+		{
+			"Strings",
+			[]string{"1234", "1234", "1234"},
+			nil,
+			[]string{"1234", "1234", "12"},
+		},
+		{
+			"Number",
+			[]int{1, 2},
+			nil,
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			op, fake := newTestOperator(t, nil)
+
+			e := entry.New()
+			e.Body = tc.body
+			e.Attributes = tc.attributes
+			require.NoError(t, op.Process(context.Background(), e))
+
+			received := <-fake.Received
+			require.Equal(t, tc.expected, received.Body)
+			require.LessOrEqual(t, received.Size(), 10)
+		})
+	}
+}
+
+func TestTruncateAttributes(t *testing.T) {
+	op, fake := newTestOperator(t, nil)
+
+	e := entry.New()
+	e.Body = "body"
+	e.AddAttribute("key", "a long attribute value")
+	e.AddResourceKey("host", "abc")
+	require.NoError(t, op.Process(context.Background(), e))
+
+	received := <-fake.Received
+	require.Equal(t, "", received.Body)
+	require.Equal(t, map[string]interface{}{"key": ""}, received.Attributes)
+	require.Equal(t, map[string]interface{}{"host": "abc"}, received.Resource)
+	require.LessOrEqual(t, received.Size(), 10)
+}
+
+func TestTruncateResource(t *testing.T) {
+	op, fake := newTestOperator(t, nil)
+
+	e := entry.New()
+	e.AddAttribute("a", "1")
+	e.AddResourceKey("host", "a long host name")
+	require.NoError(t, op.Process(context.Background(), e))
+
+	received := <-fake.Received
+	require.Equal(t, map[string]interface{}{}, received.Attributes)
+	require.Equal(t, map[string]interface{}{"host": "a long"}, received.Resource)
+	require.LessOrEqual(t, received.Size(), 10)
+}
+
+func TestTruncateTooLarge(t *testing.T) {
+	op, fake := newTestOperator(t, func(cfg *SizeLimitOperatorConfig) {
+		cfg.OnError = helper.DropOnError
+	})
+
+	acked := false
+	e := entry.New()
+	e.Body = "body"
+	e.SeverityText = "a long severity text"
+	e.OnAck(func() { acked = true })
+	err := op.Process(context.Background(), e)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "entry exceeds the maximum size without its body, attributes and resource")
+	require.True(t, acked)
+	fake.ExpectNoEntry(t, 0)
+}
+
+func TestTruncateSharedEntry(t *testing.T) {
+	op, fake := newTestOperator(t, nil)
+
+	original := entry.New()
+	original.Body = map[string]interface{}{"a": "1234", "b": "1234", "c": "1234"}
+	require.NoError(t, op.Process(context.Background(), original.Share()))
+
+	received := <-fake.Received
+	require.Equal(t, map[string]interface{}{"a": "1234", "b": "1234"}, received.Body)
+	require.Equal(t, map[string]interface{}{"a": "1234", "b": "1234", "c": "1234"}, original.Body)
+}
+
+func TestDrop(t *testing.T) {
+	op, fake := newTestOperator(t, func(cfg *SizeLimitOperatorConfig) {
+		cfg.Action = dropAction
+	})
+
+	small := entry.New()
+	small.Body = "0123456789"
+	require.NoError(t, op.Process(context.Background(), small))
+	fake.ExpectBody(t, "0123456789")
+
+	acked := false
+	large := entry.New()
+	large.Body = "0123456789a"
+	large.OnAck(func() { acked = true })
+	require.NoError(t, op.Process(context.Background(), large))
+	require.True(t, acked)
+	fake.ExpectNoEntry(t, 0)
+}
+
+func TestDropIf(t *testing.T) {
+	op, fake := newTestOperator(t, func(cfg *SizeLimitOperatorConfig) {
+		cfg.Action = dropAction
+		cfg.IfExpr = `attributes.keep != "true"`
+	})
+
+	e := entry.New()
+	e.Body = "0123456789abcdef"
+	e.AddAttribute("keep", "true")
+	require.NoError(t, op.Process(context.Background(), e))
+	fake.ExpectBody(t, "0123456789abcdef")
+}
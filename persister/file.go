@@ -0,0 +1,639 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package persister provides durable implementations of operator.Persister.
+package persister
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
+)
+
+const (
+	// SyncAlways syncs every write to disk before it returns.
+	SyncAlways = "always"
+	// SyncCompaction only syncs the snapshots written when a file is compacted.
+	SyncCompaction = "compaction"
+	// SyncNever leaves syncing to the operating system.
+	SyncNever = "never"
+
+	defaultCompactionThreshold = 64 * 1024
+
+	fileExtension = ".db"
+	tempExtension = ".tmp"
+	fileMagic     = "OTLCDB"
+	fileVersion   = 1
+
+	opSet    byte = 1
+	opDelete byte = 2
+
+	// recordHeaderSize is the size of the checksum and length that precede each record
+	recordHeaderSize = 8
+)
+
+var fileHeader = append([]byte(fileMagic), fileVersion)
+
+// NewFileConfig creates a new file persister config with default values
+func NewFileConfig(directory string) FileConfig {
+	return FileConfig{
+		Directory:           directory,
+		Sync:                SyncAlways,
+		CompactionThreshold: defaultCompactionThreshold,
+	}
+}
+
+// FileConfig is the configuration of a file persister
+type FileConfig struct {
+	Directory           string          `mapstructure:"directory"            json:"directory"            yaml:"directory"`
+	Sync                string          `mapstructure:"sync"                 json:"sync"                 yaml:"sync"                 jsonschema:"enum=always|compaction|never"`
+	CompactionThreshold helper.ByteSize `mapstructure:"compaction_threshold" json:"compaction_threshold" yaml:"compaction_threshold"`
+}
+
+// Build will build a file persister, loading the state stored in its directory.
+func (c FileConfig) Build(logger *zap.SugaredLogger) (*FilePersister, error) {
+	if c.Directory == "" {
+		return nil, errors.NewError(
+			"missing required `directory` field",
+			"ensure that the directory of the persister is set",
+		)
+	}
+
+	switch c.Sync {
+	case SyncAlways, SyncCompaction, SyncNever:
+	default:
+		return nil, errors.NewError(
+			fmt.Sprintf("invalid `sync` value '%s'", c.Sync),
+			"ensure that `sync` is one of 'always', 'compaction' or 'never'",
+		)
+	}
+
+	if c.CompactionThreshold < 0 {
+		return nil, errors.NewError(
+			"`compaction_threshold` must not be negative",
+			"ensure that `compaction_threshold` is zero or a positive size",
+		)
+	}
+
+	if err := os.MkdirAll(c.Directory, 0750); err != nil {
+		return nil, errors.Wrap(err, "create persister directory")
+	}
+
+	p := &FilePersister{
+		SugaredLogger:       logger.With("directory", c.Directory),
+		directory:           c.Directory,
+		sync:                c.Sync,
+		compactionThreshold: int64(c.CompactionThreshold),
+		files:               make(map[string]*dataFile),
+	}
+	if err := p.load(); err != nil {
+		p.closeFiles()
+		return nil, err
+	}
+	return p, nil
+}
+
+// FilePersister is a persister that stores state in a directory. The keys of each
+// scope created by operator.NewScopedPersister are stored in a separate file, as a log
+// of checksummed writes. The log of a scope is compacted into a snapshot, which is
+// written to a temporary file and renamed over the log, once most of it is obsolete.
+type FilePersister struct {
+	*zap.SugaredLogger
+	directory           string
+	sync                string
+	compactionThreshold int64
+
+	mux    sync.Mutex
+	files  map[string]*dataFile
+	closed bool
+}
+
+var (
+	_ operator.Persister = (*FilePersister)(nil)
+	_ operator.KeyLister = (*FilePersister)(nil)
+)
+
+// dataFile is the log of the keys of a scope
+type dataFile struct {
+	path string
+	file *os.File
+	data map[string][]byte
+
+	// size is the size of the log, and live is the size of a snapshot of its data
+	size int64
+	live int64
+}
+
+// Get will return the value of a key, or nil if it is not set.
+func (p *FilePersister) Get(_ context.Context, key string) ([]byte, error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if p.closed {
+		return nil, errClosed
+	}
+
+	f, ok := p.files[fileName(key)]
+	if !ok {
+		return nil, nil
+	}
+	value, ok := f.data[key]
+	if !ok {
+		return nil, nil
+	}
+	return append([]byte{}, value...), nil
+}
+
+// Set will set the value of a key.
+func (p *FilePersister) Set(_ context.Context, key string, value []byte) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if p.closed {
+		return errClosed
+	}
+
+	f, err := p.fileFor(key)
+	if err != nil {
+		return err
+	}
+	if err := p.append(f, encodeRecord(opSet, key, value)); err != nil {
+		return err
+	}
+
+	if old, ok := f.data[key]; ok {
+		f.live -= recordSize(key, old)
+	}
+	f.data[key] = append([]byte{}, value...)
+	f.live += recordSize(key, value)
+	return p.compactIfNeeded(f)
+}
+
+// Delete will delete a key. Deleting a key that is not set has no effect.
+func (p *FilePersister) Delete(_ context.Context, key string) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if p.closed {
+		return errClosed
+	}
+
+	f, ok := p.files[fileName(key)]
+	if !ok {
+		return nil
+	}
+	old, ok := f.data[key]
+	if !ok {
+		return nil
+	}
+	if err := p.append(f, encodeRecord(opDelete, key, nil)); err != nil {
+		return err
+	}
+
+	delete(f.data, key)
+	f.live -= recordSize(key, old)
+	return p.compactIfNeeded(f)
+}
+
+// Keys returns the keys that begin with a prefix, in sorted order.
+func (p *FilePersister) Keys(_ context.Context, prefix string) ([]string, error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if p.closed {
+		return nil, errClosed
+	}
+
+	keys := make([]string, 0)
+	for _, f := range p.files {
+		for key := range f.data {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Compact will replace the log of every scope with a snapshot of its keys.
+func (p *FilePersister) Compact() error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if p.closed {
+		return errClosed
+	}
+
+	for _, name := range p.fileNames() {
+		if err := p.compact(p.files[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close will close the files of the persister. The persister can not be used once it is closed.
+func (p *FilePersister) Close() error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	return p.closeFiles()
+}
+
+var errClosed = errors.NewError(
+	"persister is closed",
+	"ensure that the persister is not used after it is closed",
+)
+
+func (p *FilePersister) closeFiles() error {
+	var errs []string
+	for _, name := range p.fileNames() {
+		f := p.files[name]
+		if f.file == nil {
+			continue
+		}
+		if p.sync != SyncNever {
+			if err := f.file.Sync(); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if err := f.file.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+		f.file = nil
+	}
+	if len(errs) > 0 {
+		return errors.NewError("failed to close persister files", "", "errors", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// fileNames returns the names of the files of the persister in sorted order
+func (p *FilePersister) fileNames() []string {
+	names := make([]string, 0, len(p.files))
+	for name := range p.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fileFor returns the file of the scope of a key, creating it if necessary
+func (p *FilePersister) fileFor(key string) (*dataFile, error) {
+	name := fileName(key)
+	if f, ok := p.files[name]; ok {
+		return f, nil
+	}
+
+	f := &dataFile{
+		path: filepath.Join(p.directory, name),
+		data: make(map[string][]byte),
+		live: int64(len(fileHeader)),
+	}
+	if err := p.writeSnapshot(f); err != nil {
+		return nil, err
+	}
+	p.files[name] = f
+	return f, nil
+}
+
+// append writes a record to the end of a file. If the write fails, the file
+// is truncated so that later records are not written after a partial record.
+func (p *FilePersister) append(f *dataFile, record []byte) error {
+	if _, err := f.file.Write(record); err != nil {
+		if truncErr := f.file.Truncate(f.size); truncErr != nil {
+			p.Errorw("Failed to truncate partially written record", zap.String("path", f.path), zap.Error(truncErr))
+		}
+		return errors.Wrap(err, "write persister record")
+	}
+	f.size += int64(len(record))
+
+	if p.sync == SyncAlways {
+		if err := f.file.Sync(); err != nil {
+			return errors.Wrap(err, "sync persister file")
+		}
+	}
+	return nil
+}
+
+// compactIfNeeded compacts a file once it is larger than the compaction
+// threshold, and more than half of it is taken by obsolete records.
+func (p *FilePersister) compactIfNeeded(f *dataFile) error {
+	if f.size <= p.compactionThreshold || f.size <= 2*f.live {
+		return nil
+	}
+	return p.compact(f)
+}
+
+// compact replaces the log of a file with a snapshot of its data
+func (p *FilePersister) compact(f *dataFile) error {
+	if f.file != nil {
+		if err := f.file.Close(); err != nil {
+			return errors.Wrap(err, "close persister file")
+		}
+		f.file = nil
+	}
+	return p.writeSnapshot(f)
+}
+
+// writeSnapshot atomically replaces a file with a snapshot of its data, and opens it for appending.
+func (p *FilePersister) writeSnapshot(f *dataFile) error {
+	keys := make([]string, 0, len(f.data))
+	for key := range f.data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(fileHeader)
+	for _, key := range keys {
+		buf.Write(encodeRecord(opSet, key, f.data[key]))
+	}
+
+	tempPath := f.path + tempExtension
+	if err := p.writeFile(tempPath, buf.Bytes()); err != nil {
+		return err
+	}
+	if err := os.Rename(tempPath, f.path); err != nil {
+		return errors.Wrap(err, "replace persister file")
+	}
+	if err := p.syncDirectory(); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Wrap(err, "open persister file")
+	}
+	f.file = file
+	f.size = int64(buf.Len())
+	f.live = f.size
+	return nil
+}
+
+func (p *FilePersister) writeFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "create persister snapshot")
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return errors.Wrap(err, "write persister snapshot")
+	}
+	if p.sync != SyncNever {
+		if err := file.Sync(); err != nil {
+			_ = file.Close()
+			return errors.Wrap(err, "sync persister snapshot")
+		}
+	}
+	if err := file.Close(); err != nil {
+		return errors.Wrap(err, "close persister snapshot")
+	}
+	return nil
+}
+
+// syncDirectory makes renamed files durable. Directories can not be synced on Windows.
+func (p *FilePersister) syncDirectory() error {
+	if p.sync == SyncNever || runtime.GOOS == "windows" {
+		return nil
+	}
+
+	dir, err := os.Open(p.directory)
+	if err != nil {
+		return errors.Wrap(err, "open persister directory")
+	}
+	defer dir.Close()
+
+	if err := dir.Sync(); err != nil {
+		return errors.Wrap(err, "sync persister directory")
+	}
+	return nil
+}
+
+// load reads the files of the persister directory, removing incomplete snapshots
+func (p *FilePersister) load() error {
+	entries, err := ioutil.ReadDir(p.directory)
+	if err != nil {
+		return errors.Wrap(err, "read persister directory")
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(p.directory, entry.Name())
+		switch {
+		case entry.IsDir():
+		case strings.HasSuffix(entry.Name(), tempExtension):
+			if err := os.Remove(path); err != nil {
+				return errors.Wrap(err, "remove incomplete persister snapshot")
+			}
+		case strings.HasSuffix(entry.Name(), fileExtension):
+			f, err := p.loadFile(path)
+			if err != nil {
+				return err
+			}
+			p.files[entry.Name()] = f
+		}
+	}
+	return nil
+}
+
+// loadFile reads the log of a file. A corrupt last record, such as one that was partially written
+// when the process was stopped, is discarded, and the file is compacted so that new records are
+// not written after it. A corrupt record that is followed by other records is reported as an
+// error, and the file is left as it is, since the records that follow it may hold newer state.
+func (p *FilePersister) loadFile(path string) (*dataFile, error) {
+	contents, err := ioutil.ReadFile(path) // #nosec - the path is in the persister directory
+	if err != nil {
+		return nil, errors.Wrap(err, "read persister file")
+	}
+
+	if !bytes.HasPrefix(contents, []byte(fileMagic)) || len(contents) < len(fileHeader) {
+		return nil, errors.NewError(
+			"persister file is not a valid data file",
+			"remove the file, or ensure that the persister directory is only used by the persister",
+			"path", path,
+		)
+	}
+	if version := contents[len(fileMagic)]; version != fileVersion {
+		return nil, errors.NewError(
+			fmt.Sprintf("persister file has unsupported version %d", version),
+			"ensure that the file was written by a compatible version",
+			"path", path,
+		)
+	}
+
+	f := &dataFile{
+		path: path,
+		data: make(map[string][]byte),
+		live: int64(len(fileHeader)),
+	}
+
+	offset := len(fileHeader)
+	for offset < len(contents) {
+		op, key, value, n, err := decodeRecord(contents[offset:])
+		if err != nil && !isLastRecord(contents[offset:]) {
+			return nil, errors.NewError(
+				fmt.Sprintf("persister file has a corrupt record before its end: %s", err),
+				"restore the file from a backup, or remove it to reset the state of the operators stored in it",
+				"path", path,
+				"offset", strconv.Itoa(offset),
+			)
+		}
+		if err != nil {
+			p.Warnw("Discarding corrupt persisted state",
+				zap.String("path", path),
+				zap.Int("offset", offset),
+				zap.Int("discarded_bytes", len(contents)-offset),
+				zap.Error(err),
+			)
+			break
+		}
+		offset += n
+
+		if old, ok := f.data[key]; ok {
+			f.live -= recordSize(key, old)
+		}
+		switch op {
+		case opSet:
+			f.data[key] = value
+			f.live += recordSize(key, value)
+		case opDelete:
+			delete(f.data, key)
+		}
+	}
+
+	if offset < len(contents) {
+		if err := p.writeSnapshot(f); err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "open persister file")
+	}
+	f.file = file
+	f.size = int64(len(contents))
+	return f, nil
+}
+
+// fileName returns the name of the file that stores a key. Keys are grouped by
+// the scope of operator.NewScopedPersister, and keys without a scope are stored
+// together. Upper case letters are escaped along with other characters, so that
+// the files of different scopes do not collide on case-insensitive filesystems.
+func fileName(key string) string {
+	scope, _, _ := operator.SplitScopedKey(key)
+	if scope == "" {
+		scope = "_"
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(scope); i++ {
+		c := scope[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	b.WriteString(fileExtension)
+	return b.String()
+}
+
+// encodeRecord encodes a write as a checksum and length, followed by the
+// operation, the length of the key, the key and the value.
+func encodeRecord(op byte, key string, value []byte) []byte {
+	payload := make([]byte, 1+binary.MaxVarintLen64, 1+binary.MaxVarintLen64+len(key)+len(value))
+	payload[0] = op
+	n := binary.PutUvarint(payload[1:], uint64(len(key)))
+	payload = append(payload[:1+n], key...)
+	payload = append(payload, value...)
+
+	record := make([]byte, recordHeaderSize, recordHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(record[0:4], crc32.ChecksumIEEE(payload))
+	binary.LittleEndian.PutUint32(record[4:8], uint32(len(payload)))
+	return append(record, payload...)
+}
+
+// decodeRecord decodes the record at the start of data, returning its size
+func decodeRecord(data []byte) (op byte, key string, value []byte, size int, err error) {
+	if len(data) < recordHeaderSize {
+		return 0, "", nil, 0, fmt.Errorf("incomplete record header")
+	}
+	checksum := binary.LittleEndian.Uint32(data[0:4])
+	length := int(binary.LittleEndian.Uint32(data[4:8]))
+	if length > len(data)-recordHeaderSize {
+		return 0, "", nil, 0, fmt.Errorf("incomplete record")
+	}
+
+	payload := data[recordHeaderSize : recordHeaderSize+length]
+	if crc32.ChecksumIEEE(payload) != checksum {
+		return 0, "", nil, 0, fmt.Errorf("checksum mismatch")
+	}
+	if len(payload) < 2 {
+		return 0, "", nil, 0, fmt.Errorf("record is too short")
+	}
+
+	op = payload[0]
+	if op != opSet && op != opDelete {
+		return 0, "", nil, 0, fmt.Errorf("unknown operation %d", op)
+	}
+	keyLength, n := binary.Uvarint(payload[1:])
+	if n <= 0 || keyLength > uint64(len(payload)-1-n) {
+		return 0, "", nil, 0, fmt.Errorf("invalid key length")
+	}
+
+	key = string(payload[1+n : 1+n+int(keyLength)])
+	value = append([]byte{}, payload[1+n+int(keyLength):]...)
+	return op, key, value, recordHeaderSize + length, nil
+}
+
+// isLastRecord returns true if the record at the start of data extends to the end of the data,
+// as a record that was partially written when the process was stopped does
+func isLastRecord(data []byte) bool {
+	if len(data) < recordHeaderSize {
+		return true
+	}
+	length := int64(binary.LittleEndian.Uint32(data[4:8]))
+	return length >= int64(len(data)-recordHeaderSize)
+}
+
+// recordSize returns the size of the record that sets a key to a value
+func recordSize(key string, value []byte) int64 {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(len(key)))
+	return int64(recordHeaderSize + 1 + n + len(key) + len(value))
+}
@@ -0,0 +1,371 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persister
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
+)
+
+func newTestPersister(t *testing.T, cfg FileConfig) *FilePersister {
+	p, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, p.Close()) })
+	return p
+}
+
+func TestFilePersisterSetGetDelete(t *testing.T) {
+	p := newTestPersister(t, NewFileConfig(t.TempDir()))
+	ctx := context.Background()
+
+	value, err := p.Get(ctx, "missing")
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	require.NoError(t, p.Set(ctx, "key", []byte("value")))
+	value, err = p.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), value)
+
+	require.NoError(t, p.Delete(ctx, "key"))
+	value, err = p.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	require.NoError(t, p.Delete(ctx, "missing"))
+}
+
+func TestFilePersisterReopen(t *testing.T) {
+	cfg := NewFileConfig(t.TempDir())
+	ctx := context.Background()
+
+	p, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.NoError(t, p.Set(ctx, "a", []byte("1")))
+	require.NoError(t, p.Set(ctx, "b", []byte("2")))
+	require.NoError(t, p.Set(ctx, "a", []byte("3")))
+	require.NoError(t, p.Delete(ctx, "b"))
+	require.NoError(t, p.Set(ctx, "c", []byte{}))
+	require.NoError(t, p.Close())
+
+	p = newTestPersister(t, cfg)
+	value, err := p.Get(ctx, "a")
+	require.NoError(t, err)
+	require.Equal(t, []byte("3"), value)
+
+	value, err = p.Get(ctx, "b")
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	value, err = p.Get(ctx, "c")
+	require.NoError(t, err)
+	require.Equal(t, []byte{}, value)
+}
+
+func TestFilePersisterScopes(t *testing.T) {
+	dir := t.TempDir()
+	p := newTestPersister(t, NewFileConfig(dir))
+	ctx := context.Background()
+
+	first := operator.NewScopedPersister("first", p)
+	second := operator.NewScopedPersister("$second/op", p)
+	require.NoError(t, first.Set(ctx, "key", []byte("1")))
+	require.NoError(t, second.Set(ctx, "key", []byte("2")))
+	require.NoError(t, p.Set(ctx, "key", []byte("3")))
+
+	value, err := first.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), value)
+
+	value, err = second.Get(ctx, "key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("2"), value)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		filepath.Join(dir, "first.db"),
+		filepath.Join(dir, "%24second%2Fop.db"),
+		filepath.Join(dir, "_.db"),
+	}, files)
+}
+
+func TestFilePersisterDottedAndCasedScopes(t *testing.T) {
+	dir := t.TempDir()
+	p := newTestPersister(t, NewFileConfig(dir))
+	ctx := context.Background()
+
+	scopes := []string{"tpl.first", "tpl.second", "Op", "op"}
+	for _, scope := range scopes {
+		require.NoError(t, operator.NewScopedPersister(scope, p).Set(ctx, "key", []byte(scope)))
+	}
+	for _, scope := range scopes {
+		value, err := operator.NewScopedPersister(scope, p).Get(ctx, "key")
+		require.NoError(t, err)
+		require.Equal(t, []byte(scope), value)
+	}
+
+	// Each scope has its own file, even on case-insensitive filesystems
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		filepath.Join(dir, "tpl%2Efirst.db"),
+		filepath.Join(dir, "tpl%2Esecond.db"),
+		filepath.Join(dir, "%4Fp.db"),
+		filepath.Join(dir, "op.db"),
+	}, files)
+}
+
+func TestFilePersisterCompaction(t *testing.T) {
+	cfg := NewFileConfig(t.TempDir())
+	cfg.CompactionThreshold = 1024
+	p := newTestPersister(t, cfg)
+	ctx := context.Background()
+
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, p.Set(ctx, "scope.key", []byte("a value that is overwritten")))
+	}
+
+	info, err := os.Stat(filepath.Join(cfg.Directory, "scope.db"))
+	require.NoError(t, err)
+	require.LessOrEqual(t, info.Size(), int64(2*cfg.CompactionThreshold))
+
+	require.NoError(t, p.Compact())
+	info, err = os.Stat(filepath.Join(cfg.Directory, "scope.db"))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(fileHeader))+recordSize("scope.key", []byte("a value that is overwritten")), info.Size())
+
+	value, err := p.Get(ctx, "scope.key")
+	require.NoError(t, err)
+	require.Equal(t, []byte("a value that is overwritten"), value)
+}
+
+func TestFilePersisterCorruptTail(t *testing.T) {
+	cfg := NewFileConfig(t.TempDir())
+	ctx := context.Background()
+
+	p, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.NoError(t, p.Set(ctx, "scope.a", []byte("1")))
+	require.NoError(t, p.Set(ctx, "scope.b", []byte("2")))
+	require.NoError(t, p.Close())
+
+	// Simulate a partially written record by cutting off the end of the file
+	path := filepath.Join(cfg.Directory, "scope.db")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(path, info.Size()-1))
+
+	p = newTestPersister(t, cfg)
+	value, err := p.Get(ctx, "scope.a")
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), value)
+
+	value, err = p.Get(ctx, "scope.b")
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	// New writes must be readable after the corrupt record is discarded
+	require.NoError(t, p.Set(ctx, "scope.c", []byte("3")))
+	require.NoError(t, p.Close())
+
+	p = newTestPersister(t, cfg)
+	value, err = p.Get(ctx, "scope.c")
+	require.NoError(t, err)
+	require.Equal(t, []byte("3"), value)
+}
+
+func TestFilePersisterChecksumMismatch(t *testing.T) {
+	cfg := NewFileConfig(t.TempDir())
+	ctx := context.Background()
+
+	p, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.NoError(t, p.Set(ctx, "scope.a", []byte("1")))
+	require.NoError(t, p.Close())
+
+	path := filepath.Join(cfg.Directory, "scope.db")
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	contents[len(contents)-1] ^= 0xff
+	require.NoError(t, ioutil.WriteFile(path, contents, 0600))
+
+	p = newTestPersister(t, cfg)
+	value, err := p.Get(ctx, "scope.a")
+	require.NoError(t, err)
+	require.Nil(t, value)
+}
+
+func TestFilePersisterCorruptRecordBeforeEnd(t *testing.T) {
+	cfg := NewFileConfig(t.TempDir())
+	ctx := context.Background()
+
+	p, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.NoError(t, p.Set(ctx, "scope.a", []byte("1")))
+	require.NoError(t, p.Set(ctx, "scope.b", []byte("2")))
+	require.NoError(t, p.Close())
+
+	// Corrupt the value of the first record, which is followed by a valid record
+	path := filepath.Join(cfg.Directory, "scope.db")
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	contents[len(fileHeader)+int(recordSize("scope.a", []byte("1")))-1] ^= 0xff
+	require.NoError(t, ioutil.WriteFile(path, contents, 0600))
+
+	_, err = cfg.Build(testutil.Logger(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "corrupt record before its end")
+
+	// The file is left as it is, so that it can be recovered
+	unchanged, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, contents, unchanged)
+}
+
+func TestFilePersisterInvalidFile(t *testing.T) {
+	cases := []struct {
+		name     string
+		contents []byte
+		expected string
+	}{
+		{
+			"BadMagic",
+			[]byte("not a data file"),
+			"not a valid data file",
+		},
+		{
+			"UnsupportedVersion",
+			append([]byte(fileMagic), fileVersion+1),
+			"unsupported version",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "scope.db"), tc.contents, 0600))
+
+			_, err := NewFileConfig(dir).Build(testutil.Logger(t))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestFilePersisterRemovesIncompleteSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	tempPath := filepath.Join(dir, "scope.db"+tempExtension)
+	require.NoError(t, ioutil.WriteFile(tempPath, []byte("partial"), 0600))
+
+	newTestPersister(t, NewFileConfig(dir))
+	_, err := os.Stat(tempPath)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestFileConfigInvalid(t *testing.T) {
+	cases := []struct {
+		name     string
+		modify   func(*FileConfig)
+		expected string
+	}{
+		{
+			"MissingDirectory",
+			func(cfg *FileConfig) { cfg.Directory = "" },
+			"missing required `directory` field",
+		},
+		{
+			"InvalidSync",
+			func(cfg *FileConfig) { cfg.Sync = "sometimes" },
+			"invalid `sync` value",
+		},
+		{
+			"NegativeThreshold",
+			func(cfg *FileConfig) { cfg.CompactionThreshold = -1 },
+			"must not be negative",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewFileConfig(t.TempDir())
+			tc.modify(&cfg)
+			_, err := cfg.Build(testutil.Logger(t))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestFilePersisterClosed(t *testing.T) {
+	p, err := NewFileConfig(t.TempDir()).Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.NoError(t, p.Close())
+	require.NoError(t, p.Close())
+
+	ctx := context.Background()
+	_, err = p.Get(ctx, "key")
+	require.Error(t, err)
+	require.Error(t, p.Set(ctx, "key", nil))
+	require.Error(t, p.Delete(ctx, "key"))
+	require.Error(t, p.Compact())
+}
+
+func TestFilePersisterSyncModes(t *testing.T) {
+	for _, sync := range []string{SyncAlways, SyncCompaction, SyncNever} {
+		t.Run(sync, func(t *testing.T) {
+			cfg := NewFileConfig(t.TempDir())
+			cfg.Sync = sync
+			ctx := context.Background()
+
+			p, err := cfg.Build(testutil.Logger(t))
+			require.NoError(t, err)
+			require.NoError(t, p.Set(ctx, "scope.key", []byte("value")))
+			require.NoError(t, p.Compact())
+			require.NoError(t, p.Close())
+
+			p = newTestPersister(t, cfg)
+			value, err := p.Get(ctx, "scope.key")
+			require.NoError(t, err)
+			require.Equal(t, []byte("value"), value)
+		})
+	}
+}
+
+func TestFilePersisterKeys(t *testing.T) {
+	p := newTestPersister(t, NewFileConfig(t.TempDir()))
+	ctx := context.Background()
+
+	require.NoError(t, p.Set(ctx, "second.b", nil))
+	require.NoError(t, p.Set(ctx, "first.b", nil))
+	require.NoError(t, p.Set(ctx, "first.a", nil))
+	require.NoError(t, p.Set(ctx, "unscoped", nil))
+
+	keys, err := p.Keys(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"first.a", "first.b", "second.b", "unscoped"}, keys)
+
+	keys, err = p.Keys(ctx, "first.")
+	require.NoError(t, err)
+	require.Equal(t, []string{"first.a", "first.b"}, keys)
+}
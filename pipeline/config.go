@@ -21,18 +21,93 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/open-telemetry/opentelemetry-log-collection/clock"
 	"github.com/open-telemetry/opentelemetry-log-collection/errors"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/telemetry"
 )
 
 // Config is the configuration of a pipeline.
 type Config struct {
 	DefaultOutput operator.Operator
 	Operators     []operator.Config
+	Queues        []QueueConfig
+	Telemetry     telemetry.Provider
+	// Clock is used by operators to tell and wait for time. If nil, the system time is used.
+	Clock clock.Clock
+	// Registry restricts the operator types of the pipeline, and is used to unmarshal
+	// the operators of templates. If nil, operator.DefaultRegistry is used.
+	Registry *operator.Registry
+	// Templates are used to expand template operators. If nil, DefaultTemplateRegistry is used.
+	Templates *TemplateRegistry
+}
+
+// instrumented is implemented by operators that record telemetry.
+type instrumented interface {
+	SetTelemetry(telemetry.Provider)
+}
+
+// clocked is implemented by operators that tell or wait for time.
+type clocked interface {
+	SetClock(clock.Clock)
 }
 
 // Build will build a pipeline from the config.
 func (c Config) Build(logger *zap.SugaredLogger) (*DirectedPipeline, error) {
+	cfg, err := c.prepare()
+	if err != nil {
+		return nil, err
+	}
+
+	ops, err := cfg.buildOperators(logger)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DefaultOutput != nil {
+		cfg.configure(cfg.DefaultOutput)
+	}
+
+	pipeline, err := NewDirectedPipeline(ops)
+	if err != nil {
+		return nil, err
+	}
+	pipeline.specs = cfg.specs(ops)
+	return pipeline, nil
+}
+
+// prepare returns a copy of the config in which template operators are replaced by
+// the operators of their templates, after checking that its operator types are allowed.
+func (c Config) prepare() (Config, error) {
+	registry := c.Registry
+	if registry == nil {
+		registry = operator.DefaultRegistry
+	}
+
+	for _, opCfg := range c.Operators {
+		if !registry.IsAllowed(opCfg.Type()) {
+			return c, errors.NewError(
+				fmt.Sprintf("operator type '%s' is not allowed", opCfg.Type()),
+				"use one of the operator types that are allowed by the registry",
+				"operator_id", opCfg.ID(),
+			)
+		}
+	}
+
+	templates := c.Templates
+	if templates == nil {
+		templates = DefaultTemplateRegistry
+	}
+
+	ops, err := templates.expandTemplates(c.Operators, registry, 0)
+	if err != nil {
+		return c, err
+	}
+	c.Operators = ops
+	return c, nil
+}
+
+// buildOperators builds and connects the operators of the config.
+func (c Config) buildOperators(logger *zap.SugaredLogger) ([]operator.Operator, error) {
 	if logger == nil {
 		return nil, errors.NewError("logger must be provided", "")
 	}
@@ -58,6 +133,7 @@ func (c Config) Build(logger *zap.SugaredLogger) (*DirectedPipeline, error) {
 		if err != nil {
 			return nil, err
 		}
+		c.configure(op)
 		ops = append(ops, op)
 	}
 
@@ -80,7 +156,45 @@ func (c Config) Build(logger *zap.SugaredLogger) (*DirectedPipeline, error) {
 		}
 	}
 
-	return NewDirectedPipeline(ops)
+	return queueOperators(ops, c.Queues)
+}
+
+// configure sets the telemetry provider and clock of the config on an operator.
+// Operators must be configured before they are started.
+func (c Config) configure(op operator.Operator) {
+	if instrumentedOp, ok := op.(instrumented); ok && c.Telemetry != nil {
+		instrumentedOp.SetTelemetry(c.Telemetry)
+	}
+	if clockedOp, ok := op.(clocked); ok && c.Clock != nil {
+		clockedOp.SetClock(c.Clock)
+	}
+}
+
+// specs describes how each of the operators was built from the config.
+func (c Config) specs(ops []operator.Operator) map[string]operatorSpec {
+	builders := make(map[string]operator.Builder, len(c.Operators))
+	for _, opCfg := range c.Operators {
+		builders[opCfg.ID()] = opCfg.Builder
+	}
+
+	queues := make(map[string]QueueConfig, len(c.Queues))
+	for _, queue := range c.Queues {
+		queues[queue.OperatorID] = queue
+	}
+
+	specs := make(map[string]operatorSpec, len(ops))
+	for _, op := range ops {
+		spec := operatorSpec{
+			builder:   builders[op.ID()],
+			outputIDs: op.GetOutputIDs(),
+			queue:     queues[op.ID()],
+		}
+		if spec.builder == nil {
+			spec.instance = c.DefaultOutput
+		}
+		specs[op.ID()] = spec
+	}
+	return specs
 }
 
 func dedeplucateIDs(ops []operator.Config) {
@@ -15,15 +15,19 @@
 package pipeline
 
 import (
+	"context"
 	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator/parser/json"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/copy"
 	"github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/noop"
+	"github.com/open-telemetry/opentelemetry-log-collection/telemetry"
 	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
 )
 
@@ -114,6 +118,89 @@ func TestBuildAPipelineDefaultOperator(t *testing.T) {
 	require.True(t, exists["fake"])
 }
 
+func TestBuildPipelineDeadLetter(t *testing.T) {
+	parserCfg := json.NewJSONParserConfig("json_parser")
+	parserCfg.OnError = helper.DeadLetterOnError
+	parserCfg.DeadLetterOutput = []string{"fake"}
+
+	fakeOutput := testutil.NewFakeOutput(t)
+	cfg := Config{
+		Operators: []operator.Config{
+			{
+				Builder: parserCfg,
+			},
+			{
+				Builder: noop.NewNoopOperatorConfig("noop"),
+			},
+		},
+		DefaultOutput: fakeOutput,
+	}
+
+	pipe, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	dotGraph, err := pipe.Render()
+	require.NoError(t, err)
+	require.Contains(t, string(dotGraph), "json_parser -> fake;")
+	require.Contains(t, string(dotGraph), "json_parser -> noop;")
+
+	require.NoError(t, pipe.Start(testutil.NewUnscopedMockPersister()))
+	defer pipe.Stop(context.Background())
+
+	invalid := entry.New()
+	invalid.Body = "not json"
+	err = findOperator(t, pipe, "json_parser").Process(context.Background(), invalid)
+	require.Error(t, err)
+
+	e := <-fakeOutput.Received
+	require.Equal(t, "not json", e.Body)
+	require.Equal(t, "json_parser", e.Attributes["error.operator_id"])
+}
+
+func TestBuildPipelineTelemetry(t *testing.T) {
+	provider := telemetry.NewMemoryProvider()
+	cfg := Config{
+		Operators: []operator.Config{
+			{
+				Builder: json.NewJSONParserConfig("json_parser"),
+			},
+			{
+				Builder: noop.NewNoopOperatorConfig("noop"),
+			},
+		},
+		DefaultOutput: testutil.NewFakeOutput(t),
+		Queues:        []QueueConfig{NewQueueConfig("noop")},
+		Telemetry:     provider,
+	}
+
+	pipe, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.NoError(t, pipe.Start(testutil.NewUnscopedMockPersister()))
+
+	parser := findOperator(t, pipe, "json_parser")
+	for i := 0; i < 3; i++ {
+		e := entry.New()
+		e.Body = `{"key":"value"}`
+		require.NoError(t, parser.Process(context.Background(), e))
+	}
+	require.NoError(t, pipe.Stop(context.Background()))
+
+	parserAttrs := []telemetry.Attribute{
+		telemetry.String("operator_id", "json_parser"),
+		telemetry.String("operator_type", "json_parser"),
+	}
+	require.Equal(t, int64(3), provider.CounterValue(helper.EntriesReceivedMetric, parserAttrs...))
+	require.Equal(t, int64(3), provider.CounterValue(helper.EntriesEmittedMetric, parserAttrs...))
+	require.Len(t, provider.HistogramValues(helper.ProcessDurationMetric, parserAttrs...), 3)
+
+	noopAttrs := []telemetry.Attribute{
+		telemetry.String("operator_id", "noop"),
+		telemetry.String("operator_type", "noop"),
+	}
+	require.Equal(t, int64(3), provider.CounterValue(helper.EntriesReceivedMetric, noopAttrs...))
+	require.Equal(t, int64(3), provider.CounterValue(helper.EntriesEmittedMetric, noopAttrs...))
+}
+
 func TestDeduplicateIDs(t *testing.T) {
 	cases := []struct {
 		name        string
@@ -404,3 +491,25 @@ func newDummyJSON(dummyID string) operator.Config {
 func newDummyCopy(dummyID string) operator.Config {
 	return operator.Config{Builder: copy.NewCopyOperatorConfig(dummyID)}
 }
+
+func TestBuildPipelineRegistry(t *testing.T) {
+	registry := operator.DefaultRegistry.Clone()
+	registry.Deny("noop")
+
+	cfg := Config{
+		Operators: []operator.Config{
+			{
+				Builder: noop.NewNoopOperatorConfig("noop"),
+			},
+		},
+		Registry: registry,
+	}
+
+	_, err := cfg.Build(testutil.Logger(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "operator type 'noop' is not allowed")
+
+	cfg.Registry = nil
+	_, err = cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+}
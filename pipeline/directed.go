@@ -15,7 +15,9 @@
 package pipeline
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -40,6 +42,13 @@ type DirectedPipeline struct {
 	*zap.SugaredLogger
 	startOnce sync.Once
 	stopOnce  sync.Once
+
+	mux       sync.Mutex
+	persister operator.Persister
+	started   bool
+	stopped   bool
+	specs     map[string]operatorSpec
+	outputs   map[string]*outputRef
 }
 
 // Start will start the operators in a pipeline in reverse topological order
@@ -51,52 +60,129 @@ func (p *DirectedPipeline) Start(persister operator.Persister) error {
 	return err
 }
 
-// Stop will stop the operators in a pipeline in topological order
-func (p *DirectedPipeline) Stop() error {
+// Stop will stop the operators in a pipeline in topological order.
+// Inputs are stopped first, and operators that hold entries are drained to their outputs
+// before they are stopped. Operators that fail to drain before the context is done are
+// reported by a DrainError, and are then stopped regardless.
+func (p *DirectedPipeline) Stop(ctx context.Context) error {
 	var err error = alreadyStopped
 	p.stopOnce.Do(func() {
-		err = p.stop()
+		err = p.stop(ctx)
 	})
 	return err
 }
 
 func (p *DirectedPipeline) start(persister operator.Persister) error {
-	sortedNodes, _ := topo.Sort(p.Graph)
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.persister = persister
+	p.started = true
+	_, err := startNodes(p.Graph, persister, nil)
+	return err
+}
+
+// startNodes starts the operators of a graph in reverse topological order, and returns the operators
+// that it started. If skip is not nil, operators for which it returns true are not started.
+func startNodes(g *simple.DirectedGraph, persister operator.Persister, skip func(operator.Operator) bool) ([]operator.Operator, error) {
+	var started []operator.Operator
+	sortedNodes, _ := topo.Sort(g)
 	for i := len(sortedNodes) - 1; i >= 0; i-- {
 		op := sortedNodes[i].(OperatorNode).Operator()
+		if skip != nil && skip(op) {
+			continue
+		}
 
-		scopedPersister := operator.NewScopedPersister(op.ID(), persister)
+		scopedPersister := operator.NewVersionedPersister(op.Type(), operator.NewScopedPersister(op.ID(), persister), operator.DefaultStateMigrations, op.Logger())
 		op.Logger().Debug("Starting operator")
 		if err := op.Start(scopedPersister); err != nil {
-			return err
+			return started, err
 		}
+		started = append(started, op)
 		op.Logger().Debug("Started operator")
 	}
 
-	return nil
+	return started, nil
 }
 
-func (p *DirectedPipeline) stop() error {
+func (p *DirectedPipeline) stop(ctx context.Context) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.stopped = true
+	return stopNodes(ctx, p.Graph, nil)
+}
+
+// stopNodes drains and stops the operators of a graph in topological order.
+// If skip is not nil, operators for which it returns true are not stopped.
+func stopNodes(ctx context.Context, g *simple.DirectedGraph, skip func(operator.Operator) bool) error {
 	var err error
-	sortedNodes, _ := topo.Sort(p.Graph)
+	drainErr := &DrainError{Errors: make(map[string]error)}
+	sortedNodes, _ := topo.Sort(g)
 	for _, node := range sortedNodes {
-		operator := node.(OperatorNode).Operator()
-		operator.Logger().Debug("Stopping operator")
-		if opErr := operator.Stop(); opErr != nil {
+		op := node.(OperatorNode).Operator()
+		if skip != nil && skip(op) {
+			continue
+		}
+
+		if drainer, ok := op.(operator.Drainer); ok {
+			op.Logger().Debug("Draining operator")
+			if opErr := drainer.Drain(ctx); opErr != nil {
+				op.Logger().Errorw("Failed to drain operator", zap.Error(opErr))
+				drainErr.Errors[op.ID()] = opErr
+			}
+		}
+
+		op.Logger().Debug("Stopping operator")
+		if opErr := op.Stop(); opErr != nil {
 			err = multierr.Append(err, opErr)
 		}
-		operator.Logger().Debug("Stopped operator")
+		op.Logger().Debug("Stopped operator")
+	}
+
+	if len(drainErr.Errors) > 0 {
+		err = multierr.Append(drainErr, err)
 	}
 	return err
 }
 
+// DrainError reports the operators that failed to drain while being stopped.
+type DrainError struct {
+	// Errors maps the ID of each operator that failed to drain to its error.
+	Errors map[string]error
+}
+
+// Error returns a description of the operators that failed to drain.
+func (e *DrainError) Error() string {
+	ids := make([]string, 0, len(e.Errors))
+	for id := range e.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var description strings.Builder
+	description.WriteString("failed to drain operators: ")
+	for i, id := range ids {
+		if i != 0 {
+			description.WriteString(", ")
+		}
+		fmt.Fprintf(&description, "%s (%s)", id, e.Errors[id])
+	}
+	return description.String()
+}
+
 // Render will render the pipeline as a dot graph
 func (p *DirectedPipeline) Render() ([]byte, error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
 	return dot.Marshal(p.Graph, "G", "", " ")
 }
 
 // Operators returns a slice of operators that make up the pipeline graph
 func (p *DirectedPipeline) Operators() []operator.Operator {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
 	operators := make([]operator.Operator, 0)
 	nodes := p.Graph.Nodes()
 	for nodes.Next() {
@@ -180,26 +266,39 @@ func connectNode(graph *simple.DirectedGraph, inputNode OperatorNode) error {
 	return nil
 }
 
-// setOperatorOutputs will set the outputs on operators that can output.
-func setOperatorOutputs(operators []operator.Operator) error {
+// setOperatorOutputs will set the outputs on operators that can output. Operators are connected
+// through references to their outputs, which are returned by operator ID.
+func setOperatorOutputs(operators []operator.Operator) (map[string]*outputRef, error) {
+	outputs, refs := outputRefs(operators, nil)
 	for _, operator := range operators {
 		if !operator.CanOutput() {
 			continue
 		}
 
-		if err := operator.SetOutputs(operators); err != nil {
-			return errors.WithDetails(err, "operator_id", operator.ID())
+		if err := operator.SetOutputs(outputs); err != nil {
+			return nil, errors.WithDetails(err, "operator_id", operator.ID())
 		}
 	}
-	return nil
+	return refs, nil
 }
 
 // NewDirectedPipeline creates a new directed pipeline
 func NewDirectedPipeline(operators []operator.Operator) (*DirectedPipeline, error) {
-	if err := setOperatorOutputs(operators); err != nil {
+	refs, err := setOperatorOutputs(operators)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := newGraph(operators)
+	if err != nil {
 		return nil, err
 	}
 
+	return &DirectedPipeline{Graph: graph, outputs: refs}, nil
+}
+
+// newGraph creates a graph from operators whose outputs have already been set.
+func newGraph(operators []operator.Operator) (*simple.DirectedGraph, error) {
 	graph := simple.NewDirectedGraph()
 	if err := addNodes(graph, operators); err != nil {
 		return nil, err
@@ -209,7 +308,7 @@ func NewDirectedPipeline(operators []operator.Operator) (*DirectedPipeline, erro
 		return nil, err
 	}
 
-	return &DirectedPipeline{Graph: graph}, nil
+	return graph, nil
 }
 
 func unorderableToCycles(err topo.Unorderable) string {
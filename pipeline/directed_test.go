@@ -15,6 +15,7 @@
 package pipeline
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -90,7 +91,7 @@ func TestPipeline(t *testing.T) {
 		require.NoError(t, pipeline.Start(testutil.NewMockPersister("test1")))
 		require.Error(t, pipeline.Start(testutil.NewMockPersister("test2")))
 
-		require.NoError(t, pipeline.Stop())
+		require.NoError(t, pipeline.Stop(context.Background()))
 	})
 
 	t.Run("MultipleStop", func(t *testing.T) {
@@ -99,8 +100,8 @@ func TestPipeline(t *testing.T) {
 
 		require.NoError(t, pipeline.Start(testutil.NewMockPersister("test3")))
 
-		require.NoError(t, pipeline.Stop())
-		require.Error(t, pipeline.Stop())
+		require.NoError(t, pipeline.Stop(context.Background()))
+		require.Error(t, pipeline.Stop(context.Background()))
 	})
 
 	t.Run("DuplicateNodeIDs", func(t *testing.T) {
@@ -187,6 +188,11 @@ func TestPipeline(t *testing.T) {
 	})
 }
 
+// newOperatorPersister returns the persister that a pipeline starts a mock operator with
+func newOperatorPersister(op operator.Operator) operator.Persister {
+	return operator.NewVersionedPersister(op.Type(), testutil.NewMockPersister(op.ID()), operator.DefaultStateMigrations, op.Logger())
+}
+
 func TestPipelineStartOrder(t *testing.T) {
 	var mock2Started bool
 	var mock3Started bool
@@ -208,9 +214,9 @@ func TestPipelineStartOrder(t *testing.T) {
 	mockOperator2.On("Logger", mock.Anything).Return(zap.NewNop().Sugar())
 	mockOperator3.On("Logger", mock.Anything).Return(zap.NewNop().Sugar())
 
-	mockOperator1.On("Start", testutil.NewMockPersister(mockOperator1.ID())).Return(fmt.Errorf("operator 1 failed to start"))
-	mockOperator2.On("Start", testutil.NewMockPersister(mockOperator2.ID())).Run(func(mock.Arguments) { mock2Started = true }).Return(nil)
-	mockOperator3.On("Start", testutil.NewMockPersister(mockOperator3.ID())).Run(func(mock.Arguments) { mock3Started = true }).Return(nil)
+	mockOperator1.On("Start", newOperatorPersister(mockOperator1)).Return(fmt.Errorf("operator 1 failed to start"))
+	mockOperator2.On("Start", newOperatorPersister(mockOperator2)).Run(func(mock.Arguments) { mock2Started = true }).Return(nil)
+	mockOperator3.On("Start", newOperatorPersister(mockOperator3)).Run(func(mock.Arguments) { mock3Started = true }).Return(nil)
 
 	pipeline, err := NewDirectedPipeline([]operator.Operator{mockOperator1, mockOperator2, mockOperator3})
 	require.NoError(t, err)
@@ -242,9 +248,9 @@ func TestPipelineStopOrder(t *testing.T) {
 	mockOperator2.On("Logger", mock.Anything).Return(zap.NewNop().Sugar())
 	mockOperator3.On("Logger", mock.Anything).Return(zap.NewNop().Sugar())
 
-	mockOperator1.On("Start", testutil.NewMockPersister(mockOperator1.ID())).Return(nil)
-	mockOperator2.On("Start", testutil.NewMockPersister(mockOperator2.ID())).Return(nil)
-	mockOperator3.On("Start", testutil.NewMockPersister(mockOperator3.ID())).Return(nil)
+	mockOperator1.On("Start", newOperatorPersister(mockOperator1)).Return(nil)
+	mockOperator2.On("Start", newOperatorPersister(mockOperator2)).Return(nil)
+	mockOperator3.On("Start", newOperatorPersister(mockOperator3)).Return(nil)
 
 	mockOperator1.On("Stop").Run(func(mock.Arguments) { stopOrder = append(stopOrder, 1) }).Return(nil)
 	mockOperator2.On("Stop").Run(func(mock.Arguments) { stopOrder = append(stopOrder, 2) }).Return(nil)
@@ -256,11 +262,36 @@ func TestPipelineStopOrder(t *testing.T) {
 	err = pipeline.Start(mockPersister)
 	require.NoError(t, err)
 
-	err = pipeline.Stop()
+	err = pipeline.Stop(context.Background())
 	require.NoError(t, err)
 	require.Equal(t, []int{1, 2, 3}, stopOrder)
 }
 
+func TestPipelineStartWithStateOfOtherType(t *testing.T) {
+	ctx := context.Background()
+	mockOperator := testutil.NewMockOperator("operator1")
+	mockPersister := testutil.NewUnscopedMockPersister()
+	require.NoError(t, mockPersister.Set(ctx, "operator1.key", operator.EncodeState(operator.State{Type: "other", Data: []byte("value")})))
+
+	mockOperator.On("Outputs").Return(nil)
+	mockOperator.On("SetOutputs", mock.Anything).Return(nil)
+	mockOperator.On("Logger", mock.Anything).Return(zap.NewNop().Sugar())
+
+	var value []byte
+	var getErr error
+	mockOperator.On("Start", mock.Anything).Run(func(args mock.Arguments) {
+		value, getErr = args.Get(0).(operator.Persister).Get(ctx, "key")
+	}).Return(nil)
+
+	pipeline, err := NewDirectedPipeline([]operator.Operator{mockOperator})
+	require.NoError(t, err)
+
+	// State left by an operator of another type with the same id is ignored
+	require.NoError(t, pipeline.Start(mockPersister))
+	require.NoError(t, getErr)
+	require.Nil(t, value)
+}
+
 func TestPipelineRender(t *testing.T) {
 	mockOperator1 := testutil.NewMockOperator("operator1")
 	mockOperator2 := testutil.NewMockOperator("operator2")
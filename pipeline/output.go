@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+)
+
+var _ operator.BatchProcessor = (*outputRef)(nil)
+
+// outputRef is the output that operators of a pipeline are connected to in place of an operator
+// that processes entries. When the operator is replaced by a reload, the reference is pointed at
+// its replacement, so that the running operators upstream of it do not have to be replaced too.
+type outputRef struct {
+	target atomic.Value // refTarget
+}
+
+// refTarget wraps the target of a reference, since an atomic.Value must always hold the same type
+type refTarget struct {
+	operator.Operator
+}
+
+func newOutputRef(op operator.Operator) *outputRef {
+	ref := &outputRef{}
+	ref.set(op)
+	return ref
+}
+
+func (r *outputRef) get() operator.Operator {
+	return r.target.Load().(refTarget).Operator
+}
+
+func (r *outputRef) set(op operator.Operator) {
+	r.target.Store(refTarget{op})
+}
+
+// outputRefs returns the outputs that the operators are connected to. Operators that process entries are
+// referenced by the existing references to their ID, or by new ones, and are returned along with the
+// references. Operators that do not process entries are returned as they are, so that connecting to them fails.
+// Existing references are not modified, so they still refer to the operators they referred to before.
+func outputRefs(ops []operator.Operator, existing map[string]*outputRef) ([]operator.Operator, map[string]*outputRef) {
+	outputs := make([]operator.Operator, 0, len(ops))
+	refs := make(map[string]*outputRef, len(ops))
+	for _, op := range ops {
+		if !op.CanProcess() {
+			outputs = append(outputs, op)
+			continue
+		}
+
+		ref, ok := existing[op.ID()]
+		if !ok {
+			ref = newOutputRef(op)
+		}
+		refs[op.ID()] = ref
+		outputs = append(outputs, ref)
+	}
+	return outputs, refs
+}
+
+// ID returns the ID of the referenced operator. The other methods
+// of operator.Operator also forward to the referenced operator.
+func (r *outputRef) ID() string {
+	return r.get().ID()
+}
+
+func (r *outputRef) Type() string {
+	return r.get().Type()
+}
+
+func (r *outputRef) Start(persister operator.Persister) error {
+	return r.get().Start(persister)
+}
+
+func (r *outputRef) Stop() error {
+	return r.get().Stop()
+}
+
+func (r *outputRef) CanOutput() bool {
+	return r.get().CanOutput()
+}
+
+func (r *outputRef) Outputs() []operator.Operator {
+	return r.get().Outputs()
+}
+
+func (r *outputRef) GetOutputIDs() []string {
+	return r.get().GetOutputIDs()
+}
+
+func (r *outputRef) SetOutputs(ops []operator.Operator) error {
+	return r.get().SetOutputs(ops)
+}
+
+func (r *outputRef) SetOutputIDs(ids []string) {
+	r.get().SetOutputIDs(ids)
+}
+
+// CanProcess always returns true, since references are only created for operators that process entries
+func (r *outputRef) CanProcess() bool {
+	return true
+}
+
+// Process processes an entry with the referenced operator
+func (r *outputRef) Process(ctx context.Context, e *entry.Entry) error {
+	return r.get().Process(ctx, e)
+}
+
+func (r *outputRef) Logger() *zap.SugaredLogger {
+	return r.get().Logger()
+}
+
+// ProcessBatch processes a batch of entries with the referenced operator
+func (r *outputRef) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	return operator.ProcessBatch(ctx, r.get(), entries)
+}
@@ -17,13 +17,15 @@ package pipeline
 //go:generate mockery --name=^(Pipeline)$ --output=../testutil --outpkg=testutil --case=snake
 
 import (
+	"context"
+
 	"github.com/open-telemetry/opentelemetry-log-collection/operator"
 )
 
 // Pipeline is a collection of connected operators that exchange entries
 type Pipeline interface {
 	Start(persister operator.Persister) error
-	Stop() error
+	Stop(ctx context.Context) error
 	Operators() []operator.Operator
 	Render() ([]byte, error)
 }
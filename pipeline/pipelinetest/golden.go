@@ -0,0 +1,242 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pipelinetest runs pipelines against golden files.
+package pipelinetest
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/pipeline/sample"
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
+)
+
+const (
+	// ConfigFile, InputFile and ExpectedFile are the files of a golden test directory
+	ConfigFile   = "pipeline.yaml"
+	InputFile    = "input.log"
+	ExpectedFile = "expected.json"
+
+	// DefaultOutputID is the ID of the output that receives entries that are not sent to any output
+	DefaultOutputID = sample.DefaultOutputID
+
+	// UpdateEnv is the environment variable that requests the expected output files to be rewritten
+	UpdateEnv = "PIPELINETEST_UPDATE"
+)
+
+// DefaultTime is the observed timestamp of the entries sent through a golden pipeline
+var DefaultTime = time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Golden is a test that sends each line of an input file through a pipeline, and compares
+// the entries that reach its outputs with an expected output file. Input operators are
+// replaced by operators that emit the lines, and output operators by operators that
+// record the entries they receive, so the pipeline has no side effects.
+//
+// The pipeline is configured with the same file format as the validate command.
+// The expected output file is rewritten instead when Update is set, when the
+// PIPELINETEST_UPDATE environment variable is true, or when the test binary
+// defines an -update flag that is set.
+type Golden struct {
+	Config   string
+	Input    string
+	Expected string
+
+	// Time is the observed timestamp of the entries sent through the pipeline.
+	// If it is not set, DefaultTime is used.
+	Time time.Time
+
+	// Update rewrites the expected output file with the actual output
+	Update bool
+}
+
+// NewGolden creates a golden test from the files in a directory
+func NewGolden(dir string) Golden {
+	return Golden{
+		Config:   filepath.Join(dir, ConfigFile),
+		Input:    filepath.Join(dir, InputFile),
+		Expected: filepath.Join(dir, ExpectedFile),
+	}
+}
+
+// RunGoldenDir runs a golden test for each directory in a directory, such as testdata
+func RunGoldenDir(t *testing.T, dir string) {
+	dirs, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		golden := NewGolden(filepath.Join(dir, d.Name()))
+		t.Run(d.Name(), golden.Run)
+	}
+}
+
+// Output is an entry that reached an output of the pipeline
+type Output struct {
+	Output string          `json:"output"`
+	Entry  json.RawMessage `json:"entry"`
+}
+
+// Run runs the test, failing it if the entries that reach the outputs are not the expected entries
+func (g Golden) Run(t *testing.T) {
+	outputs, err := g.Outputs(testutil.Logger(t))
+	require.NoError(t, err)
+
+	actual, err := json.MarshalIndent(outputs, "", "  ")
+	require.NoError(t, err)
+	actual = append(actual, '\n')
+
+	if g.Update || updateRequested() {
+		require.NoError(t, ioutil.WriteFile(g.Expected, actual, 0600))
+		return
+	}
+
+	expected, err := ioutil.ReadFile(g.Expected)
+	if os.IsNotExist(err) {
+		require.FailNow(t, "expected output file does not exist", "run the test with %s=true to create %s", UpdateEnv, g.Expected)
+	}
+	require.NoError(t, err)
+
+	diff, err := Diff(expected, actual)
+	require.NoError(t, err)
+	if diff != "" {
+		require.FailNow(t, "pipeline output does not match "+g.Expected,
+			"%s\nrun the test with %s=true to accept the new output", diff, UpdateEnv)
+	}
+}
+
+// updateRequested returns true if the environment, or an -update flag
+// defined by the test binary, requests the expected outputs to be rewritten.
+// The flag is not defined by this package, so that it does not conflict with
+// test binaries that define their own.
+func updateRequested() bool {
+	if value, ok := os.LookupEnv(UpdateEnv); ok {
+		update, err := strconv.ParseBool(value)
+		return err == nil && update
+	}
+
+	f := flag.Lookup("update")
+	if f == nil {
+		return false
+	}
+	getter, ok := f.Value.(flag.Getter)
+	if !ok {
+		return false
+	}
+	update, ok := getter.Get().(bool)
+	return ok && update
+}
+
+// Diff returns a unified diff of two encoded outputs, or an empty string if they are equal.
+// The outputs are compared as JSON, so differences in formatting are ignored.
+func Diff(expected, actual []byte) (string, error) {
+	expectedText, err := normalize(expected)
+	if err != nil {
+		return "", fmt.Errorf("decode expected output: %w", err)
+	}
+	actualText, err := normalize(actual)
+	if err != nil {
+		return "", fmt.Errorf("decode actual output: %w", err)
+	}
+	if expectedText == actualText {
+		return "", nil
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(expectedText),
+		B:        difflib.SplitLines(actualText),
+		FromFile: "expected",
+		ToFile:   "actual",
+		Context:  3,
+	})
+}
+
+func normalize(encoded []byte) (string, error) {
+	var value interface{}
+	if err := json.Unmarshal(encoded, &value); err != nil {
+		return "", err
+	}
+	normalized, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(normalized) + "\n", nil
+}
+
+// Outputs runs the pipeline, and returns the entries that reached its outputs. The
+// entries are ordered by output ID, and then by the order they were received.
+func (g Golden) Outputs(logger *zap.SugaredLogger) ([]Output, error) {
+	file, err := sample.ReadConfigFile(g.Config)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := file.PipelineConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := sample.ReadLines(g.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	observed := g.Time
+	if observed.IsZero() {
+		observed = DefaultTime
+	}
+
+	recorder := &recorder{}
+	if err := sample.Run(cfg, lines, testutil.NewFakeClock(observed), recorder.record, logger); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(recorder.outputs, func(i, j int) bool {
+		return recorder.outputs[i].Output < recorder.outputs[j].Output
+	})
+	return recorder.outputs, nil
+}
+
+// recorder records the entries received by the outputs of a pipeline
+type recorder struct {
+	mux     sync.Mutex
+	outputs []Output
+}
+
+func (r *recorder) record(outputID string, e *entry.Entry) error {
+	encoded, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.outputs = append(r.outputs, Output{Output: outputID, Entry: encoded})
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipelinetest
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/input/file"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/output/drop"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/output/stdout"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/parser/json"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/parser/keyvalue"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/parser/regex"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/filter"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/move"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/router"
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
+)
+
+func TestGolden(t *testing.T) {
+	RunGoldenDir(t, "testdata")
+}
+
+func TestGoldenOutputs(t *testing.T) {
+	outputs, err := NewGolden(filepath.Join("testdata", "router")).Outputs(testutil.Logger(t))
+	require.NoError(t, err)
+	require.Len(t, outputs, 3)
+
+	// Outputs are ordered by output ID
+	require.Equal(t, "errors", outputs[0].Output)
+	require.Equal(t, "requests", outputs[1].Output)
+	require.Equal(t, "requests", outputs[2].Output)
+	require.Contains(t, string(outputs[1].Entry), `"path":"/"`)
+	require.Contains(t, string(outputs[2].Entry), `"path":"/login"`)
+}
+
+func TestGoldenTime(t *testing.T) {
+	golden := NewGolden(filepath.Join("testdata", "no_input"))
+	golden.Time = time.Date(2020, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	outputs, err := golden.Outputs(testutil.Logger(t))
+	require.NoError(t, err)
+	require.Len(t, outputs, 2)
+	require.Contains(t, string(outputs[0].Entry), `"observed_timestamp":"2020-06-01T12:00:00Z"`)
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	dir := t.TempDir()
+	config, err := ioutil.ReadFile(filepath.Join("testdata", "no_input", ConfigFile))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ConfigFile), config, 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, InputFile), []byte("line\n"), 0600))
+
+	golden := NewGolden(dir)
+	golden.Update = true
+	t.Run("Update", golden.Run)
+
+	// The rewritten output is then expected
+	golden.Update = false
+	t.Run("Compare", golden.Run)
+}
+
+func TestGoldenUpdateEnv(t *testing.T) {
+	dir := t.TempDir()
+	config, err := ioutil.ReadFile(filepath.Join("testdata", "no_input", ConfigFile))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ConfigFile), config, 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, InputFile), []byte("line\n"), 0600))
+
+	t.Setenv(UpdateEnv, "true")
+	require.True(t, updateRequested())
+	NewGolden(dir).Run(t)
+	require.FileExists(t, filepath.Join(dir, ExpectedFile))
+
+	t.Setenv(UpdateEnv, "false")
+	require.False(t, updateRequested())
+}
+
+func TestGoldenUpdateFlag(t *testing.T) {
+	// The package must not define the flag, since test binaries commonly define their own
+	require.Nil(t, flag.Lookup("update"))
+}
+
+func TestGoldenInvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, ConfigFile), []byte("pipeline:\n  - type: unknown\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, InputFile), []byte("line\n"), 0600))
+
+	_, err := NewGolden(dir).Outputs(testutil.Logger(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported type 'unknown'")
+}
+
+func TestDiff(t *testing.T) {
+	expected := []byte(`[{"output": "stdout", "entry": {"body": "a", "severity": 9}}]`)
+
+	diff, err := Diff(expected, []byte(`[{"entry":{"severity":9,"body":"a"},"output":"stdout"}]`))
+	require.NoError(t, err)
+	require.Empty(t, diff)
+
+	diff, err = Diff(expected, []byte(`[{"output": "stdout", "entry": {"body": "b", "severity": 9}}]`))
+	require.NoError(t, err)
+	require.Contains(t, diff, "--- expected\n+++ actual\n")
+	require.Contains(t, diff, "\n-      \"body\": \"a\",\n+      \"body\": \"b\",\n")
+
+	_, err = Diff([]byte("not json"), expected)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "decode expected output")
+}
@@ -0,0 +1,355 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jpillora/backoff"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+)
+
+const (
+	defaultQueueWorkers = 1
+	defaultQueueSize    = 1000
+)
+
+// NewQueueConfig creates a new queue config with default values
+func NewQueueConfig(operatorID string) QueueConfig {
+	return QueueConfig{
+		OperatorID: operatorID,
+		Workers:    defaultQueueWorkers,
+		Size:       defaultQueueSize,
+	}
+}
+
+// QueueConfig places a bounded queue in front of an operator. Every edge
+// leading into the operator writes to the queue, and a pool of workers
+// processes the queued entries concurrently with the upstream operators.
+type QueueConfig struct {
+	OperatorID string      `mapstructure:"operator_id"        json:"operator_id"        yaml:"operator_id"`
+	Workers    int         `mapstructure:"workers"            json:"workers"            yaml:"workers"`
+	Size       int         `mapstructure:"size"               json:"size"               yaml:"size"`
+	OrderBy    entry.Field `mapstructure:"order_by,omitempty" json:"order_by,omitempty" yaml:"order_by,omitempty"`
+}
+
+func (c QueueConfig) build(op operator.Operator) (*queuedOperator, error) {
+	if c.Workers < 1 {
+		return nil, errors.NewError(
+			"queue must have at least one worker",
+			"ensure that `workers` is a positive number",
+			"operator_id", c.OperatorID,
+		)
+	}
+
+	if c.Size < c.Workers {
+		return nil, errors.NewError(
+			"queue size must be at least the number of workers",
+			"ensure that `size` is greater than or equal to `workers`",
+			"operator_id", c.OperatorID,
+		)
+	}
+
+	if !op.CanProcess() {
+		return nil, errors.NewError(
+			"operator cannot be queued, because it can not process logs",
+			"ensure that queues are only placed in front of operators that process logs (like a parser or destination)",
+			"operator_id", c.OperatorID,
+		)
+	}
+
+	queued := &queuedOperator{
+		Operator: op,
+		orderBy:  c.OrderBy,
+		workers:  c.Workers,
+		size:     c.Size,
+	}
+	queued.queues = queued.newQueues()
+	return queued, nil
+}
+
+// queueOperators wraps each queued operator so that it receives entries through its queue.
+func queueOperators(ops []operator.Operator, queues []QueueConfig) ([]operator.Operator, error) {
+	queued := make(map[string]QueueConfig, len(queues))
+	for _, queue := range queues {
+		if _, ok := queued[queue.OperatorID]; ok {
+			return nil, errors.NewError(
+				fmt.Sprintf("operator '%s' has more than one queue", queue.OperatorID),
+				"ensure that each operator is queued at most once",
+			)
+		}
+		queued[queue.OperatorID] = queue
+	}
+
+	for i, op := range ops {
+		queue, ok := queued[op.ID()]
+		if !ok {
+			continue
+		}
+
+		queuedOp, err := queue.build(op)
+		if err != nil {
+			return nil, err
+		}
+		ops[i] = queuedOp
+		delete(queued, op.ID())
+	}
+
+	for _, queue := range queues {
+		if _, ok := queued[queue.OperatorID]; ok {
+			return nil, errors.NewError(
+				"queue cannot be created, because the operator does not exist in the pipeline",
+				"ensure that the queued operator is defined",
+				"operator_id", queue.OperatorID,
+			)
+		}
+	}
+
+	return ops, nil
+}
+
+// queuedOperator decouples an operator from its inputs by
+// buffering entries and processing them with a pool of workers.
+type queuedOperator struct {
+	operator.Operator
+	orderBy entry.Field
+	workers int
+	size    int
+	queues  []chan *entry.Entry
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mux     sync.RWMutex
+	stopped bool
+}
+
+// newQueues creates the queues of the operator. Without an ordering key, all workers
+// share a single queue. Otherwise each worker owns a partition of the queue so that
+// entries with the same key are always processed by the same worker, in the order
+// they were received.
+func (q *queuedOperator) newQueues() []chan *entry.Entry {
+	if q.orderBy.FieldInterface == nil {
+		return []chan *entry.Entry{make(chan *entry.Entry, q.size)}
+	}
+
+	queues := make([]chan *entry.Entry, 0, q.workers)
+	for i := 0; i < q.workers; i++ {
+		queues = append(queues, make(chan *entry.Entry, q.size/q.workers))
+	}
+	return queues
+}
+
+// Start starts the operator and its workers. The queues are recreated if
+// the operator was previously stopped, so that it can be restarted.
+func (q *queuedOperator) Start(persister operator.Persister) error {
+	if err := q.Operator.Start(persister); err != nil {
+		return err
+	}
+
+	q.mux.Lock()
+	if q.stopped {
+		q.queues = q.newQueues()
+		q.stopped = false
+	}
+	q.mux.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q.cancel = cancel
+
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work(ctx, q.queues[i%len(q.queues)])
+	}
+	return nil
+}
+
+func (q *queuedOperator) work(ctx context.Context, queue <-chan *entry.Entry) {
+	defer q.wg.Done()
+	for e := range queue {
+		if !q.process(ctx, e) {
+			q.abandon(e)
+		}
+	}
+}
+
+// process processes a queued entry, retrying it for as long as the operator
+// applies backpressure. It returns false if the context is done first.
+func (q *queuedOperator) process(ctx context.Context, e *entry.Entry) bool {
+	retry := backoff.Backoff{
+		Min: 10 * time.Millisecond,
+		Max: time.Second,
+	}
+
+	for {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		// Other errors are handled by the operator that encountered them, using its on_error strategy
+		err := q.Operator.Process(ctx, e)
+		if err != nil && ctx.Err() != nil {
+			return false
+		}
+		if !errors.IsBackpressure(err) {
+			if err != nil {
+				q.Logger().Debugw("Failed to process queued entry", zap.Error(err))
+			}
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(retry.Duration()):
+		}
+	}
+}
+
+// abandon releases an entry that could not be processed before the queue was cancelled.
+// The entry is not acknowledged, so its inputs read it again when they are restarted.
+func (q *queuedOperator) abandon(e *entry.Entry) {
+	q.Logger().Warnw("Abandoned queued entry", zap.Any("entry", e))
+	e.Abandon()
+	e.Release()
+}
+
+// Drain waits for all queued entries to be processed, and then drains the operator.
+// If the context is done first, the remaining queued entries are abandoned.
+func (q *queuedOperator) Drain(ctx context.Context) error {
+	q.closeQueues()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		remaining := 0
+		for _, queue := range q.queues {
+			remaining += len(queue)
+		}
+		if q.cancel != nil {
+			q.cancel()
+		}
+		return errors.NewError(
+			"queue was not drained before the deadline",
+			"increase the deadline, or the number of queue workers",
+			"operator_id", q.ID(),
+			"remaining", strconv.Itoa(remaining),
+		)
+	}
+
+	if drainer, ok := q.Operator.(operator.Drainer); ok {
+		return drainer.Drain(ctx)
+	}
+	return nil
+}
+
+// Stop waits for all queued entries to be processed before stopping the operator.
+func (q *queuedOperator) Stop() error {
+	q.closeQueues()
+	q.wg.Wait()
+	if q.cancel != nil {
+		q.cancel()
+	}
+	return q.Operator.Stop()
+}
+
+// closeQueues prevents new entries from being queued
+func (q *queuedOperator) closeQueues() {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	if !q.stopped {
+		q.stopped = true
+		for _, queue := range q.queues {
+			close(queue)
+		}
+	}
+}
+
+// Process adds an entry to the queue, applying backpressure while the queue is full.
+func (q *queuedOperator) Process(ctx context.Context, e *entry.Entry) error {
+	q.mux.RLock()
+	defer q.mux.RUnlock()
+
+	if q.stopped {
+		return errors.NewError(
+			"queued operator received an entry after it was stopped",
+			"this is an unexpected internal error",
+			"operator_id", q.ID(),
+		)
+	}
+
+	select {
+	case q.queueFor(e) <- e:
+		return nil
+	default:
+		return errors.NewBackpressureError("queue is full")
+	}
+}
+
+// ProcessBatch adds a batch of entries to the queue. If the queue fills up, the
+// returned errors.BatchError reports the first entry that was not queued.
+func (q *queuedOperator) ProcessBatch(ctx context.Context, entries []*entry.Entry) error {
+	q.mux.RLock()
+	defer q.mux.RUnlock()
+
+	if q.stopped {
+		return errors.NewError(
+			"queued operator received an entry after it was stopped",
+			"this is an unexpected internal error",
+			"operator_id", q.ID(),
+		)
+	}
+
+	for i, e := range entries {
+		select {
+		case q.queueFor(e) <- e:
+		default:
+			return errors.NewBatchError(i, errors.NewBackpressureError("queue is full"))
+		}
+	}
+	return nil
+}
+
+func (q *queuedOperator) queueFor(e *entry.Entry) chan *entry.Entry {
+	if len(q.queues) == 1 {
+		return q.queues[0]
+	}
+
+	// Entries without a key are all assigned to the first partition
+	value, ok := e.Get(q.orderBy)
+	if !ok {
+		return q.queues[0]
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(fmt.Sprint(value)))
+	return q.queues[hash.Sum32()%uint32(len(q.queues))]
+}
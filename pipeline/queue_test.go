@@ -0,0 +1,376 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/input/generate"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/noop"
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
+)
+
+func newQueuedPipeline(t *testing.T, queue QueueConfig) (*DirectedPipeline, *testutil.FakeOutput) {
+	fakeOutput := testutil.NewFakeOutput(t)
+	cfg := Config{
+		Operators: []operator.Config{
+			{
+				Builder: noop.NewNoopOperatorConfig("noop"),
+			},
+		},
+		DefaultOutput: fakeOutput,
+		Queues:        []QueueConfig{queue},
+	}
+
+	pipe, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	return pipe, fakeOutput
+}
+
+func findOperator(t *testing.T, pipe *DirectedPipeline, operatorID string) operator.Operator {
+	for _, op := range pipe.Operators() {
+		if op.ID() == operatorID {
+			return op
+		}
+	}
+	require.FailNow(t, "operator not found", operatorID)
+	return nil
+}
+
+func TestBuildQueueInvalid(t *testing.T) {
+	cases := []struct {
+		name     string
+		queues   []QueueConfig
+		expected string
+	}{
+		{
+			"NoWorkers",
+			[]QueueConfig{{OperatorID: "noop", Workers: 0, Size: 10}},
+			"queue must have at least one worker",
+		},
+		{
+			"SizeTooSmall",
+			[]QueueConfig{{OperatorID: "noop", Workers: 4, Size: 2}},
+			"queue size must be at least the number of workers",
+		},
+		{
+			"InputOperator",
+			[]QueueConfig{NewQueueConfig("generate_input")},
+			"operator cannot be queued",
+		},
+		{
+			"MissingOperator",
+			[]QueueConfig{NewQueueConfig("missing")},
+			"the operator does not exist in the pipeline",
+		},
+		{
+			"Duplicate",
+			[]QueueConfig{NewQueueConfig("noop"), NewQueueConfig("noop")},
+			"has more than one queue",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{
+				Operators: []operator.Config{
+					{
+						Builder: generate.NewGenerateInputConfig("generate_input"),
+					},
+					{
+						Builder: noop.NewNoopOperatorConfig("noop"),
+					},
+				},
+				DefaultOutput: testutil.NewFakeOutput(t),
+				Queues:        tc.queues,
+			}
+
+			_, err := cfg.Build(testutil.Logger(t))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestQueuedOperatorRender(t *testing.T) {
+	pipe, _ := newQueuedPipeline(t, NewQueueConfig("noop"))
+
+	dotGraph, err := pipe.Render()
+	require.NoError(t, err)
+	require.Contains(t, string(dotGraph), "noop -> fake;")
+}
+
+func TestQueuedOperatorProcess(t *testing.T) {
+	queue := NewQueueConfig("noop")
+	queue.Workers = 4
+	pipe, fakeOutput := newQueuedPipeline(t, queue)
+	require.NoError(t, pipe.Start(testutil.NewUnscopedMockPersister()))
+
+	noopOp := findOperator(t, pipe, "noop")
+	for i := 0; i < 50; i++ {
+		e := entry.New()
+		e.Body = i
+		require.NoError(t, noopOp.Process(context.Background(), e))
+	}
+
+	received := make([]interface{}, 0, 50)
+	for i := 0; i < 50; i++ {
+		e := <-fakeOutput.Received
+		received = append(received, e.Body)
+	}
+
+	expected := make([]interface{}, 0, 50)
+	for i := 0; i < 50; i++ {
+		expected = append(expected, i)
+	}
+	require.ElementsMatch(t, expected, received)
+	require.NoError(t, pipe.Stop(context.Background()))
+}
+
+func TestQueuedOperatorOrderBy(t *testing.T) {
+	queue := NewQueueConfig("noop")
+	queue.Workers = 4
+	queue.Size = 100
+	queue.OrderBy = entry.NewAttributeField("file.path")
+	pipe, fakeOutput := newQueuedPipeline(t, queue)
+	require.NoError(t, pipe.Start(testutil.NewUnscopedMockPersister()))
+
+	sources := []string{"a.log", "b.log", "c.log"}
+	noopOp := findOperator(t, pipe, "noop")
+	for i := 0; i < 30; i++ {
+		e := entry.New()
+		e.AddAttribute("file.path", sources[i%len(sources)])
+		e.Body = i
+		require.NoError(t, noopOp.Process(context.Background(), e))
+	}
+
+	lastSeen := make(map[interface{}]int)
+	for i := 0; i < 30; i++ {
+		e := <-fakeOutput.Received
+		source := e.Attributes["file.path"]
+		if last, ok := lastSeen[source]; ok {
+			require.Greater(t, e.Body.(int), last, fmt.Sprintf("entries from %s out of order", source))
+		}
+		lastSeen[source] = e.Body.(int)
+	}
+	require.NoError(t, pipe.Stop(context.Background()))
+}
+
+func TestQueuedOperatorStopDrains(t *testing.T) {
+	pipe, fakeOutput := newQueuedPipeline(t, NewQueueConfig("noop"))
+	require.NoError(t, pipe.Start(testutil.NewUnscopedMockPersister()))
+
+	noopOp := findOperator(t, pipe, "noop")
+	for i := 0; i < 10; i++ {
+		require.NoError(t, noopOp.Process(context.Background(), entry.New()))
+	}
+
+	require.NoError(t, pipe.Stop(context.Background()))
+	require.Len(t, fakeOutput.Received, 10)
+
+	err := noopOp.Process(context.Background(), entry.New())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "after it was stopped")
+}
+
+// blockingOutput does not accept entries until the context is done
+type blockingOutput struct {
+	*testutil.FakeOutput
+}
+
+func (b *blockingOutput) Process(ctx context.Context, _ *entry.Entry) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestQueuedOperatorDrainDeadline(t *testing.T) {
+	cfg := Config{
+		Operators: []operator.Config{
+			{
+				Builder: noop.NewNoopOperatorConfig("noop"),
+			},
+		},
+		DefaultOutput: &blockingOutput{testutil.NewFakeOutput(t)},
+		Queues:        []QueueConfig{NewQueueConfig("noop")},
+	}
+	pipe, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.NoError(t, pipe.Start(testutil.NewUnscopedMockPersister()))
+
+	noopOp := findOperator(t, pipe, "noop")
+	for i := 0; i < 5; i++ {
+		require.NoError(t, noopOp.Process(context.Background(), entry.New()))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = pipe.Stop(ctx)
+	require.Error(t, err)
+
+	var drainErr *DrainError
+	require.True(t, stderrors.As(err, &drainErr))
+	require.Len(t, drainErr.Errors, 1)
+	require.Contains(t, drainErr.Errors["noop"].Error(), "queue was not drained before the deadline")
+	require.Contains(t, err.Error(), "failed to drain operators: noop")
+}
+
+func TestQueuedOperatorProcessBatch(t *testing.T) {
+	pipe, fakeOutput := newQueuedPipeline(t, NewQueueConfig("noop"))
+	require.NoError(t, pipe.Start(testutil.NewUnscopedMockPersister()))
+
+	noopOp := findOperator(t, pipe, "noop")
+	entries := []*entry.Entry{entry.New(), entry.New(), entry.New()}
+	require.NoError(t, operator.ProcessBatch(context.Background(), noopOp, entries))
+
+	require.NoError(t, pipe.Stop(context.Background()))
+	require.Len(t, fakeOutput.Received, 3)
+}
+
+// backpressureOutput rejects the first entries it receives with backpressure
+type backpressureOutput struct {
+	*testutil.FakeOutput
+	mux      sync.Mutex
+	rejected int
+	reject   int
+}
+
+func (b *backpressureOutput) Process(ctx context.Context, e *entry.Entry) error {
+	b.mux.Lock()
+	if b.rejected < b.reject {
+		b.rejected++
+		b.mux.Unlock()
+		return errors.NewBackpressureError("output is full")
+	}
+	b.mux.Unlock()
+	return b.FakeOutput.Process(ctx, e)
+}
+
+func TestQueuedOperatorRetriesBackpressure(t *testing.T) {
+	output := &backpressureOutput{FakeOutput: testutil.NewFakeOutput(t), reject: 3}
+	cfg := Config{
+		Operators: []operator.Config{
+			{
+				Builder: noop.NewNoopOperatorConfig("noop"),
+			},
+		},
+		DefaultOutput: output,
+		Queues:        []QueueConfig{NewQueueConfig("noop")},
+	}
+	pipe, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.NoError(t, pipe.Start(testutil.NewUnscopedMockPersister()))
+
+	noopOp := findOperator(t, pipe, "noop")
+	e := entry.New()
+	e.Body = "retried"
+	require.NoError(t, noopOp.Process(context.Background(), e))
+
+	output.ExpectBody(t, "retried")
+	require.NoError(t, pipe.Stop(context.Background()))
+}
+
+func TestQueuedOperatorFullQueue(t *testing.T) {
+	queue := NewQueueConfig("noop")
+	queue.Size = 1
+	cfg := Config{
+		Operators: []operator.Config{
+			{
+				Builder: noop.NewNoopOperatorConfig("noop"),
+			},
+		},
+		DefaultOutput: &blockingOutput{testutil.NewFakeOutput(t)},
+		Queues:        []QueueConfig{queue},
+	}
+	pipe, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.NoError(t, pipe.Start(testutil.NewUnscopedMockPersister()))
+
+	// The first entry is held by the worker, and the second fills the queue
+	noopOp := findOperator(t, pipe, "noop")
+	require.NoError(t, noopOp.Process(context.Background(), entry.New()))
+	require.Eventually(t, func() bool {
+		return noopOp.Process(context.Background(), entry.New()) == nil
+	}, time.Second, 10*time.Millisecond)
+
+	err = noopOp.Process(context.Background(), entry.New())
+	require.True(t, errors.IsBackpressure(err))
+
+	err = operator.ProcessBatch(context.Background(), noopOp, []*entry.Entry{entry.New()})
+	require.True(t, errors.IsBackpressure(err))
+	require.Equal(t, 0, errors.BatchAccepted(err, 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.Error(t, pipe.Stop(ctx))
+}
+
+func TestQueuedOperatorAbandonsEntries(t *testing.T) {
+	cfg := Config{
+		Operators: []operator.Config{
+			{
+				Builder: noop.NewNoopOperatorConfig("noop"),
+			},
+		},
+		DefaultOutput: &blockingOutput{testutil.NewFakeOutput(t)},
+		Queues:        []QueueConfig{NewQueueConfig("noop")},
+	}
+	pipe, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.NoError(t, pipe.Start(testutil.NewUnscopedMockPersister()))
+
+	// The checkpoint of an input is committed as its entries are acknowledged
+	acks := helper.NewAckTracker(0, nil)
+	noopOp := findOperator(t, pipe, "noop")
+	for i := 1; i <= 5; i++ {
+		e := entry.New()
+		acks.Track(e, i)
+		require.NoError(t, noopOp.Process(context.Background(), e))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.Error(t, pipe.Stop(ctx))
+
+	// The entry held by the blocked worker is abandoned along with the queued entries,
+	// without advancing the checkpoint past them
+	require.Never(t, func() bool {
+		return acks.Committed() != 0
+	}, 100*time.Millisecond, 10*time.Millisecond)
+	require.Equal(t, 5, acks.Pending())
+}
+
+func TestQueuedOperatorRestart(t *testing.T) {
+	pipe, fakeOutput := newQueuedPipeline(t, NewQueueConfig("noop"))
+	noopOp := findOperator(t, pipe, "noop")
+
+	// A stopped queued operator can be started again, with new queues
+	for i := 0; i < 2; i++ {
+		require.NoError(t, noopOp.Start(testutil.NewUnscopedMockPersister()))
+		require.NoError(t, noopOp.Process(context.Background(), entry.New()))
+		require.NoError(t, noopOp.Stop())
+		<-fakeOutput.Received
+	}
+}
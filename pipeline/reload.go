@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"reflect"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+)
+
+var notStarted = errors.NewError("pipeline not started", "ensure that the pipeline is started before it is reloaded")
+
+// operatorSpec describes how an operator was built from a pipeline config.
+type operatorSpec struct {
+	builder   operator.Builder
+	instance  operator.Operator
+	outputIDs []string
+	queue     QueueConfig
+}
+
+func (s operatorSpec) equal(other operatorSpec) bool {
+	return s.instance == other.instance &&
+		reflect.DeepEqual(s.builder, other.builder) &&
+		reflect.DeepEqual(s.outputIDs, other.outputIDs) &&
+		reflect.DeepEqual(s.queue, other.queue)
+}
+
+// Reload will replace the operators of a running pipeline with those defined by a new config.
+//
+// Operators are matched by ID. An operator is kept running if its type, config and outputs are unchanged.
+// All other operators are replaced by the operators of the new config, and the running operators that
+// output to them are connected to their replacements. Since operators are started with a persister scoped
+// to their ID, replaced operators resume from the state persisted by their predecessors.
+//
+// New operators are started before the operators they replace are stopped, so that the pipeline is left
+// unchanged if any of them fails to start. Replaced operators are then drained to the new operators as they
+// are by Stop, within the deadline of the context. The new config is validated before any operator is started.
+func (p *DirectedPipeline) Reload(ctx context.Context, cfg Config, logger *zap.SugaredLogger) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if p.stopped {
+		return alreadyStopped
+	}
+	if !p.started {
+		return notStarted
+	}
+
+	cfg, err := cfg.prepare()
+	if err != nil {
+		return err
+	}
+
+	newOps, err := cfg.buildOperators(logger)
+	if err != nil {
+		return err
+	}
+	newSpecs := cfg.specs(newOps)
+
+	replaced := p.replacedOperators(newSpecs)
+	kept := make(map[string]operator.Operator)
+	nodes := p.Graph.Nodes()
+	for nodes.Next() {
+		op := nodes.Node().(OperatorNode).Operator()
+		if !replaced[op.ID()] {
+			kept[op.ID()] = op
+		}
+	}
+
+	ops := make([]operator.Operator, 0, len(newOps))
+	added := make([]operator.Operator, 0, len(newOps))
+	for _, op := range newOps {
+		if keptOp, ok := kept[op.ID()]; ok {
+			ops = append(ops, keptOp)
+			continue
+		}
+		ops = append(ops, op)
+		added = append(added, op)
+		if op == cfg.DefaultOutput {
+			cfg.configure(op)
+		}
+	}
+
+	// Kept operators are running, so their outputs must not be modified. They are connected to the
+	// references of their outputs, which are pointed at the new operators once these are started.
+	outputs, refs := outputRefs(ops, p.outputs)
+	for _, op := range added {
+		if !op.CanOutput() {
+			continue
+		}
+		if err := op.SetOutputs(outputs); err != nil {
+			return errors.WithDetails(err, "operator_id", op.ID())
+		}
+	}
+
+	graph, err := newGraph(ops)
+	if err != nil {
+		return err
+	}
+
+	isKept := func(op operator.Operator) bool {
+		_, ok := kept[op.ID()]
+		return ok
+	}
+
+	started, err := startNodes(graph, p.persister, isKept)
+	if err != nil {
+		// The references still point at the running operators, so only the started operators receive entries
+		isStarted := make(map[operator.Operator]bool, len(started))
+		for _, op := range started {
+			isStarted[op] = true
+		}
+		stopErr := stopNodes(ctx, graph, func(op operator.Operator) bool { return !isStarted[op] })
+		return multierr.Append(err, stopErr)
+	}
+
+	for _, op := range ops {
+		if ref, ok := refs[op.ID()]; ok && ref.get() != op {
+			ref.set(op)
+		}
+	}
+
+	stopErr := stopNodes(ctx, p.Graph, isKept)
+	p.Graph = graph
+	p.specs = newSpecs
+	p.outputs = refs
+	return stopErr
+}
+
+// replacedOperators returns the IDs of the running operators that must be stopped to apply new specs.
+// These are the operators that were removed or changed.
+func (p *DirectedPipeline) replacedOperators(newSpecs map[string]operatorSpec) map[string]bool {
+	replaced := make(map[string]bool)
+	nodes := p.Graph.Nodes()
+	for nodes.Next() {
+		op := nodes.Node().(OperatorNode).Operator()
+		oldSpec, ok := p.specs[op.ID()]
+		if !ok {
+			replaced[op.ID()] = true
+			continue
+		}
+
+		newSpec, ok := newSpecs[op.ID()]
+		if !ok || !oldSpec.equal(newSpec) {
+			replaced[op.ID()] = true
+		}
+	}
+	return replaced
+}
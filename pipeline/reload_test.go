@@ -0,0 +1,314 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/clock"
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/parser/json"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/noop"
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
+)
+
+// startCounterConfig builds an operator that counts its starts in its persister
+type startCounterConfig struct {
+	helper.WriterConfig
+	Label string
+	// built records the operators built from the config
+	built *[]*startCounter
+}
+
+func (c startCounterConfig) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
+	writer, err := c.WriterConfig.Build(logger)
+	if err != nil {
+		return nil, err
+	}
+	op := &startCounter{WriterOperator: writer}
+	if c.built != nil {
+		*c.built = append(*c.built, op)
+	}
+	return op, nil
+}
+
+type startCounter struct {
+	helper.WriterOperator
+	starts int
+	stops  int
+}
+
+func (s *startCounter) Stop() error {
+	s.stops++
+	return nil
+}
+
+func (s *startCounter) Start(persister operator.Persister) error {
+	value, err := persister.Get(context.Background(), "starts")
+	if err != nil {
+		return err
+	}
+	if value != nil {
+		if s.starts, err = strconv.Atoi(string(value)); err != nil {
+			return err
+		}
+	}
+	s.starts++
+	return persister.Set(context.Background(), "starts", []byte(strconv.Itoa(s.starts)))
+}
+
+func (s *startCounter) CanProcess() bool {
+	return true
+}
+
+func (s *startCounter) Process(ctx context.Context, e *entry.Entry) error {
+	return s.Write(ctx, e)
+}
+
+func newReloadConfig(fakeOutput operator.Operator, label, parseTo string) Config {
+	parserCfg := json.NewJSONParserConfig("parser")
+	parserCfg.ParseTo = entry.NewAttributeField(parseTo)
+	return Config{
+		Operators: []operator.Config{
+			{
+				Builder: &startCounterConfig{
+					WriterConfig: helper.NewWriterConfig("counter", "start_counter"),
+					Label:        label,
+				},
+			},
+			{
+				Builder: parserCfg,
+			},
+			{
+				Builder: noop.NewNoopOperatorConfig("noop"),
+			},
+		},
+		DefaultOutput: fakeOutput,
+	}
+}
+
+func startReloadPipeline(t *testing.T) (*DirectedPipeline, *testutil.FakeOutput) {
+	fakeOutput := testutil.NewFakeOutput(t)
+	pipe, err := newReloadConfig(fakeOutput, "a", "parsed").Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.NoError(t, pipe.Start(testutil.NewUnscopedMockPersister()))
+	t.Cleanup(func() { _ = pipe.Stop(context.Background()) })
+	return pipe, fakeOutput
+}
+
+func TestReloadUnchanged(t *testing.T) {
+	pipe, fakeOutput := startReloadPipeline(t)
+	before := pipe.Operators()
+
+	require.NoError(t, pipe.Reload(context.Background(), newReloadConfig(fakeOutput, "a", "parsed"), testutil.Logger(t)))
+	require.ElementsMatch(t, before, pipe.Operators())
+}
+
+func TestReloadChanged(t *testing.T) {
+	pipe, fakeOutput := startReloadPipeline(t)
+	noopOp := findOperator(t, pipe, "noop")
+	parserOp := findOperator(t, pipe, "parser")
+	counterOp := findOperator(t, pipe, "counter")
+
+	require.NoError(t, pipe.Reload(context.Background(), newReloadConfig(fakeOutput, "a", "reloaded"), testutil.Logger(t)))
+
+	// Only the changed operator is replaced, and the operator upstream of it is connected to its replacement
+	require.Same(t, noopOp, findOperator(t, pipe, "noop"))
+	require.Same(t, fakeOutput, findOperator(t, pipe, "fake"))
+	require.NotSame(t, parserOp, findOperator(t, pipe, "parser"))
+	require.Same(t, counterOp, findOperator(t, pipe, "counter"))
+	require.Equal(t, 1, counterOp.(*startCounter).starts)
+
+	e := entry.New()
+	e.Body = `{"key":"value"}`
+	require.NoError(t, counterOp.Process(context.Background(), e))
+	received := <-fakeOutput.Received
+	require.Equal(t, map[string]interface{}{"key": "value"}, received.Attributes["reloaded"])
+}
+
+func TestReloadChangedInput(t *testing.T) {
+	pipe, fakeOutput := startReloadPipeline(t)
+	parserOp := findOperator(t, pipe, "parser")
+	counterOp := findOperator(t, pipe, "counter")
+
+	require.NoError(t, pipe.Reload(context.Background(), newReloadConfig(fakeOutput, "b", "parsed"), testutil.Logger(t)))
+	require.Same(t, parserOp, findOperator(t, pipe, "parser"))
+	require.NotSame(t, counterOp, findOperator(t, pipe, "counter"))
+}
+
+func TestReloadRemovedAndAdded(t *testing.T) {
+	pipe, fakeOutput := startReloadPipeline(t)
+
+	cfg := newReloadConfig(fakeOutput, "a", "parsed")
+	cfg.Operators[2] = operator.Config{Builder: noop.NewNoopOperatorConfig("other")}
+	require.NoError(t, pipe.Reload(context.Background(), cfg, testutil.Logger(t)))
+
+	ids := make([]string, 0)
+	for _, op := range pipe.Operators() {
+		ids = append(ids, op.ID())
+	}
+	require.ElementsMatch(t, []string{"counter", "parser", "other", "fake"}, ids)
+
+	dotGraph, err := pipe.Render()
+	require.NoError(t, err)
+	require.Contains(t, string(dotGraph), "parser -> other;")
+	require.Contains(t, string(dotGraph), "other -> fake;")
+}
+
+func TestReloadInvalid(t *testing.T) {
+	pipe, fakeOutput := startReloadPipeline(t)
+	before := pipe.Operators()
+
+	cfg := newReloadConfig(fakeOutput, "b", "parsed")
+	cfg.Operators[1].Builder.(*json.JSONParserConfig).OnError = "invalid"
+	err := pipe.Reload(context.Background(), cfg, testutil.Logger(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "on_error")
+	require.ElementsMatch(t, before, pipe.Operators())
+	require.Equal(t, 1, findOperator(t, pipe, "counter").(*startCounter).starts)
+}
+
+func TestReloadNotRunning(t *testing.T) {
+	fakeOutput := testutil.NewFakeOutput(t)
+	cfg := newReloadConfig(fakeOutput, "a", "parsed")
+	pipe, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	err = pipe.Reload(context.Background(), cfg, testutil.Logger(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not started")
+
+	require.NoError(t, pipe.Start(testutil.NewUnscopedMockPersister()))
+	require.NoError(t, pipe.Stop(context.Background()))
+
+	err = pipe.Reload(context.Background(), cfg, testutil.Logger(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already stopped")
+}
+
+// failingConfig builds an operator that fails to start
+type failingConfig struct {
+	helper.WriterConfig
+}
+
+func (c failingConfig) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
+	writer, err := c.WriterConfig.Build(logger)
+	if err != nil {
+		return nil, err
+	}
+	return &failing{startCounter: startCounter{WriterOperator: writer}}, nil
+}
+
+type failing struct {
+	startCounter
+}
+
+func (f *failing) Start(operator.Persister) error {
+	return fmt.Errorf("failed to start")
+}
+
+func TestReloadStartFailure(t *testing.T) {
+	pipe, fakeOutput := startReloadPipeline(t)
+	before := pipe.Operators()
+	counterOp := findOperator(t, pipe, "counter")
+	parserOp := findOperator(t, pipe, "parser")
+
+	// The new noop operator is started before the counter that replaces it fails, and is stopped again
+	var built []*startCounter
+	cfg := newReloadConfig(fakeOutput, "a", "reloaded")
+	cfg.Operators[0] = operator.Config{Builder: &failingConfig{WriterConfig: helper.NewWriterConfig("counter", "failing")}}
+	cfg.Operators[2] = operator.Config{Builder: &startCounterConfig{WriterConfig: helper.NewWriterConfig("noop", "start_counter"), built: &built}}
+	err := pipe.Reload(context.Background(), cfg, testutil.Logger(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to start")
+	require.ElementsMatch(t, before, pipe.Operators())
+	require.Len(t, built, 1)
+	require.Equal(t, 1, built[0].starts)
+	require.Equal(t, 1, built[0].stops)
+
+	// Entries are still processed by the old operators
+	e := entry.New()
+	e.Body = `{"key":"value"}`
+	require.NoError(t, counterOp.Process(context.Background(), e))
+	received := <-fakeOutput.Received
+	require.Equal(t, map[string]interface{}{"key": "value"}, received.Attributes["parsed"])
+
+	// A later reload replaces the operators that failed to be replaced
+	require.NoError(t, pipe.Reload(context.Background(), newReloadConfig(fakeOutput, "a", "reloaded"), testutil.Logger(t)))
+	require.NotSame(t, parserOp, findOperator(t, pipe, "parser"))
+}
+
+// clockedOutput counts the clocks it is given
+type clockedOutput struct {
+	*testutil.FakeOutput
+	clocks int
+}
+
+func (c *clockedOutput) SetClock(clock.Clock) {
+	c.clocks++
+}
+
+func TestReloadConfiguresNewOperators(t *testing.T) {
+	output := &clockedOutput{FakeOutput: testutil.NewFakeOutput(t)}
+	cfg := newReloadConfig(output, "a", "parsed")
+	cfg.Clock = testutil.NewFakeClock(time.Now())
+	pipe, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.NoError(t, pipe.Start(testutil.NewUnscopedMockPersister()))
+	t.Cleanup(func() { _ = pipe.Stop(context.Background()) })
+	require.Equal(t, 1, output.clocks)
+
+	// The running default output is not configured again
+	cfg = newReloadConfig(output, "a", "reloaded")
+	cfg.Clock = testutil.NewFakeClock(time.Now())
+	require.NoError(t, pipe.Reload(context.Background(), cfg, testutil.Logger(t)))
+	require.Equal(t, 1, output.clocks)
+}
+
+func TestReloadWhileProcessing(t *testing.T) {
+	pipe, fakeOutput := startReloadPipeline(t)
+	counterOp := findOperator(t, pipe, "counter")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			e := entry.New()
+			e.Body = `{"key":"value"}`
+			assert.NoError(t, counterOp.Process(context.Background(), e))
+		}
+	}()
+
+	require.NoError(t, pipe.Reload(context.Background(), newReloadConfig(fakeOutput, "a", "reloaded"), testutil.Logger(t)))
+	<-done
+
+	// Every entry is processed by either the old or the new parser
+	for i := 0; i < 100; i++ {
+		received := <-fakeOutput.Received
+		if received.Attributes["parsed"] == nil {
+			require.NotNil(t, received.Attributes["reloaded"])
+		}
+	}
+}
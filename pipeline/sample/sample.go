@@ -0,0 +1,281 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sample sends sample log lines through a pipeline without side effects.
+// It is used by the validate command and by golden-file pipeline tests.
+package sample
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/clock"
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
+	"github.com/open-telemetry/opentelemetry-log-collection/pipeline"
+)
+
+const (
+	// DefaultOutputID is the ID of the output that receives entries that are not sent to any output
+	DefaultOutputID = "default_output"
+
+	maxLineSize = 1024 * 1024
+	runTimeout  = 10 * time.Second
+)
+
+// ConfigFile is the structure of a pipeline configuration file
+type ConfigFile struct {
+	Pipeline []OperatorConfig       `yaml:"pipeline"`
+	Queues   []pipeline.QueueConfig `yaml:"queues"`
+}
+
+// OperatorConfig unmarshals an operator config of a configuration file, keeping
+// the error rather than failing, so that all invalid operators can be reported.
+type OperatorConfig struct {
+	operator.Config
+	Err error
+}
+
+// UnmarshalYAML will unmarshal an operator config from YAML
+func (o *OperatorConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	o.Err = o.Config.UnmarshalYAML(unmarshal)
+	return nil
+}
+
+// ReadConfigFile reads a pipeline configuration file. Unknown operator fields
+// are reported when the operators are built, as in a running pipeline.
+func ReadConfigFile(path string) (ConfigFile, error) {
+	bytes, err := ioutil.ReadFile(path) // #nosec - configs are loaded from user provided paths
+	if err != nil {
+		return ConfigFile{}, err
+	}
+
+	var file ConfigFile
+	if err := yaml.Unmarshal(bytes, &file); err != nil {
+		return ConfigFile{}, fmt.Errorf("failed to read pipeline config %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// PipelineConfig returns the config of the pipeline defined by the file,
+// or the error of the first operator that could not be unmarshalled.
+func (f ConfigFile) PipelineConfig() (pipeline.Config, error) {
+	cfg := pipeline.Config{Queues: f.Queues}
+	for i, opCfg := range f.Pipeline {
+		if opCfg.Err != nil {
+			return pipeline.Config{}, fmt.Errorf("operator %d: %w", i+1, opCfg.Err)
+		}
+		cfg.Operators = append(cfg.Operators, opCfg.Config)
+	}
+	return cfg, nil
+}
+
+// ReadLines reads the lines of a sample file
+func ReadLines(path string) ([]string, error) {
+	file, err := os.Open(path) // #nosec - samples are loaded from user provided paths
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// RecordFunc is called with each entry that reaches an output of the pipeline
+type RecordFunc func(outputID string, e *entry.Entry) error
+
+// Run sends each line through a pipeline. Input operators are replaced by operators that
+// emit the lines, and output operators by operators that pass the entries they receive to
+// record, so the pipeline has no side effects. Pipelines without inputs receive the lines at
+// the operators that no other operator outputs to. If clk is not nil, it is the clock of the
+// pipeline, and the lines are observed at its time.
+func Run(cfg pipeline.Config, lines []string, clk clock.Clock, record RecordFunc, logger *zap.SugaredLogger) error {
+	cfg.DefaultOutput = newRecordOutput(DefaultOutputID, record, logger)
+	cfg.Clock = clk
+
+	pipe, err := cfg.Build(logger)
+	if err != nil {
+		return err
+	}
+
+	var inputs []operator.Operator
+	ops := make([]operator.Operator, 0)
+	for _, op := range pipe.Operators() {
+		switch {
+		case !op.CanProcess():
+			input := newLineInput(op, logger)
+			if clk != nil {
+				input.SetClock(clk)
+			}
+			inputs = append(inputs, input)
+			ops = append(ops, input)
+		case !op.CanOutput() && op.ID() != DefaultOutputID:
+			ops = append(ops, newRecordOutput(op.ID(), record, logger))
+		default:
+			ops = append(ops, op)
+		}
+	}
+
+	if len(inputs) == 0 {
+		inputs = roots(ops)
+	}
+
+	samplePipe, err := pipeline.NewDirectedPipeline(ops)
+	if err != nil {
+		return err
+	}
+	if err := samplePipe.Start(newMemoryPersister()); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
+	defer cancel()
+
+	var sendErr error
+	for _, line := range lines {
+		for _, input := range inputs {
+			e := entry.New()
+			if clk != nil {
+				e.ObservedTimestamp = clk.Now()
+			}
+			e.Body = line
+			// Errors are handled by the operators' on_error settings, like in a running pipeline
+			if err := input.Process(ctx, e); err != nil {
+				logger.Debugw("Failed to process line", zap.Error(err))
+			}
+		}
+		if ctx.Err() != nil {
+			sendErr = ctx.Err()
+			break
+		}
+	}
+
+	if err := samplePipe.Stop(ctx); err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// roots returns the operators that are not an output of another operator
+func roots(ops []operator.Operator) []operator.Operator {
+	outputs := make(map[string]bool)
+	for _, op := range ops {
+		for _, id := range op.GetOutputIDs() {
+			outputs[id] = true
+		}
+	}
+
+	var result []operator.Operator
+	for _, op := range ops {
+		if op.CanProcess() && !outputs[op.ID()] && op.ID() != DefaultOutputID {
+			result = append(result, op)
+		}
+	}
+	return result
+}
+
+// lineInput replaces an input operator, emitting the entries it is given to the input's outputs
+type lineInput struct {
+	helper.WriterOperator
+}
+
+func newLineInput(input operator.Operator, logger *zap.SugaredLogger) *lineInput {
+	return &lineInput{
+		WriterOperator: helper.WriterOperator{
+			BasicOperator: helper.BasicOperator{
+				OperatorID:    input.ID(),
+				OperatorType:  input.Type(),
+				SugaredLogger: logger.With("operator_id", input.ID()),
+			},
+			OutputIDs: input.GetOutputIDs(),
+		},
+	}
+}
+
+func (l *lineInput) CanProcess() bool {
+	return true
+}
+
+func (l *lineInput) Process(ctx context.Context, e *entry.Entry) error {
+	return l.Write(ctx, e)
+}
+
+// recordOutput replaces an output operator, recording the entries it receives
+type recordOutput struct {
+	helper.OutputOperator
+	record RecordFunc
+}
+
+func newRecordOutput(operatorID string, record RecordFunc, logger *zap.SugaredLogger) *recordOutput {
+	return &recordOutput{
+		OutputOperator: helper.OutputOperator{
+			BasicOperator: helper.BasicOperator{
+				OperatorID:    operatorID,
+				OperatorType:  "record_output",
+				SugaredLogger: logger.With("operator_id", operatorID),
+			},
+		},
+		record: record,
+	}
+}
+
+func (r *recordOutput) Process(_ context.Context, e *entry.Entry) error {
+	e.Ack()
+	return r.record(r.ID(), e)
+}
+
+// memoryPersister is a persister that does not outlive the sample
+type memoryPersister struct {
+	mux  sync.Mutex
+	data map[string][]byte
+}
+
+func newMemoryPersister() *memoryPersister {
+	return &memoryPersister{data: make(map[string][]byte)}
+}
+
+func (p *memoryPersister) Get(_ context.Context, key string) ([]byte, error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return p.data[key], nil
+}
+
+func (p *memoryPersister) Set(_ context.Context, key string, value []byte) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.data[key] = value
+	return nil
+}
+
+func (p *memoryPersister) Delete(_ context.Context, key string) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	delete(p.data, key)
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sample
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/input/file"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/output/stdout"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/parser/regex"
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
+)
+
+func writeFile(t *testing.T, name, contents string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+	return path
+}
+
+type recorded struct {
+	mux     sync.Mutex
+	outputs []string
+	bodies  []interface{}
+}
+
+func (r *recorded) record(outputID string, e *entry.Entry) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.outputs = append(r.outputs, outputID)
+	r.bodies = append(r.bodies, e.Body)
+	return nil
+}
+
+func TestReadConfigFileKeepsOperatorErrors(t *testing.T) {
+	path := writeFile(t, "pipeline.yaml", `
+pipeline:
+  - type: unknown_operator
+  - type: stdout
+`)
+
+	file, err := ReadConfigFile(path)
+	require.NoError(t, err)
+	require.Len(t, file.Pipeline, 2)
+	require.Error(t, file.Pipeline[0].Err)
+	require.NoError(t, file.Pipeline[1].Err)
+
+	_, err = file.PipelineConfig()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "operator 1")
+}
+
+func TestRunReplacesInputsAndOutputs(t *testing.T) {
+	path := writeFile(t, "pipeline.yaml", `
+pipeline:
+  - type: file_input
+    include: [ /does/not/exist ]
+  - type: stdout
+`)
+	file, err := ReadConfigFile(path)
+	require.NoError(t, err)
+	cfg, err := file.PipelineConfig()
+	require.NoError(t, err)
+
+	r := &recorded{}
+	require.NoError(t, Run(cfg, []string{"a", "b"}, nil, r.record, testutil.Logger(t)))
+	require.Equal(t, []string{"stdout", "stdout"}, r.outputs)
+	require.Equal(t, []interface{}{"a", "b"}, r.bodies)
+}
+
+func TestRunWithoutInputs(t *testing.T) {
+	path := writeFile(t, "pipeline.yaml", `
+pipeline:
+  - type: regex_parser
+    regex: '^(?P<level>\w+)'
+`)
+	file, err := ReadConfigFile(path)
+	require.NoError(t, err)
+	cfg, err := file.PipelineConfig()
+	require.NoError(t, err)
+
+	clk := testutil.NewFakeClock(time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC))
+	var observed []interface{}
+	record := func(outputID string, e *entry.Entry) error {
+		require.Equal(t, DefaultOutputID, outputID)
+		require.Equal(t, clk.Now(), e.ObservedTimestamp)
+		observed = append(observed, e.Attributes["level"])
+		return nil
+	}
+	require.NoError(t, Run(cfg, []string{"info"}, clk, record, testutil.Logger(t)))
+	require.Equal(t, []interface{}{"info"}, observed)
+}
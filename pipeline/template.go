@@ -0,0 +1,385 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"text/template"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/errors"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/helper"
+)
+
+const (
+	templateOperatorType = "template"
+
+	// maxTemplateDepth limits how deeply templates may be nested in other templates
+	maxTemplateDepth = 10
+)
+
+func init() {
+	operator.Register(templateOperatorType, func() operator.Builder { return NewTemplateConfig("") })
+}
+
+// DefaultTemplateRegistry is the registry of templates used by pipelines that do not specify one.
+var DefaultTemplateRegistry = NewTemplateRegistry()
+
+// TemplateRegistry is used to track and retrieve known templates
+type TemplateRegistry struct {
+	mux       sync.RWMutex
+	templates map[string]*Template
+}
+
+// NewTemplateRegistry creates a new template registry
+func NewTemplateRegistry() *TemplateRegistry {
+	return &TemplateRegistry{
+		templates: make(map[string]*Template),
+	}
+}
+
+// Register will register a template, replacing any template with the same name.
+func (r *TemplateRegistry) Register(t *Template) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.templates[t.Name()] = t
+}
+
+// Lookup looks up a template by name. Its second return value will
+// be false if no template is registered with that name.
+func (r *TemplateRegistry) Lookup(name string) (*Template, bool) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	t, ok := r.templates[name]
+	return t, ok
+}
+
+// RegisterTemplate will register a template in the default template registry
+func RegisterTemplate(t *Template) {
+	DefaultTemplateRegistry.Register(t)
+}
+
+// TemplateParameter describes a parameter of a template
+type TemplateParameter struct {
+	Name     string
+	Default  interface{}
+	Required bool
+}
+
+// Template is a named sequence of operator configs. The sequence is defined as a
+// YAML list of operator configs, which is rendered with the template's parameters
+// using the syntax of the text/template package.
+type Template struct {
+	name       string
+	parameters map[string]TemplateParameter
+	pipeline   *template.Template
+}
+
+// NewTemplate creates a new template from a YAML list of operator configs.
+// Parameters are referenced as `{{ .name }}`, and `{{ json .name }}` may be used to quote a value.
+func NewTemplate(name, pipeline string, parameters ...TemplateParameter) (*Template, error) {
+	if name == "" {
+		return nil, errors.NewError(
+			"missing required argument `name`",
+			"ensure that the template has a name",
+		)
+	}
+
+	parsed, err := template.New(name).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"json": templateJSON}).
+		Parse(pipeline)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse template")
+	}
+
+	params := make(map[string]TemplateParameter, len(parameters))
+	for _, param := range parameters {
+		params[param.Name] = param
+	}
+
+	return &Template{
+		name:       name,
+		parameters: params,
+		pipeline:   parsed,
+	}, nil
+}
+
+// Name returns the name of the template
+func (t *Template) Name() string {
+	return t.name
+}
+
+// Render renders the template with the supplied parameters, and unmarshals the
+// operator configs it defines using the operator types of a registry.
+func (t *Template) Render(params map[string]interface{}, registry *operator.Registry) ([]operator.Config, error) {
+	values := make(map[string]interface{}, len(t.parameters))
+	for name, value := range params {
+		if _, ok := t.parameters[name]; !ok {
+			return nil, errors.NewError(
+				fmt.Sprintf("template does not have a parameter named '%s'", name),
+				"ensure that only the parameters defined by the template are set",
+				"template", t.name,
+			)
+		}
+		values[name] = value
+	}
+
+	for name, param := range t.parameters {
+		if _, ok := values[name]; ok {
+			continue
+		}
+		if param.Required {
+			return nil, errors.NewError(
+				fmt.Sprintf("missing required template parameter '%s'", name),
+				"ensure that all of the required parameters of the template are set",
+				"template", t.name,
+			)
+		}
+		values[name] = param.Default
+	}
+
+	var rendered bytes.Buffer
+	if err := t.pipeline.Execute(&rendered, values); err != nil {
+		return nil, errors.WithDetails(errors.Wrap(err, "render template"), "template", t.name)
+	}
+
+	configs, err := registry.UnmarshalConfigs(rendered.Bytes())
+	if err != nil {
+		return nil, errors.WithDetails(errors.Wrap(err, "unmarshal rendered template"), "template", t.name)
+	}
+
+	if len(configs) == 0 {
+		return nil, errors.NewError(
+			"template does not define any operators",
+			"ensure that the template renders a list of operator configs",
+			"template", t.name,
+		)
+	}
+	return configs, nil
+}
+
+func templateJSON(value interface{}) (string, error) {
+	bytes, err := json.Marshal(jsonCompatible(value))
+	return string(bytes), err
+}
+
+// jsonCompatible converts maps decoded from YAML into maps that can be marshalled to JSON
+func jsonCompatible(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = jsonCompatible(item)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			s = append(s, jsonCompatible(item))
+		}
+		return s
+	default:
+		return value
+	}
+}
+
+// NewTemplateConfig creates a new template operator config with default values
+func NewTemplateConfig(operatorID string) *TemplateConfig {
+	return &TemplateConfig{
+		WriterConfig: helper.NewWriterConfig(operatorID, templateOperatorType),
+	}
+}
+
+// TemplateConfig is the configuration of a template operator. When a pipeline is built,
+// the template operator is replaced by the operators of the named template. Entries sent
+// to the template operator are received by the first of these operators, and the last
+// of them sends entries to the outputs of the template operator.
+type TemplateConfig struct {
+	helper.WriterConfig `mapstructure:",squash" yaml:",inline"`
+	Template            string                 `mapstructure:"template"   json:"template"   yaml:"template"`
+	Parameters          map[string]interface{} `mapstructure:"parameters" json:"parameters" yaml:"parameters"`
+}
+
+// Build will fail, because template operators are expanded when a pipeline is built.
+func (c TemplateConfig) Build(_ *zap.SugaredLogger) (operator.Operator, error) {
+	return nil, errors.NewError(
+		"template operators can only be built as part of a pipeline",
+		"build the template operator with pipeline.Config",
+		"operator_id", c.ID(),
+	)
+}
+
+// expandTemplates replaces each template operator with the operators of its template.
+// Expanded operators are given the ID of the template operator as a prefix.
+func (r *TemplateRegistry) expandTemplates(configs []operator.Config, registry *operator.Registry, depth int) ([]operator.Config, error) {
+	hasTemplates := false
+	for _, cfg := range configs {
+		if _, ok := cfg.Builder.(*TemplateConfig); ok {
+			hasTemplates = true
+		}
+	}
+	if !hasTemplates {
+		return configs, nil
+	}
+
+	if depth >= maxTemplateDepth {
+		return nil, errors.NewError(
+			"templates are nested too deeply",
+			"ensure that templates do not include themselves",
+			"max_depth", fmt.Sprint(maxTemplateDepth),
+		)
+	}
+
+	dedeplucateIDs(configs)
+
+	// aliases map the ID of each template operator to the ID of its first operator
+	aliases := make(map[string]string)
+	expanded := make([]operator.Config, 0, len(configs))
+	for _, cfg := range configs {
+		templateCfg, ok := cfg.Builder.(*TemplateConfig)
+		if !ok {
+			expanded = append(expanded, cfg)
+			continue
+		}
+
+		if err := cfg.CheckUnknownFields(); err != nil {
+			return nil, err
+		}
+
+		templateOps, err := r.expandTemplate(templateCfg, registry, depth)
+		if err != nil {
+			return nil, err
+		}
+		aliases[templateCfg.ID()] = templateOps[0].ID()
+		expanded = append(expanded, templateOps...)
+	}
+
+	for _, cfg := range expanded {
+		renameOutputs(cfg.Builder, func(id string) string {
+			if alias, ok := aliases[id]; ok {
+				return alias
+			}
+			return id
+		})
+	}
+	return expanded, nil
+}
+
+// expandTemplate renders the operators of a template operator
+func (r *TemplateRegistry) expandTemplate(cfg *TemplateConfig, registry *operator.Registry, depth int) ([]operator.Config, error) {
+	t, ok := r.Lookup(cfg.Template)
+	if !ok {
+		return nil, errors.NewError(
+			fmt.Sprintf("template '%s' is not registered", cfg.Template),
+			"ensure that the `template` field refers to a registered template",
+			"operator_id", cfg.ID(),
+		)
+	}
+
+	configs, err := t.Render(cfg.Parameters, registry)
+	if err != nil {
+		return nil, errors.WithDetails(err, "operator_id", cfg.ID())
+	}
+
+	configs, err = r.expandTemplates(configs, registry, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	dedeplucateIDs(configs)
+
+	internal := make(map[string]bool, len(configs))
+	for _, opCfg := range configs {
+		internal[opCfg.ID()] = true
+	}
+	prefix := func(id string) string {
+		if internal[id] {
+			return cfg.ID() + "." + id
+		}
+		return id
+	}
+
+	for _, opCfg := range configs {
+		renameOutputs(opCfg.Builder, prefix)
+		opCfg.SetID(prefix(opCfg.ID()))
+	}
+	setDefaultOutputs(configs[len(configs)-1].Builder, cfg.OutputIDs)
+
+	return configs, nil
+}
+
+var outputIDsType = reflect.TypeOf(helper.OutputIDs{})
+
+// renameOutputs replaces each output ID in an operator config with the result of rename.
+func renameOutputs(builder operator.Builder, rename func(string) string) {
+	renameOutputsValue(reflect.ValueOf(builder), rename)
+}
+
+func renameOutputsValue(value reflect.Value, rename func(string) string) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !value.IsNil() {
+			renameOutputsValue(value.Elem(), rename)
+		}
+	case reflect.Slice:
+		if value.Type() == outputIDsType {
+			if value.CanSet() && value.Len() > 0 {
+				ids := value.Interface().(helper.OutputIDs)
+				renamed := make(helper.OutputIDs, 0, len(ids))
+				for _, id := range ids {
+					renamed = append(renamed, rename(id))
+				}
+				value.Set(reflect.ValueOf(renamed))
+			}
+			return
+		}
+		for i := 0; i < value.Len(); i++ {
+			renameOutputsValue(value.Index(i), rename)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).PkgPath == "" {
+				renameOutputsValue(value.Field(i), rename)
+			}
+		}
+	}
+}
+
+// setDefaultOutputs sets the outputs of an operator config that does not have any
+func setDefaultOutputs(builder operator.Builder, outputIDs helper.OutputIDs) {
+	if len(outputIDs) == 0 {
+		return
+	}
+
+	value := reflect.ValueOf(builder)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return
+	}
+
+	field := value.FieldByName("OutputIDs")
+	if !field.IsValid() || field.Type() != outputIDsType || !field.CanSet() || field.Len() > 0 {
+		return
+	}
+	field.Set(reflect.ValueOf(append(helper.OutputIDs{}, outputIDs...)))
+}
@@ -0,0 +1,291 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/open-telemetry/opentelemetry-log-collection/entry"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/add"
+	"github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/noop"
+	_ "github.com/open-telemetry/opentelemetry-log-collection/operator/transformer/router"
+	"github.com/open-telemetry/opentelemetry-log-collection/testutil"
+)
+
+const serviceTemplate = `
+- type: add
+  field: attributes.service
+  value: {{ json .service }}
+- type: router
+  routes:
+    - expr: 'body == "skip"'
+      output: {{ .skip_output }}
+  default: noop
+- type: noop
+`
+
+func newTemplateRegistry(t *testing.T) *TemplateRegistry {
+	registry := NewTemplateRegistry()
+
+	service, err := NewTemplate("service", serviceTemplate,
+		TemplateParameter{Name: "service", Required: true},
+		TemplateParameter{Name: "skip_output", Default: "noop"},
+	)
+	require.NoError(t, err)
+	registry.Register(service)
+
+	recursive, err := NewTemplate("recursive", "- type: template\n  template: recursive\n")
+	require.NoError(t, err)
+	registry.Register(recursive)
+
+	return registry
+}
+
+func unmarshalOperators(t *testing.T, raw string) []operator.Config {
+	var configs []operator.Config
+	require.NoError(t, yaml.Unmarshal([]byte(raw), &configs))
+	return configs
+}
+
+func TestBuildPipelineTemplate(t *testing.T) {
+	fakeOutput := testutil.NewFakeOutput(t)
+	cfg := Config{
+		Operators: unmarshalOperators(t, `
+- type: noop
+  output: a
+- id: a
+  type: template
+  template: service
+  parameters:
+    service: service-a
+- id: b
+  type: template
+  template: service
+  parameters:
+    service: service-b
+  output: end
+- id: end
+  type: noop
+`),
+		DefaultOutput: fakeOutput,
+		Templates:     newTemplateRegistry(t),
+	}
+
+	pipe, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	ids := make([]string, 0)
+	for _, op := range pipe.Operators() {
+		ids = append(ids, op.ID())
+	}
+	require.ElementsMatch(t, []string{"noop", "a.add", "a.router", "a.noop", "b.add", "b.router", "b.noop", "end", "fake"}, ids)
+
+	dotGraph, err := pipe.Render()
+	require.NoError(t, err)
+	for _, edge := range []string{
+		`noop -> "a.add";`,
+		`"a.add" -> "a.router";`,
+		`"a.router" -> "a.noop";`,
+		`"a.noop" -> "b.add";`,
+		`"b.noop" -> end;`,
+		`end -> fake;`,
+	} {
+		require.Contains(t, string(dotGraph), edge)
+	}
+
+	require.NoError(t, pipe.Start(testutil.NewUnscopedMockPersister()))
+	defer func() {
+		require.NoError(t, pipe.Stop(context.Background()))
+	}()
+
+	input := findOperator(t, pipe, "noop")
+	require.NoError(t, input.Process(context.Background(), entry.New()))
+	select {
+	case e := <-fakeOutput.Received:
+		// The last template to add the attribute wins
+		require.Equal(t, "service-b", e.Attributes["service"])
+	case <-time.After(time.Second):
+		require.FailNow(t, "timed out waiting for entry")
+	}
+}
+
+func TestBuildPipelineTemplateDefaultIDs(t *testing.T) {
+	cfg := Config{
+		Operators: unmarshalOperators(t, `
+- type: template
+  template: service
+  parameters:
+    service: service-a
+- type: template
+  template: service
+  parameters:
+    service: service-b
+    skip_output: external
+- id: external
+  type: noop
+`),
+		Templates: newTemplateRegistry(t),
+	}
+
+	pipe, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+
+	ids := make([]string, 0)
+	for _, op := range pipe.Operators() {
+		ids = append(ids, op.ID())
+	}
+	require.ElementsMatch(t, []string{
+		"template.add", "template.router", "template.noop",
+		"template1.add", "template1.router", "template1.noop",
+		"external",
+	}, ids)
+
+	// Outputs that do not refer to operators of the template are unchanged
+	router := findOperator(t, pipe, "template1.router")
+	require.Equal(t, []string{"external", "template1.noop"}, router.GetOutputIDs())
+}
+
+func TestBuildPipelineTemplateInvalid(t *testing.T) {
+	cases := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{
+			"Unregistered",
+			"- type: template\n  template: missing\n",
+			"template 'missing' is not registered",
+		},
+		{
+			"MissingParameter",
+			"- type: template\n  template: service\n",
+			"missing required template parameter 'service'",
+		},
+		{
+			"UnknownParameter",
+			"- type: template\n  template: service\n  parameters:\n    service: a\n    other: b\n",
+			"template does not have a parameter named 'other'",
+		},
+		{
+			"UnknownField",
+			"- type: template\n  template: service\n  paramters:\n    service: a\n",
+			"operator config contains unknown fields",
+		},
+		{
+			"Recursive",
+			"- type: template\n  template: recursive\n",
+			"templates are nested too deeply",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{
+				Operators: unmarshalOperators(t, tc.raw),
+				Templates: newTemplateRegistry(t),
+			}
+			_, err := cfg.Build(testutil.Logger(t))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expected)
+		})
+	}
+}
+
+func TestBuildPipelineTemplateRegistry(t *testing.T) {
+	registry := operator.DefaultRegistry.Clone()
+	registry.RegisterAlias("tag", "add")
+
+	templates := NewTemplateRegistry()
+	tagged, err := NewTemplate("tagged", "- type: tag\n  field: attributes.tagged\n  value: 'true'\n")
+	require.NoError(t, err)
+	templates.Register(tagged)
+
+	cfg := Config{
+		Operators: unmarshalOperators(t, "- id: t\n  type: template\n  template: tagged\n"),
+		Registry:  registry,
+		Templates: templates,
+	}
+	pipe, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.Equal(t, "add", findOperator(t, pipe, "t.add").Type())
+
+	// Operators of templates are restricted by the registry
+	registry.Deny("add")
+	_, err = cfg.Build(testutil.Logger(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "operator type 'add' is not allowed")
+}
+
+func TestTemplateRenderInvalid(t *testing.T) {
+	_, err := NewTemplate("", "- type: noop\n")
+	require.Error(t, err)
+
+	_, err = NewTemplate("invalid", "- type: {{ .type")
+	require.Error(t, err)
+
+	empty, err := NewTemplate("empty", "")
+	require.NoError(t, err)
+	_, err = empty.Render(nil, operator.DefaultRegistry)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "template does not define any operators")
+
+	undefined, err := NewTemplate("undefined", "- type: {{ .type }}\n")
+	require.NoError(t, err)
+	_, err = undefined.Render(nil, operator.DefaultRegistry)
+	require.Error(t, err)
+}
+
+func TestTemplateConfigBuild(t *testing.T) {
+	_, err := NewTemplateConfig("template").Build(testutil.Logger(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can only be built as part of a pipeline")
+}
+
+func TestReloadTemplateParameters(t *testing.T) {
+	registry := newTemplateRegistry(t)
+	newConfig := func(service string) Config {
+		return Config{
+			Operators: []operator.Config{
+				{Builder: noop.NewNoopOperatorConfig("noop")},
+				{Builder: &TemplateConfig{
+					WriterConfig: NewTemplateConfig("a").WriterConfig,
+					Template:     "service",
+					Parameters:   map[string]interface{}{"service": service},
+				}},
+			},
+			Templates: registry,
+		}
+	}
+
+	pipe, err := newConfig("service-a").Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.NoError(t, pipe.Start(testutil.NewUnscopedMockPersister()))
+	defer func() {
+		require.NoError(t, pipe.Stop(context.Background()))
+	}()
+
+	router := findOperator(t, pipe, "a.router")
+	require.NoError(t, pipe.Reload(context.Background(), newConfig("service-b"), testutil.Logger(t)))
+
+	// Only the changed operator, and those upstream of it, are replaced
+	require.Same(t, router, findOperator(t, pipe, "a.router"))
+	require.Len(t, pipe.Operators(), 4)
+}
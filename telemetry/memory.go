@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryProvider is a provider that keeps all measurements in memory.
+// It is intended to be used in tests.
+type MemoryProvider struct {
+	mux        sync.Mutex
+	counters   map[string]map[string]int64
+	histograms map[string]map[string][]float64
+}
+
+// NewMemoryProvider creates a new in-memory provider.
+func NewMemoryProvider() *MemoryProvider {
+	return &MemoryProvider{
+		counters:   make(map[string]map[string]int64),
+		histograms: make(map[string]map[string][]float64),
+	}
+}
+
+// Counter returns a counter that records to memory.
+func (p *MemoryProvider) Counter(name, _ string) Counter {
+	return &memoryCounter{provider: p, name: name}
+}
+
+// Histogram returns a histogram that records to memory.
+func (p *MemoryProvider) Histogram(name, _, _ string) Histogram {
+	return &memoryHistogram{provider: p, name: name}
+}
+
+// CounterValue returns the value of a counter for a set of attributes.
+func (p *MemoryProvider) CounterValue(name string, attrs ...Attribute) int64 {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return p.counters[name][seriesKey(attrs)]
+}
+
+// HistogramValues returns the values recorded by a histogram for a set of attributes.
+func (p *MemoryProvider) HistogramValues(name string, attrs ...Attribute) []float64 {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	values := p.histograms[name][seriesKey(attrs)]
+	return append([]float64(nil), values...)
+}
+
+type memoryCounter struct {
+	provider *MemoryProvider
+	name     string
+}
+
+func (c *memoryCounter) Add(_ context.Context, value int64, attrs ...Attribute) {
+	c.provider.mux.Lock()
+	defer c.provider.mux.Unlock()
+
+	series, ok := c.provider.counters[c.name]
+	if !ok {
+		series = make(map[string]int64)
+		c.provider.counters[c.name] = series
+	}
+	series[seriesKey(attrs)] += value
+}
+
+type memoryHistogram struct {
+	provider *MemoryProvider
+	name     string
+}
+
+func (h *memoryHistogram) Record(_ context.Context, value float64, attrs ...Attribute) {
+	h.provider.mux.Lock()
+	defer h.provider.mux.Unlock()
+
+	series, ok := h.provider.histograms[h.name]
+	if !ok {
+		series = make(map[string][]float64)
+		h.provider.histograms[h.name] = series
+	}
+	key := seriesKey(attrs)
+	series[key] = append(series[key], value)
+}
+
+// seriesKey creates a key that is independent of the order of the attributes
+func seriesKey(attrs []Attribute) string {
+	pairs := make([]string, 0, len(attrs))
+	for _, attr := range attrs {
+		pairs = append(pairs, attr.Key+"="+attr.Value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCounter(t *testing.T) {
+	provider := NewMemoryProvider()
+	counter := provider.Counter("test", "")
+
+	ctx := context.Background()
+	counter.Add(ctx, 1, String("a", "1"), String("b", "2"))
+	counter.Add(ctx, 2, String("b", "2"), String("a", "1"))
+	counter.Add(ctx, 5, String("a", "other"))
+
+	require.Equal(t, int64(3), provider.CounterValue("test", String("a", "1"), String("b", "2")))
+	require.Equal(t, int64(5), provider.CounterValue("test", String("a", "other")))
+	require.Equal(t, int64(0), provider.CounterValue("test"))
+	require.Equal(t, int64(0), provider.CounterValue("missing"))
+}
+
+func TestMemoryHistogram(t *testing.T) {
+	provider := NewMemoryProvider()
+	histogram := provider.Histogram("test", "", Milliseconds)
+
+	ctx := context.Background()
+	histogram.Record(ctx, 1.5, String("a", "1"))
+	histogram.Record(ctx, 2.5, String("a", "1"))
+
+	require.Equal(t, []float64{1.5, 2.5}, provider.HistogramValues("test", String("a", "1")))
+	require.Empty(t, provider.HistogramValues("test", String("a", "2")))
+}
+
+func TestNopProvider(t *testing.T) {
+	provider := NewNopProvider()
+	ctx := context.Background()
+	require.NotPanics(t, func() {
+		provider.Counter("test", "").Add(ctx, 1, String("a", "1"))
+		provider.Histogram("test", "", Milliseconds).Record(ctx, 1, String("a", "1"))
+	})
+}
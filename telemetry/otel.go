@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+	"go.uber.org/zap"
+)
+
+// NewOTelProvider creates a provider that records measurements with an OpenTelemetry meter.
+// Instruments that can not be created are replaced by instruments that discard all measurements.
+func NewOTelProvider(meter metric.Meter, logger *zap.SugaredLogger) Provider {
+	return &otelProvider{meter: meter, logger: logger}
+}
+
+type otelProvider struct {
+	meter  metric.Meter
+	logger *zap.SugaredLogger
+}
+
+func (p *otelProvider) Counter(name, description string) Counter {
+	counter, err := p.meter.SyncInt64().Counter(name, instrument.WithDescription(description))
+	if err != nil {
+		p.logger.Errorw("Failed to create counter", "name", name, zap.Error(err))
+		return nopInstrument{}
+	}
+	return &otelCounter{counter: counter}
+}
+
+func (p *otelProvider) Histogram(name, description, u string) Histogram {
+	histogram, err := p.meter.SyncFloat64().Histogram(name,
+		instrument.WithDescription(description),
+		instrument.WithUnit(unit.Unit(u)),
+	)
+	if err != nil {
+		p.logger.Errorw("Failed to create histogram", "name", name, zap.Error(err))
+		return nopInstrument{}
+	}
+	return &otelHistogram{histogram: histogram}
+}
+
+type otelCounter struct {
+	counter syncint64.Counter
+}
+
+func (c *otelCounter) Add(ctx context.Context, value int64, attrs ...Attribute) {
+	c.counter.Add(ctx, value, toKeyValues(attrs)...)
+}
+
+type otelHistogram struct {
+	histogram syncfloat64.Histogram
+}
+
+func (h *otelHistogram) Record(ctx context.Context, value float64, attrs ...Attribute) {
+	h.histogram.Record(ctx, value, toKeyValues(attrs)...)
+}
+
+func toKeyValues(attrs []Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		kvs = append(kvs, attribute.String(attr.Key, attr.Value))
+	}
+	return kvs
+}
@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/nonrecording"
+	"go.uber.org/zap/zaptest"
+)
+
+// fakeMeter records the measurements of its synchronous instruments
+type fakeMeter struct {
+	metric.Meter
+	failing bool
+	attrs   map[string][]attribute.KeyValue
+	values  map[string]float64
+}
+
+func newFakeMeter() *fakeMeter {
+	return &fakeMeter{
+		Meter:  nonrecording.NewNoopMeter(),
+		attrs:  make(map[string][]attribute.KeyValue),
+		values: make(map[string]float64),
+	}
+}
+
+func (m *fakeMeter) SyncInt64() syncint64.InstrumentProvider {
+	return &fakeInt64Provider{InstrumentProvider: m.Meter.SyncInt64(), meter: m}
+}
+
+func (m *fakeMeter) SyncFloat64() syncfloat64.InstrumentProvider {
+	return &fakeFloat64Provider{InstrumentProvider: m.Meter.SyncFloat64(), meter: m}
+}
+
+type fakeInt64Provider struct {
+	syncint64.InstrumentProvider
+	meter *fakeMeter
+}
+
+func (p *fakeInt64Provider) Counter(name string, _ ...instrument.Option) (syncint64.Counter, error) {
+	if p.meter.failing {
+		return nil, fmt.Errorf("failed to create %s", name)
+	}
+	return &fakeInstrument{meter: p.meter, name: name}, nil
+}
+
+type fakeFloat64Provider struct {
+	syncfloat64.InstrumentProvider
+	meter *fakeMeter
+}
+
+func (p *fakeFloat64Provider) Histogram(name string, _ ...instrument.Option) (syncfloat64.Histogram, error) {
+	if p.meter.failing {
+		return nil, fmt.Errorf("failed to create %s", name)
+	}
+	return &fakeInstrument{meter: p.meter, name: name}, nil
+}
+
+type fakeInstrument struct {
+	instrument.Synchronous
+	meter *fakeMeter
+	name  string
+}
+
+func (i *fakeInstrument) Add(_ context.Context, value int64, attrs ...attribute.KeyValue) {
+	i.meter.values[i.name] += float64(value)
+	i.meter.attrs[i.name] = attrs
+}
+
+func (i *fakeInstrument) Record(_ context.Context, value float64, attrs ...attribute.KeyValue) {
+	i.meter.values[i.name] += value
+	i.meter.attrs[i.name] = attrs
+}
+
+func TestOTelProvider(t *testing.T) {
+	meter := newFakeMeter()
+	provider := NewOTelProvider(meter, zaptest.NewLogger(t).Sugar())
+
+	ctx := context.Background()
+	provider.Counter("counter", "").Add(ctx, 2, String("operator_id", "test"))
+	provider.Histogram("histogram", "", Milliseconds).Record(ctx, 1.5, String("operator_id", "test"))
+
+	require.Equal(t, float64(2), meter.values["counter"])
+	require.Equal(t, float64(1.5), meter.values["histogram"])
+	require.Equal(t, []attribute.KeyValue{attribute.String("operator_id", "test")}, meter.attrs["counter"])
+}
+
+func TestOTelProviderInstrumentError(t *testing.T) {
+	meter := newFakeMeter()
+	meter.failing = true
+	provider := NewOTelProvider(meter, zaptest.NewLogger(t).Sugar())
+
+	ctx := context.Background()
+	require.NotPanics(t, func() {
+		provider.Counter("counter", "").Add(ctx, 1)
+		provider.Histogram("histogram", "", Milliseconds).Record(ctx, 1)
+	})
+	require.Empty(t, meter.values)
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package telemetry defines the interface used by operators to record metrics.
+package telemetry
+
+import (
+	"context"
+)
+
+// Attribute is a key value pair that identifies a metric series.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// String creates a new attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Provider creates the instruments used to record metrics.
+type Provider interface {
+	// Counter returns a monotonic counter with the given name.
+	Counter(name, description string) Counter
+	// Histogram returns a histogram with the given name and unit.
+	Histogram(name, description, unit string) Histogram
+}
+
+// Counter records monotonically increasing values.
+type Counter interface {
+	Add(ctx context.Context, value int64, attrs ...Attribute)
+}
+
+// Histogram records a distribution of values.
+type Histogram interface {
+	Record(ctx context.Context, value float64, attrs ...Attribute)
+}
+
+// Milliseconds is the unit used for durations.
+const Milliseconds = "ms"
+
+// NewNopProvider creates a provider that discards all measurements.
+func NewNopProvider() Provider {
+	return nopProvider{}
+}
+
+type nopProvider struct{}
+
+func (nopProvider) Counter(string, string) Counter             { return nopInstrument{} }
+func (nopProvider) Histogram(string, string, string) Histogram { return nopInstrument{} }
+
+type nopInstrument struct{}
+
+func (nopInstrument) Add(context.Context, int64, ...Attribute)      {}
+func (nopInstrument) Record(context.Context, float64, ...Attribute) {}
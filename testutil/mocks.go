@@ -31,6 +31,7 @@ import (
 func NewMockOperator(id string) *Operator {
 	mockOutput := &Operator{}
 	mockOutput.On("ID").Return(id)
+	mockOutput.On("Type").Return("mock")
 	mockOutput.On("CanProcess").Return(true)
 	mockOutput.On("CanOutput").Return(true)
 	return mockOutput
@@ -83,8 +84,9 @@ func (f *FakeOutput) Stop() error { return nil }
 // Type always return `fake_output` for a fake output
 func (f *FakeOutput) Type() string { return "fake_output" }
 
-// Process will place all incoming entries on the Received channel of a fake output
+// Process will acknowledge and place all incoming entries on the Received channel of a fake output
 func (f *FakeOutput) Process(ctx context.Context, entry *entry.Entry) error {
+	entry.Ack()
 	f.Received <- entry
 	return nil
 }
@@ -3,6 +3,8 @@
 package testutil
 
 import (
+	context "context"
+
 	operator "github.com/open-telemetry/opentelemetry-log-collection/operator"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -65,13 +67,13 @@ func (_m *Pipeline) Start(persister operator.Persister) error {
 	return r0
 }
 
-// Stop provides a mock function with given fields:
-func (_m *Pipeline) Stop() error {
-	ret := _m.Called()
+// Stop provides a mock function with given fields: ctx
+func (_m *Pipeline) Stop(ctx context.Context) error {
+	ret := _m.Called(ctx)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func() error); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
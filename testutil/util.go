@@ -18,6 +18,7 @@ import (
 	context "context"
 	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -76,6 +77,19 @@ func (p *mockPersister) Delete(ctx context.Context, k string) error {
 	return nil
 }
 
+func (p *mockPersister) Keys(ctx context.Context, prefix string) ([]string, error) {
+	p.dataMux.Lock()
+	defer p.dataMux.Unlock()
+	keys := make([]string, 0, len(p.data))
+	for k := range p.data {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
 // NewUnscopedMockPersister will return a new persister for testing
 func NewUnscopedMockPersister() operator.Persister {
 	data := make(map[string][]byte)